@@ -0,0 +1,58 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// documentationCache records the hash of everything each chart's documentation was last generated from, keyed by chart
+// directory, so that charts which haven't changed since can be skipped
+type documentationCache struct {
+	mutex  sync.Mutex
+	hashes map[string]string
+}
+
+func loadDocumentationCache(cacheFile string) (*documentationCache, error) {
+	cache := &documentationCache{hashes: make(map[string]string)}
+
+	contents, err := ioutil.ReadFile(cacheFile)
+	if os.IsNotExist(err) {
+		log.Debugf("No cache file found at %s, generating documentation for all charts", cacheFile)
+		return cache, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(contents, &cache.hashes); err != nil {
+		return nil, err
+	}
+
+	return cache, nil
+}
+
+func (c *documentationCache) unchanged(chartDirectory string, hash string) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.hashes[chartDirectory] == hash
+}
+
+func (c *documentationCache) update(chartDirectory string, hash string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.hashes[chartDirectory] = hash
+}
+
+func (c *documentationCache) save(cacheFile string) error {
+	contents, err := json.MarshalIndent(c.hashes, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(cacheFile, append(contents, '\n'), 0644)
+}
@@ -5,24 +5,75 @@ import (
 	"os"
 	"strings"
 
+	"github.com/norwoodj/helm-docs/pkg/document"
+	"github.com/norwoodj/helm-docs/pkg/settings"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 )
 
-var version string
+// The configuration file read from the directory helm-docs is run from, if present and no other file is given
+const defaultConfigFile = ".helm-docs.yaml"
 
-func possibleLogLevels() []string {
-	levels := make([]string, 0)
+// readConfigFile reads settings from a yaml file with keys named after the command line options, e.g. "template-file".
+// Options given on the command line or in the environment take precedence over those set in the file
+func readConfigFile() error {
+	configFile := viper.GetString("config-file")
+	if configFile == "" {
+		if _, err := os.Stat(defaultConfigFile); os.IsNotExist(err) {
+			return nil
+		}
 
-	for _, l := range log.AllLevels {
-		levels = append(levels, l.String())
+		configFile = defaultConfigFile
 	}
 
-	return levels
+	viper.SetConfigFile(configFile)
+	return viper.ReadInConfig()
 }
 
-func initializeCli() {
+// The prefix of the environment variables setting each option, e.g. HELM_DOCS_TEMPLATE_FILE for --template-file
+const environmentVariablePrefix = "HELM_DOCS_"
+
+func environmentVariableName(flagName string) string {
+	return environmentVariablePrefix + strings.ToUpper(strings.ReplaceAll(flagName, "-", "_"))
+}
+
+// setFlagsFromEnvironment sets each option not given on the command line from its environment variable, if set. Values
+// are parsed just as they would be on the command line, so that lists can be given comma separated, e.g.
+// HELM_DOCS_TEMPLATE_FILE=README.md.gotmpl,_templates.gotmpl, and invalid values are reported as such
+func setFlagsFromEnvironment(flags *pflag.FlagSet) error {
+	var err error
+
+	flags.VisitAll(func(flag *pflag.Flag) {
+		if err != nil || flag.Changed {
+			return
+		}
+
+		value, ok := os.LookupEnv(environmentVariableName(flag.Name))
+		if !ok {
+			return
+		}
+
+		if setErr := flags.Set(flag.Name, value); setErr != nil {
+			err = fmt.Errorf("invalid value %q of %s: %s", value, environmentVariableName(flag.Name), setErr)
+		}
+	})
+
+	return err
+}
+
+func initializeCli(command *cobra.Command) {
+	if err := setFlagsFromEnvironment(command.Flags()); err != nil {
+		log.Errorf("Failed to read options from the environment: %s", err)
+		os.Exit(1)
+	}
+
+	if err := readConfigFile(); err != nil {
+		log.Errorf("Failed to read config file: %s", err)
+		os.Exit(1)
+	}
+
 	logLevelName := viper.GetString("log-level")
 	logLevel, err := log.ParseLevel(logLevelName)
 	if err != nil {
@@ -30,29 +81,133 @@ func initializeCli() {
 		os.Exit(1)
 	}
 
-	log.SetFormatter(&log.TextFormatter{FullTimestamp: true})
+	logFormatter, err := newLogFormatter(viper.GetString("log-format"))
+	if err != nil {
+		log.Error(err)
+		os.Exit(1)
+	}
+
+	log.SetFormatter(logFormatter)
 	log.SetLevel(logLevel)
 }
 
+// newLogFormatter returns the formatter of logs of a log format, text for people to read or json for CI systems to parse
+func newLogFormatter(logFormat string) (log.Formatter, error) {
+	switch logFormat {
+	case "text":
+		return &log.TextFormatter{FullTimestamp: true}, nil
+	case "json":
+		return &log.JSONFormatter{}, nil
+	default:
+		return nil, fmt.Errorf("invalid log format %s, must be one of (text, json)", logFormat)
+	}
+}
+
 func newHelmDocsCommand(run func(cmd *cobra.Command, args []string)) (*cobra.Command, error) {
 	command := &cobra.Command{
-		Use:     "helm-docs",
+		Use:     "helm-docs [chart-search-root...]",
 		Short:   "helm-docs automatically generates markdown documentation for helm charts from requirements and values files",
 		Version: version,
+		Args:    cobra.ArbitraryArgs,
 		Run:     run,
 	}
 
-	logLevelUsage := fmt.Sprintf("Level of logs that should printed, one of (%s)", strings.Join(possibleLogLevels(), ", "))
-	command.PersistentFlags().BoolP("dry-run", "d", false, "don't actually render any markdown files just print to stdout passed")
-	command.PersistentFlags().StringP("ignore-file", "i", ".helmdocsignore", "The filename to use as an ignore file to exclude chart directories")
-	command.PersistentFlags().StringP("log-level", "l", "info", logLevelUsage)
-	command.PersistentFlags().StringP("output-file", "o", "README.md", "markdown file path relative to each chart directory to which rendered documentation will be written")
-	command.PersistentFlags().StringP("template-file", "t", "README.md.gotmpl", "gotemplate file path relative to each chart directory from which documentation will be generated")
+	settings.AddFlags(command.PersistentFlags())
+	command.PersistentFlags().Lookup("output-format").Usage = fmt.Sprintf("format in which documentation is rendered, one of (%s); output files named .md take the format's extension instead", strings.Join(document.RendererNames(), ", "))
 
 	viper.AutomaticEnv()
-	viper.SetEnvPrefix("HELM_DOCS")
+	viper.SetEnvPrefix(strings.TrimSuffix(environmentVariablePrefix, "_"))
 	viper.SetEnvKeyReplacer(strings.NewReplacer("-", "_"))
 	err := viper.BindPFlags(command.PersistentFlags())
 
 	return command, err
 }
+
+func newServeCommand(run func(cmd *cobra.Command, args []string)) (*cobra.Command, error) {
+	command := &cobra.Command{
+		Use:   "serve",
+		Short: "serves html previews of the documentation of each chart, rendered in memory and reloaded as charts change",
+		Run:   run,
+	}
+
+	command.Flags().String("address", "localhost:8080", "address on which to serve the previews")
+	err := viper.BindPFlags(command.Flags())
+
+	return command, err
+}
+
+func newCompletionCommand(run func(cmd *cobra.Command, args []string)) *cobra.Command {
+	return &cobra.Command{
+		Use:       "completion (bash|zsh|fish|powershell)",
+		Short:     "prints a script completing helm-docs' commands and options in the given shell, including the names of charts for --chart",
+		ValidArgs: []string{"bash", "zsh", "fish", "powershell"},
+		Args:      cobra.ExactValidArgs(1),
+		Run:       run,
+	}
+}
+
+func newPreCommitCommand(run func(cmd *cobra.Command, args []string)) (*cobra.Command, error) {
+	command := &cobra.Command{
+		Use:   "pre-commit [changed-file...]",
+		Short: "regenerates the documentation of the charts containing the changed files given, failing if any was updated unless staging it",
+		Args:  cobra.ArbitraryArgs,
+		Run:   run,
+	}
+
+	command.Flags().Bool("stage", false, "stage updated documentation with git add rather than failing")
+	err := viper.BindPFlags(command.Flags())
+
+	return command, err
+}
+
+func newLintCommand(run func(cmd *cobra.Command, args []string)) *cobra.Command {
+	return &cobra.Command{
+		Use:   "lint [chart-search-root...]",
+		Short: "checks the values files of the charts found for malformed description comments, orphaned annotations, descriptions of undefined keys and duplicate keys",
+		Args:  cobra.ArbitraryArgs,
+		Run:   run,
+	}
+}
+
+func newHelmfileCommand(run func(cmd *cobra.Command, args []string)) (*cobra.Command, error) {
+	command := &cobra.Command{
+		Use:   "helmfile [helmfile...]",
+		Short: "generates a markdown document for each release declared in helmfiles, defaulting to ./helmfile.yaml, with the chart it installs and the values it overrides",
+		Args:  cobra.ArbitraryArgs,
+		Run:   run,
+	}
+
+	command.Flags().String("helmfile-output-dir", "docs", "directory to which the document of each release is written, named after the release")
+	command.Flags().String("helmfile-template-file", "helmfile.md.gotmpl", "gotemplate file path, relative to the directory helm-docs is run from, from which the document of each release will be generated")
+	err := viper.BindPFlags(command.Flags())
+
+	return command, err
+}
+
+func newVersionCommand(run func(cmd *cobra.Command, args []string)) (*cobra.Command, error) {
+	command := &cobra.Command{
+		Use:   "version",
+		Short: "prints the version of helm-docs, and the commit, date and go version it was built from and with",
+		Args:  cobra.NoArgs,
+		Run:   run,
+	}
+
+	command.Flags().Bool("short", false, "print only the version, e.g. for scripts to compare")
+	err := viper.BindPFlags(command.Flags())
+
+	return command, err
+}
+
+func newInitCommand(run func(cmd *cobra.Command, args []string)) (*cobra.Command, error) {
+	command := &cobra.Command{
+		Use:   "init [chart-dir]",
+		Short: "writes a starter template file to the chart directory, defaulting to the current one, with all of the built in sections to customize",
+		Args:  cobra.MaximumNArgs(1),
+		Run:   run,
+	}
+
+	command.Flags().Bool("force", false, "overwrite the template file if it already exists")
+	err := viper.BindPFlags(command.Flags())
+
+	return command, err
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"os"
+
+	"github.com/norwoodj/helm-docs/pkg/helm"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+func printCompletion(command *cobra.Command, args []string) {
+	var err error
+
+	switch args[0] {
+	case "bash":
+		err = command.Root().GenBashCompletion(os.Stdout)
+	case "zsh":
+		err = command.Root().GenZshCompletion(os.Stdout)
+	case "fish":
+		err = command.Root().GenFishCompletion(os.Stdout, true)
+	case "powershell":
+		err = command.Root().GenPowerShellCompletion(os.Stdout)
+	}
+
+	if err != nil {
+		log.Errorf("Failed to generate %s completion: %s", args[0], err)
+		os.Exit(1)
+	}
+}
+
+// completeChartNames completes the --chart option with the names of the charts found from the directory helm-docs is
+// run in. The shell shows anything logged while completing, so errors just leave the names uncompleted
+func completeChartNames(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+	log.SetLevel(log.PanicLevel)
+
+	chartNames, err := helm.FindChartNames()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	return chartNames, cobra.ShellCompDirectiveNoFileComp
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"os"
+	"strings"
+
+	"github.com/norwoodj/helm-docs/pkg/document"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// documentHelmfiles generates a document for each release declared in the helmfiles given, so that whole deployments
+// are documented rather than only the charts they install
+func documentHelmfiles(command *cobra.Command, args []string) {
+	initializeCli(command)
+
+	helmfiles := args
+	if len(helmfiles) == 0 {
+		helmfiles = []string{"helmfile.yaml"}
+	}
+
+	failedHelmfiles := make([]string, 0)
+	for _, h := range helmfiles {
+		if err := document.PrintHelmfileDocumentation(h, viper.GetBool("dry-run")); err != nil {
+			failedHelmfiles = append(failedHelmfiles, h)
+		}
+	}
+
+	if len(failedHelmfiles) > 0 {
+		log.Errorf("Documentation failed or is out of date for helmfiles [%s]", strings.Join(failedHelmfiles, ", "))
+		os.Exit(1)
+	}
+}
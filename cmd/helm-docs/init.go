@@ -0,0 +1,50 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/norwoodj/helm-docs/pkg/document"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// initTemplateFile writes a starter template file, containing all of the built in sections, to a chart directory so
+// that it can be customized from there
+func initTemplateFile(command *cobra.Command, args []string) {
+	initializeCli(command)
+
+	chartDirectory := "."
+	if len(args) > 0 {
+		chartDirectory = args[0]
+	}
+
+	if _, err := os.Stat(filepath.Join(chartDirectory, "Chart.yaml")); err != nil {
+		log.Errorf("Did not find a chart in %s: %s", chartDirectory, err)
+		os.Exit(1)
+	}
+
+	templateFile := "README.md.gotmpl"
+	if templateFiles := viper.GetStringSlice("template-file"); len(templateFiles) > 0 {
+		templateFile = templateFiles[0]
+	}
+
+	templatePath := templateFile
+	if !filepath.IsAbs(templateFile) {
+		templatePath = filepath.Join(chartDirectory, templateFile)
+	}
+
+	if _, err := os.Stat(templatePath); err == nil && !viper.GetBool("force") {
+		log.Errorf("Template file %s already exists, pass --force to overwrite it", templatePath)
+		os.Exit(1)
+	}
+
+	if err := ioutil.WriteFile(templatePath, []byte(document.StarterDocumentationTemplate), 0644); err != nil {
+		log.Errorf("Error writing template file %s: %s", templatePath, err)
+		os.Exit(1)
+	}
+
+	log.Infof("Wrote template file %s", templatePath)
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/norwoodj/helm-docs/pkg/helm"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// lintCharts checks the values files of the charts found for comments helm-docs can't make sense of and for duplicate
+// keys, printing each finding with its file and line, and failing if there are any
+func lintCharts(command *cobra.Command, args []string) {
+	initializeCli(command)
+
+	if len(args) > 0 {
+		viper.Set("chart-search-root", args)
+	}
+
+	chartDirs, err := helm.FindChartDirectories()
+	if err != nil {
+		log.Errorf("Error finding chart directories: %s", err)
+		os.Exit(1)
+	}
+
+	findingCount := 0
+	for _, c := range chartDirs {
+		findings, err := helm.LintChartValuesFiles(c)
+		if err != nil {
+			log.Errorf("Error linting values files of chart %s: %s", c, err)
+			os.Exit(1)
+		}
+
+		for _, f := range findings {
+			fmt.Println(f)
+		}
+
+		findingCount += len(findings)
+	}
+
+	if findingCount > 0 {
+		log.Errorf("Found %d problems in the values files of charts", findingCount)
+		os.Exit(1)
+	}
+}
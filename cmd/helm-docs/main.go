@@ -1,7 +1,10 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"os"
+	"sort"
 	"strings"
 	"sync"
 
@@ -12,44 +15,303 @@ import (
 	"github.com/spf13/viper"
 )
 
-func retrieveInfoAndPrintDocumentation(chartDirectory string, waitGroup *sync.WaitGroup, dryRun bool) {
+// chartFailures collects the charts which failed a check during the run, from which the exit code is determined. A
+// chart failing several checks is collected once
+type chartFailures struct {
+	mutex  sync.Mutex
+	charts []string
+}
+
+func (f *chartFailures) add(chartDirectory string) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	for _, c := range f.charts {
+		if c == chartDirectory {
+			return
+		}
+	}
+
+	f.charts = append(f.charts, chartDirectory)
+}
+
+// chartContext returns the context a chart is documented in, which times out after the chart timeout, if one is set
+func chartContext() (context.Context, context.CancelFunc) {
+	if timeout := viper.GetDuration("timeout"); timeout > 0 {
+		return context.WithTimeout(context.Background(), timeout)
+	}
+
+	return context.WithCancel(context.Background())
+}
+
+// isTimeout returns whether an error is from giving up on a chart at its timeout, which always fails the run, as
+// documentation left stale by a chart too slow to document would otherwise go unnoticed
+func isTimeout(err error) bool {
+	return errors.Is(err, context.DeadlineExceeded)
+}
+
+func checkDescriptionCoverage(chartDocumentationInfo helm.ChartDocumentationInfo, failures *chartFailures) {
+	coverageThreshold := viper.GetFloat64("coverage-threshold")
+	if viper.GetBool("fail-on-missing-descriptions") {
+		coverageThreshold = 100
+	}
+
+	if coverageThreshold <= 0 {
+		return
+	}
+
+	coverage, undocumentedKeys, err := document.ValuesDescriptionCoverage(chartDocumentationInfo)
+	if err != nil {
+		log.Warnf("Error computing values description coverage for chart %s: %s", chartDocumentationInfo.ChartDirectory, err)
+		failures.add(chartDocumentationInfo.ChartDirectory)
+		return
+	}
+
+	if coverage < coverageThreshold {
+		log.Errorf(
+			"Values description coverage of chart %s is %.1f%%, below the threshold of %.1f%%. Undocumented values: [%s]",
+			chartDocumentationInfo.ChartDirectory,
+			coverage,
+			coverageThreshold,
+			strings.Join(undocumentedKeys, ", "),
+		)
+
+		failures.add(chartDocumentationInfo.ChartDirectory)
+	}
+}
+
+// checkSchemaValidation logs the ways in which a chart's default values don't match its values schema, failing the run
+// for them if asked to
+func checkSchemaValidation(chartDocumentationInfo helm.ChartDocumentationInfo, failures *chartFailures) {
+	if len(chartDocumentationInfo.ChartSchemaValidation.Violations) == 0 {
+		return
+	}
+
+	logViolation := log.Warnf
+	if viper.GetBool("fail-on-schema-violation") {
+		logViolation = log.Errorf
+		failures.add(chartDocumentationInfo.ChartDirectory)
+	}
+
+	for _, v := range chartDocumentationInfo.ChartSchemaValidation.Violations {
+		logViolation("Default value %s of chart %s is not valid against its values schema: %s", v.Key, chartDocumentationInfo.ChartDirectory, v.Description)
+	}
+}
+
+// checkLintResult logs the errors linting reported for a chart, failing the run for them if asked to
+func checkLintResult(chartDocumentationInfo helm.ChartDocumentationInfo, failures *chartFailures) {
+	if chartDocumentationInfo.ChartLint.Count("ERROR") == 0 {
+		return
+	}
+
+	logLintError := log.Warnf
+	if viper.GetBool("fail-on-lint-errors") {
+		logLintError = log.Errorf
+		failures.add(chartDocumentationInfo.ChartDirectory)
+	}
+
+	for _, m := range chartDocumentationInfo.ChartLint.Messages {
+		if m.Severity == "ERROR" {
+			logLintError("Linting reported an error for chart %s: %s: %s", chartDocumentationInfo.ChartDirectory, m.File, m.Message)
+		}
+	}
+}
+
+// isDocumentationCached returns whether a chart's documentation was last generated from the same inputs as it would be
+// now, and so can be skipped
+func isDocumentationCached(chartDocumentationInfo helm.ChartDocumentationInfo, cache *documentationCache) bool {
+	if cache == nil {
+		return false
+	}
+
+	hash, err := document.ChartDocumentationHash(chartDocumentationInfo)
+	if err != nil {
+		log.Warnf("Error hashing the inputs of chart %s, generating its documentation: %s", chartDocumentationInfo.ChartDirectory, err)
+		return false
+	}
+
+	return cache.unchanged(chartDocumentationInfo.ChartDirectory, hash)
+}
+
+// updateDocumentationCache records the hash of the inputs a chart's documentation has just been generated from
+func updateDocumentationCache(chartDocumentationInfo helm.ChartDocumentationInfo, cache *documentationCache) {
+	if cache == nil {
+		return
+	}
+
+	hash, err := document.ChartDocumentationHash(chartDocumentationInfo)
+	if err != nil {
+		log.Warnf("Error hashing the inputs of chart %s, not caching it: %s", chartDocumentationInfo.ChartDirectory, err)
+		return
+	}
+
+	cache.update(chartDocumentationInfo.ChartDirectory, hash)
+}
+
+func retrieveInfoAndPrintDocumentation(chartDirectory string, waitGroup *sync.WaitGroup, dryRun bool, failures *chartFailures, cache *documentationCache, report *runReport, progress *progressReporter) {
 	defer waitGroup.Done()
-	chartDocumentationInfo, err := helm.ParseChartInformation(chartDirectory)
+	defer progress.chartFinished()
+	ctx, cancel := chartContext()
+	defer cancel()
+	chartDocumentationInfo, err := helm.ParseChartInformationContext(ctx, chartDirectory)
 
 	if err != nil {
 		log.Warnf("Error parsing information for chart %s, skipping: %s", chartDirectory, err)
+		report.errored(chartDirectory, err)
+		if viper.GetBool("fail-on-error") || isTimeout(err) {
+			failures.add(chartDirectory)
+		}
+
 		return
 	}
 
-	document.PrintDocumentation(chartDocumentationInfo, dryRun)
+	report.parsed(chartDocumentationInfo)
+
+	if isDocumentationCached(chartDocumentationInfo, cache) {
+		log.Infof("Chart %s is unchanged since its documentation was last generated, skipping", chartDirectory)
+		report.skipped(chartDirectory)
+		checkDescriptionCoverage(chartDocumentationInfo, failures)
+		checkSchemaValidation(chartDocumentationInfo, failures)
+		checkLintResult(chartDocumentationInfo, failures)
+		return
+	}
 
+	// Documentation that fails to render is only a failure of the run in strict mode, or when failing on any error
+	err = document.PrintDocumentationContext(ctx, chartDocumentationInfo, dryRun)
+	switch err {
+	case nil:
+		report.processed(chartDirectory)
+	case document.ErrDocumentationOutOfDate:
+		report.processed(chartDirectory)
+		report.stale(chartDirectory)
+		failures.add(chartDirectory)
+	default:
+		report.errored(chartDirectory, err)
+		if viper.GetBool("strict") || viper.GetBool("fail-on-error") || isTimeout(err) {
+			failures.add(chartDirectory)
+		}
+	}
+
+	// Only documentation that was actually written is cached
+	if err == nil && !dryRun && !viper.GetBool("check") {
+		updateDocumentationCache(chartDocumentationInfo, cache)
+	}
+
+	checkDescriptionCoverage(chartDocumentationInfo, failures)
+	checkSchemaValidation(chartDocumentationInfo, failures)
+	checkLintResult(chartDocumentationInfo, failures)
+}
+
+func printIndex(indexFile string, charts []helm.ChartDocumentationInfo, dryRun bool, failures *chartFailures) {
+	err := document.PrintIndex(charts, dryRun)
+	if err == document.ErrDocumentationOutOfDate || (err != nil && (viper.GetBool("strict") || viper.GetBool("fail-on-error"))) {
+		failures.add(indexFile)
+	}
 }
 
-func helmDocs(_ *cobra.Command, _ []string) {
-	initializeCli()
+func helmDocs(command *cobra.Command, args []string) {
+	initializeCli(command)
+
+	// Directories given as arguments, e.g. to "helm docs" when run as a helm plugin, are searched for charts in place of
+	// the chart search roots
+	if len(args) > 0 {
+		viper.Set("chart-search-root", args)
+	}
+	document.HelmDocsVersion = version
+
+	// Deferred calls don't run on os.Exit, so failures set the code exited with once the charts downloaded from a
+	// repository have been removed
+	exitCode := 0
+	defer func() {
+		if exitCode != 0 {
+			os.Exit(exitCode)
+		}
+	}()
+
+	repositoryArchives := downloadRepositoryCharts()
+	defer func() { removeChartArchives(repositoryArchives) }()
+
 	chartDirs, err := helm.FindChartDirectories()
 
 	if err != nil {
 		log.Errorf("Error finding chart directories: %s", err)
-		os.Exit(1)
+		exitCode = 1
+		return
 	}
 
 	log.Infof("Found Chart directories [%s]", strings.Join(chartDirs, ", "))
 	dryRun := viper.GetBool("dry-run")
+	serial := dryRun || viper.GetBool("check")
 	waitGroup := sync.WaitGroup{}
+	failures := chartFailures{}
+	report := newRunReport()
 
+	// Charts are only skipped when their documentation would be written, so the cache is unused on dry runs
+	var cache *documentationCache
+	cacheFile := viper.GetString("cache-file")
+	if cacheFile != "" && !dryRun {
+		cache, err = loadDocumentationCache(cacheFile)
+		if err != nil {
+			log.Errorf("Error reading cache file %s: %s", cacheFile, err)
+			exitCode = 1
+			return
+		}
+	}
+
+	progress := startProgressReporter(len(chartDirs))
 	for _, c := range chartDirs {
 		waitGroup.Add(1)
 
-		// On dry runs all output goes to stdout, and so as to not jumble things, generate serially
-		if dryRun {
-			retrieveInfoAndPrintDocumentation(c, &waitGroup, dryRun)
+		// On dry runs and checks all output goes to stdout, and so as to not jumble things, generate serially
+		if serial {
+			retrieveInfoAndPrintDocumentation(c, &waitGroup, dryRun, &failures, cache, report, progress)
 		} else {
-			go retrieveInfoAndPrintDocumentation(c, &waitGroup, dryRun)
+			go retrieveInfoAndPrintDocumentation(c, &waitGroup, dryRun, &failures, cache, report, progress)
 		}
 	}
 
 	waitGroup.Wait()
+	progress.finish()
+	removeChartArchives(repositoryArchives)
+	repositoryArchives = nil
+
+	if cache != nil {
+		if err := cache.save(cacheFile); err != nil {
+			log.Warnf("Error writing cache file %s: %s", cacheFile, err)
+		}
+	}
+
+	if indexFile := viper.GetString("index-file"); indexFile != "" {
+		printIndex(indexFile, report.charts, dryRun, &failures)
+	}
+
+	report.write(&failures, viper.GetString("report-file"))
+
+	// In watch mode, failures are only logged, and documentation is regenerated as charts are edited until interrupted
+	if viper.GetBool("watch") {
+		log.Infof("Watching chart directories [%s] for changes", strings.Join(chartDirs, ", "))
+		err := watchChartDirectories(chartDirs, document.SharedTemplatePaths(), func(chartDirectory string) {
+			helm.ResetLocalCharts()
+			waitGroup.Add(1)
+			retrieveInfoAndPrintDocumentation(chartDirectory, &waitGroup, dryRun, &chartFailures{}, nil, newRunReport(), nil)
+		})
+
+		if err != nil {
+			log.Errorf("Error watching chart directories: %s", err)
+			exitCode = 1
+			return
+		}
+
+		log.Infof("Stopped watching chart directories")
+		return
+	}
+
+	if len(failures.charts) > 0 {
+		sort.Strings(failures.charts)
+		log.Errorf("Checks failed for charts [%s]", strings.Join(failures.charts, ", "))
+		exitCode = 1
+		return
+	}
 }
 
 func main() {
@@ -59,6 +321,49 @@ func main() {
 		os.Exit(1)
 	}
 
+	serveCommand, err := newServeCommand(serveDocumentation)
+	if err != nil {
+		log.Errorf("Failed to create the CLI commander: %s", err)
+		os.Exit(1)
+	}
+
+	preCommitCommand, err := newPreCommitCommand(preCommit)
+	if err != nil {
+		log.Errorf("Failed to create the CLI commander: %s", err)
+		os.Exit(1)
+	}
+
+	initCommand, err := newInitCommand(initTemplateFile)
+	if err != nil {
+		log.Errorf("Failed to create the CLI commander: %s", err)
+		os.Exit(1)
+	}
+
+	helmfileCommand, err := newHelmfileCommand(documentHelmfiles)
+	if err != nil {
+		log.Errorf("Failed to create the CLI commander: %s", err)
+		os.Exit(1)
+	}
+
+	versionCommand, err := newVersionCommand(printVersion)
+	if err != nil {
+		log.Errorf("Failed to create the CLI commander: %s", err)
+		os.Exit(1)
+	}
+
+	command.AddCommand(serveCommand)
+	command.AddCommand(initCommand)
+	command.AddCommand(preCommitCommand)
+	command.AddCommand(newLintCommand(lintCharts))
+	command.AddCommand(helmfileCommand)
+	command.AddCommand(versionCommand)
+	command.AddCommand(newCompletionCommand(printCompletion))
+
+	if err := command.RegisterFlagCompletionFunc("chart", completeChartNames); err != nil {
+		log.Errorf("Failed to create the CLI commander: %s", err)
+		os.Exit(1)
+	}
+
 	if err := command.Execute(); err != nil {
 		log.Errorf("Failed to start the CLI: %s", err)
 		os.Exit(1)
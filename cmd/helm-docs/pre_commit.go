@@ -0,0 +1,128 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/norwoodj/helm-docs/pkg/document"
+	"github.com/norwoodj/helm-docs/pkg/helm"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// isSharedTemplateFile returns whether a file is one of the template files shared between all charts, i.e. one of the
+// shared template paths, or a .gotmpl file in one of them that's a directory
+func isSharedTemplateFile(sharedTemplatePaths []string, file string) bool {
+	absoluteFile, _ := filepath.Abs(file)
+
+	for _, p := range sharedTemplatePaths {
+		absolutePath, _ := filepath.Abs(p)
+		if absoluteFile == absolutePath {
+			return true
+		}
+
+		if info, err := os.Stat(absolutePath); err == nil && info.IsDir() && filepath.Dir(absoluteFile) == absolutePath && filepath.Ext(absoluteFile) == ".gotmpl" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// chartsOwningFiles returns the chart directories containing any of the given files. Files in subcharts belong to the
+// subchart, i.e. the innermost of the chart directories, whereas the shared template files belong to every chart
+func chartsOwningFiles(chartDirs []string, sharedTemplatePaths []string, files []string) []string {
+	owningChartDirs := make(map[string]bool)
+
+	for _, f := range files {
+		if isSharedTemplateFile(sharedTemplatePaths, f) {
+			return chartDirs
+		}
+
+		absoluteFile, _ := filepath.Abs(f)
+		owningChartDir := ""
+		owningChartDirLength := -1
+
+		for _, c := range chartDirs {
+			absoluteChartDir, _ := filepath.Abs(c)
+			relativeFile, err := filepath.Rel(absoluteChartDir, absoluteFile)
+			if err != nil || relativeFile == ".." || strings.HasPrefix(relativeFile, ".."+string(filepath.Separator)) {
+				continue
+			}
+
+			if len(absoluteChartDir) > owningChartDirLength {
+				owningChartDir = c
+				owningChartDirLength = len(absoluteChartDir)
+			}
+		}
+
+		if owningChartDir != "" {
+			owningChartDirs[owningChartDir] = true
+		}
+	}
+
+	changedChartDirs := make([]string, 0, len(owningChartDirs))
+	for _, c := range chartDirs {
+		if owningChartDirs[c] {
+			changedChartDirs = append(changedChartDirs, c)
+		}
+	}
+
+	return changedChartDirs
+}
+
+// preCommit regenerates the documentation of the charts containing the files changed in a commit, as passed by the
+// pre-commit framework. Updated documentation is either staged, or makes the hook fail so that it can be reviewed and
+// staged before committing again
+func preCommit(command *cobra.Command, changedFiles []string) {
+	initializeCli(command)
+	document.HelmDocsVersion = version
+	chartDirs, err := helm.FindChartDirectories()
+
+	if err != nil {
+		log.Errorf("Error finding chart directories: %s", err)
+		os.Exit(1)
+	}
+
+	changedChartDirs := chartsOwningFiles(chartDirs, document.SharedTemplatePaths(), changedFiles)
+	log.Infof("Found changed chart directories [%s]", strings.Join(changedChartDirs, ", "))
+	updatedPaths := make([]string, 0)
+	failed := false
+
+	for _, c := range changedChartDirs {
+		chartDocumentationInfo, err := helm.ParseChartInformation(c)
+		if err != nil {
+			log.Errorf("Error parsing information for chart %s: %s", c, err)
+			failed = true
+			continue
+		}
+
+		chartUpdatedPaths, err := document.UpdateDocumentation(chartDocumentationInfo)
+		updatedPaths = append(updatedPaths, chartUpdatedPaths...)
+		if err != nil {
+			failed = true
+		}
+	}
+
+	if len(updatedPaths) > 0 && viper.GetBool("stage") {
+		command := exec.Command("git", append([]string{"add", "--"}, updatedPaths...)...)
+		command.Stderr = os.Stderr
+
+		if err := command.Run(); err != nil {
+			log.Errorf("Error staging updated documentation [%s]: %s", strings.Join(updatedPaths, ", "), err)
+			os.Exit(1)
+		}
+
+		log.Infof("Staged updated documentation [%s]", strings.Join(updatedPaths, ", "))
+	} else if len(updatedPaths) > 0 {
+		log.Errorf("Updated documentation [%s], review and stage it before committing again", strings.Join(updatedPaths, ", "))
+		failed = true
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChartsOwningFiles(t *testing.T) {
+	chartDirs := []string{"charts/app", "charts/app/charts/cache", "charts/db", "other/app"}
+
+	assert.Equal(t, []string{"charts/app"}, chartsOwningFiles(chartDirs, nil, []string{"charts/app/values.yaml", "charts/app/templates/deployment.yaml"}))
+	assert.Equal(t, []string{"charts/app/charts/cache"}, chartsOwningFiles(chartDirs, nil, []string{"charts/app/charts/cache/values.yaml"}))
+	assert.Equal(t, []string{"charts/db", "other/app"}, chartsOwningFiles(chartDirs, nil, []string{"other/app/Chart.yaml", "charts/db/README.md.gotmpl"}))
+	assert.Equal(t, []string{}, chartsOwningFiles(chartDirs, nil, []string{"charts/application/values.yaml", "README.md"}))
+}
+
+func TestChartsOwningSharedTemplateFiles(t *testing.T) {
+	sharedTemplatesDir, err := ioutil.TempDir("", "helm-docs-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(sharedTemplatesDir)
+
+	chartDirs := []string{"charts/app", "charts/db"}
+	sharedTemplatePaths := []string{"_templates.gotmpl", sharedTemplatesDir, "/templates/README.md.gotmpl"}
+
+	assert.Equal(t, chartDirs, chartsOwningFiles(chartDirs, sharedTemplatePaths, []string{"_templates.gotmpl"}))
+	assert.Equal(t, chartDirs, chartsOwningFiles(chartDirs, sharedTemplatePaths, []string{path.Join(sharedTemplatesDir, "badges.gotmpl")}))
+	assert.Equal(t, chartDirs, chartsOwningFiles(chartDirs, sharedTemplatePaths, []string{"charts/app/values.yaml", "/templates/README.md.gotmpl"}))
+
+	// Only the templates directly in the shared templates directory are parsed
+	assert.Equal(t, []string{}, chartsOwningFiles(chartDirs, sharedTemplatePaths, []string{path.Join(sharedTemplatesDir, "notes.txt")}))
+	assert.Equal(t, []string{}, chartsOwningFiles(chartDirs, sharedTemplatePaths, []string{path.Join(sharedTemplatesDir, "old", "badges.gotmpl")}))
+	assert.Equal(t, []string{"charts/db"}, chartsOwningFiles(chartDirs, sharedTemplatePaths, []string{"charts/db/_templates.gotmpl"}))
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"sync/atomic"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+// How often progress is logged while charts are being documented
+const progressInterval = 5 * time.Second
+
+// progressReporter periodically logs how many of the charts found have been documented so far, so that long runs over
+// many charts, during which little else may be logged, don't look hung
+type progressReporter struct {
+	total    int
+	finished int64
+	stop     chan struct{}
+	stopped  chan struct{}
+}
+
+// startProgressReporter starts logging the progress of documenting the given number of charts, unless running quietly
+func startProgressReporter(total int) *progressReporter {
+	if viper.GetBool("quiet") {
+		return nil
+	}
+
+	return newProgressReporter(total, progressInterval)
+}
+
+// newProgressReporter starts logging the progress of documenting the given number of charts at the given interval
+func newProgressReporter(total int, interval time.Duration) *progressReporter {
+	p := &progressReporter{total: total, stop: make(chan struct{}), stopped: make(chan struct{})}
+	go p.run(interval)
+	return p
+}
+
+func (p *progressReporter) run(interval time.Duration) {
+	defer close(p.stopped)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			log.Infof("Documented %d/%d charts", atomic.LoadInt64(&p.finished), p.total)
+		case <-p.stop:
+			return
+		}
+	}
+}
+
+// chartFinished counts a chart as documented, whether or not its documentation could be generated
+func (p *progressReporter) chartFinished() {
+	if p == nil {
+		return
+	}
+
+	atomic.AddInt64(&p.finished, 1)
+}
+
+// finish stops logging progress once every chart has been documented
+func (p *progressReporter) finish() {
+	if p == nil {
+		return
+	}
+
+	close(p.stop)
+	<-p.stopped
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+// syncBuffer is a buffer logs can be written to while it's read
+type syncBuffer struct {
+	mutex  sync.Mutex
+	buffer bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	return b.buffer.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	return b.buffer.String()
+}
+
+func TestProgressReporter(t *testing.T) {
+	var logs syncBuffer
+	defer log.SetOutput(log.StandardLogger().Out)
+	log.SetOutput(&logs)
+
+	progress := newProgressReporter(3, 10*time.Millisecond)
+	progress.chartFinished()
+
+	assert.Eventually(t, func() bool { return strings.Contains(logs.String(), "Documented 1/3 charts") }, time.Second, 5*time.Millisecond)
+
+	progress.chartFinished()
+	progress.chartFinished()
+	progress.finish()
+
+	// Nothing is logged once the reporter has finished
+	loggedAtFinish := logs.String()
+	time.Sleep(30 * time.Millisecond)
+	assert.Equal(t, loggedAtFinish, logs.String())
+}
+
+func TestProgressReporterQuiet(t *testing.T) {
+	viper.Set("quiet", true)
+	defer viper.Set("quiet", nil)
+
+	progress := startProgressReporter(3)
+	assert.Nil(t, progress)
+
+	// A reporter that was never started, as when running quietly, can be used all the same
+	progress.chartFinished()
+	progress.finish()
+}
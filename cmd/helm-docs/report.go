@@ -0,0 +1,115 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"sort"
+	"sync"
+
+	"github.com/norwoodj/helm-docs/pkg/helm"
+	log "github.com/sirupsen/logrus"
+)
+
+// runReport summarizes what happened to each chart during a run, for CI systems to parse rather than the logs
+type runReport struct {
+	mutex sync.Mutex
+
+	// Charts whose documentation was generated, or checked
+	Processed []string `json:"processed"`
+
+	// Charts which were skipped, because they couldn't be parsed or rendered, or were unchanged since the last run
+	Skipped []string `json:"skipped"`
+
+	// Charts which failed a check, making the run fail
+	Failed []string `json:"failed"`
+
+	// Charts whose documentation was found to be out of date when checking it
+	Stale []string `json:"stale"`
+
+	// The errors for which skipped charts' documentation couldn't be generated, by chart
+	Errors map[string]string `json:"errors"`
+
+	// The information of every chart that could be parsed, for the index of charts
+	charts []helm.ChartDocumentationInfo
+}
+
+func newRunReport() *runReport {
+	return &runReport{Processed: []string{}, Skipped: []string{}, Failed: []string{}, Stale: []string{}, Errors: make(map[string]string)}
+}
+
+func (r *runReport) add(charts *[]string, chartDirectory string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	*charts = append(*charts, chartDirectory)
+}
+
+func (r *runReport) processed(chartDirectory string) { r.add(&r.Processed, chartDirectory) }
+func (r *runReport) skipped(chartDirectory string)   { r.add(&r.Skipped, chartDirectory) }
+func (r *runReport) stale(chartDirectory string)     { r.add(&r.Stale, chartDirectory) }
+
+func (r *runReport) parsed(chartDocumentationInfo helm.ChartDocumentationInfo) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.charts = append(r.charts, chartDocumentationInfo)
+}
+
+// errored records a chart skipped because of an error
+func (r *runReport) errored(chartDirectory string, err error) {
+	r.add(&r.Skipped, chartDirectory)
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.Errors[chartDirectory] = err.Error()
+}
+
+// logErrors logs every chart that was skipped because of an error together, so that they aren't lost in the logs of
+// the others
+func (r *runReport) logErrors() {
+	chartDirectories := make([]string, 0, len(r.Errors))
+	for c := range r.Errors {
+		chartDirectories = append(chartDirectories, c)
+	}
+
+	sort.Strings(chartDirectories)
+	for _, c := range chartDirectories {
+		log.WithField("chart", c).Errorf("Documentation of chart %s was not generated: %s", c, r.Errors[c])
+	}
+}
+
+// write finishes the report with the charts that failed, then writes it to the report file if one is given, or
+// otherwise logs a summary of it
+func (r *runReport) write(failures *chartFailures, reportFile string) {
+	r.Failed = append(r.Failed, failures.charts...)
+	for _, charts := range [][]string{r.Processed, r.Skipped, r.Failed, r.Stale} {
+		sort.Strings(charts)
+	}
+
+	r.logErrors()
+
+	if reportFile == "" {
+		log.WithFields(log.Fields{
+			"processed": len(r.Processed),
+			"skipped":   len(r.Skipped),
+			"failed":    len(r.Failed),
+			"stale":     len(r.Stale),
+		}).Info("Finished generating documentation")
+
+		return
+	}
+
+	// Errors often quote templates, so characters significant in html are left unescaped
+	var contents bytes.Buffer
+	encoder := json.NewEncoder(&contents)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+
+	err := encoder.Encode(r)
+	if err == nil {
+		err = ioutil.WriteFile(reportFile, contents.Bytes(), 0644)
+	}
+
+	if err != nil {
+		log.Warnf("Error writing report file %s: %s", reportFile, err)
+	}
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path"
+	"sync"
+	"testing"
+
+	"github.com/norwoodj/helm-docs/pkg/helm"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewLogFormatter(t *testing.T) {
+	formatter, err := newLogFormatter("json")
+	assert.Nil(t, err)
+
+	var logs bytes.Buffer
+	logger := log.New()
+	logger.SetOutput(&logs)
+	logger.SetFormatter(formatter)
+	logger.WithField("chart", "charts/app").Error("Documentation of chart charts/app was not generated")
+
+	var entry map[string]interface{}
+	assert.Nil(t, json.Unmarshal(logs.Bytes(), &entry))
+	assert.Equal(t, "charts/app", entry["chart"])
+	assert.Equal(t, "error", entry["level"])
+
+	_, err = newLogFormatter("text")
+	assert.Nil(t, err)
+
+	_, err = newLogFormatter("yaml")
+	assert.NotNil(t, err)
+}
+
+func TestRunReportWrite(t *testing.T) {
+	reportDirectory, err := ioutil.TempDir("", "helm-docs-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(reportDirectory)
+
+	report := newRunReport()
+	report.processed("charts/web")
+	report.processed("charts/app")
+	report.stale("charts/app")
+	report.skipped("charts/cached")
+	report.errored("charts/broken", errors.New("template: <no value>"))
+
+	failures := chartFailures{}
+	failures.add("charts/app")
+
+	reportFile := path.Join(reportDirectory, "report.json")
+	report.write(&failures, reportFile)
+
+	contents, err := ioutil.ReadFile(reportFile)
+	assert.Nil(t, err)
+
+	var written map[string]interface{}
+	assert.Nil(t, json.Unmarshal(contents, &written))
+	assert.Equal(t, []interface{}{"charts/app", "charts/web"}, written["processed"])
+	assert.Equal(t, []interface{}{"charts/broken", "charts/cached"}, written["skipped"])
+	assert.Equal(t, []interface{}{"charts/app"}, written["failed"])
+	assert.Equal(t, []interface{}{"charts/app"}, written["stale"])
+	assert.Equal(t, map[string]interface{}{"charts/broken": "template: <no value>"}, written["errors"])
+	assert.Contains(t, string(contents), "<no value>")
+}
+
+func TestRunReportWriteChartFailingSeveralChecks(t *testing.T) {
+	viper.Set("fail-on-schema-violation", true)
+	viper.Set("fail-on-lint-errors", true)
+	defer viper.Set("fail-on-schema-violation", nil)
+	defer viper.Set("fail-on-lint-errors", nil)
+
+	chartDocumentationInfo := helm.ChartDocumentationInfo{
+		ChartDirectory:        "charts/app",
+		ChartSchemaValidation: helm.ChartSchemaValidation{Violations: []helm.ChartSchemaViolation{{Key: "replicas", Description: "must be an integer"}}},
+		ChartLint:             helm.ChartLintResult{Linted: true, Messages: []helm.ChartLintMessage{{Severity: "ERROR", File: "Chart.yaml", Message: "version is required"}}},
+	}
+
+	failures := chartFailures{}
+	checkSchemaValidation(chartDocumentationInfo, &failures)
+	checkLintResult(chartDocumentationInfo, &failures)
+	failures.add("charts/app")
+
+	report := newRunReport()
+	report.write(&failures, "")
+	assert.Equal(t, []string{"charts/app"}, failures.charts)
+	assert.Equal(t, []string{"charts/app"}, report.Failed)
+}
+
+func TestFailOnError(t *testing.T) {
+	chartDirectory, err := ioutil.TempDir("", "helm-docs-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(chartDirectory)
+
+	assert.Nil(t, ioutil.WriteFile(path.Join(chartDirectory, "Chart.yaml"), []byte("apiVersion: v2\nname: broken\nversion: 1.0.0\n"), 0644))
+	assert.Nil(t, ioutil.WriteFile(path.Join(chartDirectory, "values.yaml"), []byte("replicas: [unclosed\n"), 0644))
+
+	document := func() (*chartFailures, *runReport) {
+		waitGroup := sync.WaitGroup{}
+		waitGroup.Add(1)
+		failures := &chartFailures{}
+		report := newRunReport()
+		retrieveInfoAndPrintDocumentation(chartDirectory, &waitGroup, true, failures, nil, report, nil)
+		return failures, report
+	}
+
+	failures, report := document()
+	assert.Empty(t, failures.charts)
+	assert.Equal(t, []string{chartDirectory}, report.Skipped)
+	assert.Contains(t, report.Errors, chartDirectory)
+
+	viper.Set("fail-on-error", true)
+	defer viper.Set("fail-on-error", nil)
+
+	failures, report = document()
+	assert.Equal(t, []string{chartDirectory}, failures.charts)
+	assert.Equal(t, []string{chartDirectory}, report.Skipped)
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"os"
+
+	"github.com/norwoodj/helm-docs/pkg/helm"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+// downloadRepositoryCharts downloads the latest version of each chart listed in the repository index given, if any, to
+// the repository output directory, to be documented there as chart archives. The archives are returned so that they can
+// be removed once documented
+func downloadRepositoryCharts() []string {
+	repositoryIndex := viper.GetString("repository-index")
+	if repositoryIndex == "" {
+		return nil
+	}
+
+	chartArchives, err := helm.DownloadRepositoryCharts(repositoryIndex, viper.GetString("repository-output-dir"))
+	if err != nil {
+		removeChartArchives(chartArchives)
+		log.Errorf("Error downloading the charts in repository index %s: %s", repositoryIndex, err)
+		os.Exit(1)
+	}
+
+	if len(chartArchives) == 0 {
+		log.Errorf("Found no charts to document in repository index %s", repositoryIndex)
+		os.Exit(1)
+	}
+
+	viper.Set("chart-archive", chartArchives)
+	return chartArchives
+}
+
+func removeChartArchives(chartArchives []string) {
+	for _, a := range chartArchives {
+		if err := os.Remove(a); err != nil {
+			log.Warnf("Error removing chart archive %s: %s", a, err)
+		}
+	}
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"html/template"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/norwoodj/helm-docs/pkg/document"
+	"github.com/norwoodj/helm-docs/pkg/helm"
+	"github.com/russross/blackfriday"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// The page served for each document polls for the hash of the document's current rendering, reloading itself when the
+// hash changes, so that edits to charts show up in the browser without refreshing it
+const previewPageTemplate = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{ .Title }}</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Helvetica, Arial, sans-serif; line-height: 1.5; max-width: 980px; margin: 0 auto; padding: 32px; color: #24292e; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #dfe2e5; padding: 6px 13px; }
+tr:nth-child(2n) { background-color: #f6f8fa; }
+code, pre { font-family: SFMono-Regular, Consolas, Menlo, monospace; background-color: #f6f8fa; border-radius: 3px; }
+pre { padding: 16px; overflow: auto; }
+</style>
+</head>
+<body>
+{{ .Body }}
+{{- if .Hash }}
+<script>
+setInterval(function () {
+  fetch(window.location.pathname + "?hash").then(function (response) { return response.text(); }).then(function (hash) {
+    if (hash !== "{{ .Hash }}") { window.location.reload(); }
+  });
+}, 1000);
+</script>
+{{- end }}
+</body>
+</html>
+`
+
+var previewPage = template.Must(template.New("preview").Parse(previewPageTemplate))
+
+type previewPageData struct {
+	Title string
+	Body  template.HTML
+	Hash  string
+}
+
+// renderAllDocumentation renders the documentation of every chart in memory, keyed by output path
+func renderAllDocumentation() (map[string]string, []string, error) {
+	helm.ResetLocalCharts()
+	chartDirs, err := helm.FindChartDirectories()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	documents := make(map[string]string)
+	outputPaths := make([]string, 0)
+
+	for _, c := range chartDirs {
+		chartDocumentationInfo, err := helm.ParseChartInformation(c)
+		if err != nil {
+			log.Warnf("Error parsing information for chart %s, skipping: %s", c, err)
+			continue
+		}
+
+		chartDocuments, err := document.RenderDocumentation(chartDocumentationInfo)
+		if err != nil {
+			continue
+		}
+
+		for _, d := range chartDocuments {
+			outputPath := filepath.ToSlash(d.OutputPath)
+			documents[outputPath] = d.Documentation
+			outputPaths = append(outputPaths, outputPath)
+		}
+	}
+
+	return documents, outputPaths, nil
+}
+
+func writePreviewPage(w http.ResponseWriter, data previewPageData) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := previewPage.Execute(w, data); err != nil {
+		log.Warnf("Error writing preview page: %s", err)
+	}
+}
+
+// previewServer serves the documentation of the charts as rendered by render, which is kept until it's invalidated by
+// a change to the charts, rather than rendered on every request, as open pages poll for changes every second
+type previewServer struct {
+	render func() (map[string]string, []string, error)
+
+	mutex       sync.Mutex
+	rendered    bool
+	uncached    bool
+	documents   map[string]string
+	outputPaths []string
+}
+
+// invalidate discards the rendered documentation, so that the next request renders it afresh
+func (s *previewServer) invalidate() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.rendered = false
+}
+
+// disableCache makes every request render the documentation afresh, for when changes to the charts can't be watched
+func (s *previewServer) disableCache() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.uncached = true
+}
+
+func (s *previewServer) renderedDocumentation() (map[string]string, []string, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.rendered && !s.uncached {
+		return s.documents, s.outputPaths, nil
+	}
+
+	documents, outputPaths, err := s.render()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	s.documents, s.outputPaths, s.rendered = documents, outputPaths, true
+	return documents, outputPaths, nil
+}
+
+// ServeHTTP serves an index of the charts' documents at the root and each document as html under its output path
+func (s *previewServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	documents, outputPaths, err := s.renderedDocumentation()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	requestedPath := strings.TrimPrefix(r.URL.Path, "/")
+	if requestedPath == "" {
+		index := strings.Builder{}
+		index.WriteString("# Charts\n\n")
+		for _, p := range outputPaths {
+			index.WriteString(fmt.Sprintf("* [%s](/%s)\n", p, p))
+		}
+
+		writePreviewPage(w, previewPageData{Title: "helm-docs", Body: template.HTML(blackfriday.MarkdownCommon([]byte(index.String())))})
+		return
+	}
+
+	documentation, ok := documents[requestedPath]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	hash := fmt.Sprintf("%x", sha256.Sum256([]byte(documentation)))
+	if _, ok := r.URL.Query()["hash"]; ok {
+		fmt.Fprint(w, hash)
+		return
+	}
+
+	writePreviewPage(w, previewPageData{
+		Title: requestedPath,
+		Body:  template.HTML(blackfriday.MarkdownCommon([]byte(documentation))),
+		Hash:  hash,
+	})
+}
+
+func serveDocumentation(command *cobra.Command, _ []string) {
+	initializeCli(command)
+	document.HelmDocsVersion = version
+
+	chartDirs, err := helm.FindChartDirectories()
+	if err != nil {
+		log.Errorf("Error finding chart directories: %s", err)
+		os.Exit(1)
+	}
+
+	server := &previewServer{render: renderAllDocumentation}
+
+	// The rendered documentation is discarded as the charts change. Charts created while serving are only found once
+	// some other chart changes
+	go func() {
+		err := watchChartDirectories(chartDirs, document.SharedTemplatePaths(), func(string) { server.invalidate() })
+		if err != nil {
+			log.Warnf("Error watching chart directories, rendering documentation on every request instead: %s", err)
+			server.disableCache()
+		}
+	}()
+
+	address := viper.GetString("address")
+	log.Infof("Serving previews of chart documentation on http://%s", address)
+
+	if err := http.ListenAndServe(address, server); err != nil {
+		log.Errorf("Error serving previews of chart documentation: %s", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestPreviewServer serves the documentation of a single chart, rendered from what's written to it when rendered
+func newTestPreviewServer(documentation *string, renders *int) *previewServer {
+	return &previewServer{render: func() (map[string]string, []string, error) {
+		*renders++
+		return map[string]string{"charts/app/README.md": *documentation}, []string{"charts/app/README.md"}, nil
+	}}
+}
+
+func getPreview(server http.Handler, target string) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	server.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, target, nil))
+	return recorder
+}
+
+func TestPreviewServer(t *testing.T) {
+	renders := 0
+	documentation := "# app\n"
+	server := newTestPreviewServer(&documentation, &renders)
+
+	index := getPreview(server, "/")
+	assert.Equal(t, http.StatusOK, index.Code)
+	assert.Contains(t, index.Body.String(), `<a href="/charts/app/README.md">charts/app/README.md</a>`)
+
+	page := getPreview(server, "/charts/app/README.md")
+	assert.Equal(t, http.StatusOK, page.Code)
+	assert.Contains(t, page.Body.String(), "<h1>app</h1>")
+
+	hash := getPreview(server, "/charts/app/README.md?hash")
+	assert.Equal(t, fmt.Sprintf("%x", sha256.Sum256([]byte("# app\n"))), hash.Body.String())
+
+	assert.Equal(t, http.StatusNotFound, getPreview(server, "/charts/db/README.md").Code)
+	assert.Equal(t, 1, renders)
+}
+
+func TestPreviewServerInvalidate(t *testing.T) {
+	renders := 0
+	documentation := "# app\n"
+	server := newTestPreviewServer(&documentation, &renders)
+
+	hash := getPreview(server, "/charts/app/README.md?hash").Body.String()
+
+	documentation = "# application\n"
+	getPreview(server, "/charts/app/README.md?hash")
+	assert.Equal(t, hash, getPreview(server, "/charts/app/README.md?hash").Body.String())
+	assert.Equal(t, 1, renders)
+
+	server.invalidate()
+	assert.NotEqual(t, hash, getPreview(server, "/charts/app/README.md?hash").Body.String())
+	assert.Equal(t, 2, renders)
+
+	server.disableCache()
+	getPreview(server, "/charts/app/README.md?hash")
+	getPreview(server, "/charts/app/README.md?hash")
+	assert.Equal(t, 4, renders)
+}
+
+func TestPreviewServerRenderError(t *testing.T) {
+	renders := 0
+	server := &previewServer{render: func() (map[string]string, []string, error) {
+		renders++
+		return nil, nil, errors.New("failed to find chart directories")
+	}}
+
+	response := getPreview(server, "/")
+	assert.Equal(t, http.StatusInternalServerError, response.Code)
+	assert.Contains(t, response.Body.String(), "failed to find chart directories")
+
+	// Failed renders aren't kept, so that the next request tries again
+	getPreview(server, "/")
+	assert.Equal(t, 2, renders)
+}
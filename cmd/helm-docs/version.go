@@ -0,0 +1,39 @@
+package main
+
+import (
+	"fmt"
+	"runtime"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// Build metadata, injected at build time with ldflags, e.g. -X main.version=1.0.0 -X main.commit=$(git rev-parse HEAD)
+var (
+	version string
+	commit  string
+	date    string
+)
+
+func buildMetadataOrUnknown(value string) string {
+	if value == "" {
+		return "unknown"
+	}
+
+	return value
+}
+
+// printVersion prints the version helm-docs was built as, along with the commit and date it was built from and the go
+// version it was built with, or only the version with --short so that scripts can compare it
+func printVersion(_ *cobra.Command, _ []string) {
+	if viper.GetBool("short") {
+		fmt.Println(buildMetadataOrUnknown(version))
+		return
+	}
+
+	fmt.Printf("helm-docs version %s\n", buildMetadataOrUnknown(version))
+	fmt.Printf("  commit:     %s\n", buildMetadataOrUnknown(commit))
+	fmt.Printf("  built:      %s\n", buildMetadataOrUnknown(date))
+	fmt.Printf("  go version: %s\n", runtime.Version())
+	fmt.Printf("  platform:   %s/%s\n", runtime.GOOS, runtime.GOARCH)
+}
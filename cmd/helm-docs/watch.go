@@ -0,0 +1,144 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	log "github.com/sirupsen/logrus"
+)
+
+// Editors often write a file in several steps, so changes are only acted upon once none have been seen for this long
+const watchDebounceInterval = 200 * time.Millisecond
+
+// isWatchedFile returns whether a change to a file in a chart directory could change the chart's documentation. Only
+// the chart's yaml, json, template and notes files and packaged subcharts are watched, so that writing the
+// documentation doesn't itself trigger regenerating it
+func isWatchedFile(name string) bool {
+	switch filepath.Ext(name) {
+	case ".yaml", ".yml", ".json", ".gotmpl", ".tpl", ".txt", ".tgz":
+		return true
+	default:
+		return false
+	}
+}
+
+// watchDirectoryTree watches a directory and all of its subdirectories, other than hidden ones such as .git
+func watchDirectoryTree(watcher *fsnotify.Watcher, directory string) error {
+	return filepath.Walk(directory, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !info.IsDir() && p != directory {
+			return nil
+		}
+
+		if p != directory && strings.HasPrefix(info.Name(), ".") {
+			return filepath.SkipDir
+		}
+
+		return watcher.Add(p)
+	})
+}
+
+// watchSharedTemplates watches the template files shared between all charts, a change to any of which changes the
+// documentation of every chart, and the shared template directories that exist. Files are watched through their
+// directory, as editors often replace a file rather than write to it
+func watchSharedTemplates(watcher *fsnotify.Watcher, sharedTemplatePaths []string) error {
+	for _, p := range sharedTemplatePaths {
+		absolutePath, err := filepath.Abs(p)
+		if err != nil {
+			return err
+		}
+
+		watchedDirectory := filepath.Dir(absolutePath)
+		if info, err := os.Stat(absolutePath); err == nil && info.IsDir() {
+			watchedDirectory = absolutePath
+		}
+
+		if _, err := os.Stat(watchedDirectory); os.IsNotExist(err) {
+			continue
+		}
+
+		if err := watcher.Add(watchedDirectory); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// watchChartDirectories calls generate for each chart directory in which, or in any of whose subdirectories, a file
+// that could change its documentation changes, and for every chart directory when a shared template file changes. It
+// returns an error once watching the directories fails, or nil if the watcher is closed
+func watchChartDirectories(chartDirs []string, sharedTemplatePaths []string, generate func(chartDirectory string)) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+
+	defer watcher.Close()
+
+	for _, c := range chartDirs {
+		if err := watchDirectoryTree(watcher, c); err != nil {
+			return err
+		}
+	}
+
+	if err := watchSharedTemplates(watcher, sharedTemplatePaths); err != nil {
+		return err
+	}
+
+	changedChartDirs := make(map[string]bool)
+	var debounce <-chan time.Time
+
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+
+			// Directories created in a chart, e.g. a new templates/ directory, are watched in turn
+			if event.Op&fsnotify.Create != 0 {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() && len(chartsOwningFiles(chartDirs, nil, []string{event.Name})) > 0 {
+					if err := watchDirectoryTree(watcher, event.Name); err != nil {
+						log.Warnf("Error watching directory %s: %s", event.Name, err)
+					}
+				}
+			}
+
+			if !isWatchedFile(event.Name) || event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Remove|fsnotify.Rename) == 0 {
+				continue
+			}
+
+			changedFileChartDirs := chartsOwningFiles(chartDirs, sharedTemplatePaths, []string{event.Name})
+			if len(changedFileChartDirs) == 0 {
+				continue
+			}
+
+			log.Debugf("Detected change to %s", event.Name)
+			for _, c := range changedFileChartDirs {
+				changedChartDirs[c] = true
+			}
+
+			debounce = time.After(watchDebounceInterval)
+		case <-debounce:
+			for c := range changedChartDirs {
+				generate(c)
+			}
+
+			changedChartDirs = make(map[string]bool)
+			debounce = nil
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+
+			return err
+		}
+	}
+}
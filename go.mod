@@ -4,15 +4,25 @@ go 1.14
 
 require (
 	github.com/Masterminds/goutils v1.1.0 // indirect
-	github.com/Masterminds/semver v1.4.2 // indirect
+	github.com/Masterminds/semver v1.4.2
 	github.com/Masterminds/sprig v2.20.0+incompatible
+	github.com/cyphar/filepath-securejoin v0.2.2 // indirect
+	github.com/fsnotify/fsnotify v1.4.7
+	github.com/gobwas/glob v0.2.3 // indirect
+	github.com/golang/protobuf v1.3.2
 	github.com/google/uuid v1.1.1 // indirect
 	github.com/huandu/xstrings v1.2.0 // indirect
 	github.com/imdario/mergo v0.3.7 // indirect
+	github.com/pmezard/go-difflib v1.0.0
+	github.com/russross/blackfriday v1.5.2
 	github.com/sirupsen/logrus v1.2.0
-	github.com/spf13/cobra v0.0.5
+	github.com/spf13/cobra v1.0.0
+	github.com/spf13/pflag v1.0.5
 	github.com/spf13/viper v1.4.0
-	github.com/stretchr/testify v1.2.2
-	gopkg.in/yaml.v2 v2.2.2
+	github.com/stretchr/testify v1.4.0
+	github.com/xeipuuv/gojsonschema v1.2.0
+	gopkg.in/yaml.v2 v2.2.4
+	gopkg.in/yaml.v3 v3.0.1
+	k8s.io/apimachinery v0.17.0 // indirect
 	k8s.io/helm v2.14.3+incompatible
 )
@@ -0,0 +1,121 @@
+// Package docs is the API for embedding helm-docs in other programs, e.g. chart scaffolders or internal portals, so that
+// they can generate the documentation of charts without running the helm-docs command. Its options are given as Go
+// values rather than read from the command line, the environment or a configuration file.
+package docs
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/norwoodj/helm-docs/pkg/document"
+	"github.com/norwoodj/helm-docs/pkg/helm"
+	"github.com/norwoodj/helm-docs/pkg/settings"
+	"github.com/spf13/viper"
+)
+
+// Options configures the documentation of a chart
+type Options struct {
+	// The directory of the chart to document, or the path of a packaged chart (.tgz)
+	ChartDirectory string
+
+	// Template files, relative to the chart directory unless absolute, from which the documentation is generated, later
+	// files overriding the defines of earlier ones. Defaults to README.md.gotmpl, or the built in template if the chart
+	// has none
+	TemplateFiles []string
+
+	// The file, relative to the chart directory, to which the documentation is written. Defaults to README.md
+	OutputFile string
+
+	// Whether to write the documentation to the chart's output files, rather than only rendering it
+	Write bool
+
+	// Any other option of the helm-docs command, keyed by its name without the leading dashes, e.g.
+	// "sort-values-order" or "render-resources", with a value of the option's type. Names of options the command
+	// doesn't have are an error
+	Settings map[string]interface{}
+}
+
+// Result is the documentation generated for a chart
+type Result struct {
+	// What was parsed from the chart's files, from which its documentation was rendered
+	Chart helm.ChartDocumentationInfo
+
+	// The documentation rendered for each of the chart's output files
+	Documents []document.RenderedDocument
+
+	// The output files that were written because their contents changed, when writing documentation
+	UpdatedFiles []string
+}
+
+// settings returns a viper instance of its own holding the options, so that those of the helm-docs command, and of any
+// program embedding it using viper's global instance, are left alone. Settings not given take the defaults of the
+// helm-docs command's flags, and settings the command doesn't have are an error rather than being silently ignored
+func (o Options) settings() (*viper.Viper, error) {
+	v := viper.New()
+	defaults := settings.Defaults()
+	for k, d := range defaults {
+		v.SetDefault(k, d)
+	}
+
+	for k, value := range o.Settings {
+		if _, ok := defaults[k]; !ok {
+			return nil, fmt.Errorf("unknown setting %s", k)
+		}
+
+		v.Set(k, value)
+	}
+
+	if len(o.TemplateFiles) > 0 {
+		v.Set("template-file", o.TemplateFiles)
+	}
+
+	if o.OutputFile != "" {
+		v.Set("output-file", o.OutputFile)
+	}
+
+	return v, nil
+}
+
+// GenerateChart parses a chart and renders its documentation, writing it to the chart's output files if asked to
+func GenerateChart(opts Options) (Result, error) {
+	return GenerateChartContext(context.Background(), opts)
+}
+
+// GenerateChartContext documents a chart like GenerateChart, giving up once the context is done, e.g. on reaching a
+// deadline. Nothing is written for charts given up on
+func GenerateChartContext(ctx context.Context, opts Options) (Result, error) {
+	if opts.ChartDirectory == "" {
+		return Result{}, fmt.Errorf("no chart directory given to document")
+	}
+
+	chartSettings, err := opts.settings()
+	if err != nil {
+		return Result{}, err
+	}
+
+	// The chart is parsed with the options, and its documentation rendered with the settings it was parsed with
+	ctx = settings.NewContext(ctx, chartSettings)
+
+	// Charts may have changed since the last chart was documented
+	helm.ResetLocalCharts()
+
+	chartDocumentationInfo, err := helm.ParseChartInformationContext(ctx, opts.ChartDirectory)
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to parse chart %s: %w", opts.ChartDirectory, err)
+	}
+
+	documents, err := document.RenderDocumentationContext(ctx, chartDocumentationInfo)
+	if err != nil {
+		return Result{Chart: chartDocumentationInfo}, fmt.Errorf("failed to render documentation of chart %s: %w", opts.ChartDirectory, err)
+	}
+
+	result := Result{Chart: chartDocumentationInfo, Documents: documents}
+	if opts.Write {
+		result.UpdatedFiles, err = document.WriteDocuments(chartDocumentationInfo, documents)
+		if err != nil {
+			return result, fmt.Errorf("failed to write documentation of chart %s: %w", opts.ChartDirectory, err)
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,91 @@
+package docs
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateChart(t *testing.T) {
+	// Settings of viper's global instance, e.g. those of a program embedding helm-docs, are neither read nor changed
+	viper.Set("skip-sections", []string{"values"})
+	defer viper.Set("skip-sections", nil)
+
+	result, err := GenerateChart(Options{ChartDirectory: "../../example-charts/nginx-ingress"})
+	assert.Nil(t, err)
+	assert.Equal(t, "nginx-ingress", result.Chart.Name)
+	assert.Empty(t, result.UpdatedFiles)
+
+	readme, err := ioutil.ReadFile("../../example-charts/nginx-ingress/README.md")
+	assert.Nil(t, err)
+	assert.Len(t, result.Documents, 1)
+	assert.Equal(t, "../../example-charts/nginx-ingress/README.md", result.Documents[0].OutputPath)
+	assert.Equal(t, string(readme), result.Documents[0].Documentation)
+
+	assert.Equal(t, []string{"values"}, viper.GetStringSlice("skip-sections"))
+	assert.False(t, viper.IsSet("sort-values-order"))
+}
+
+func TestGenerateChartSettings(t *testing.T) {
+	result, err := GenerateChart(Options{
+		ChartDirectory: "../../example-charts/nginx-ingress",
+		OutputFile:     "VALUES.md",
+		Settings:       map[string]interface{}{"skip-sections": []string{"values"}},
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "../../example-charts/nginx-ingress/VALUES.md", result.Documents[0].OutputPath)
+	assert.NotContains(t, result.Documents[0].Documentation, "Chart Values")
+
+	_, err = GenerateChart(Options{ChartDirectory: "../../example-charts/missing"})
+	assert.NotNil(t, err)
+
+	_, err = GenerateChart(Options{
+		ChartDirectory: "../../example-charts/nginx-ingress",
+		Settings:       map[string]interface{}{"skip-section": []string{"values"}},
+	})
+
+	assert.EqualError(t, err, "unknown setting skip-section")
+}
+
+func TestGenerateChartConcurrently(t *testing.T) {
+	// Each chart is documented with its own settings, whatever else is being documented at the same time
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		skipValues := i%2 == 0
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			skippedSections := []string{}
+			if skipValues {
+				skippedSections = []string{"values"}
+			}
+
+			result, err := GenerateChart(Options{
+				ChartDirectory: "../../example-charts/nginx-ingress",
+				Settings:       map[string]interface{}{"skip-sections": skippedSections},
+			})
+
+			assert.Nil(t, err)
+			assert.Equal(t, !skipValues, strings.Contains(result.Documents[0].Documentation, "Chart Values"))
+		}()
+	}
+
+	wg.Wait()
+}
+
+func TestGenerateChartContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := GenerateChartContext(ctx, Options{ChartDirectory: "../../example-charts/nginx-ingress", Write: true})
+	assert.True(t, errors.Is(err, context.Canceled))
+}
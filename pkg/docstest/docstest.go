@@ -0,0 +1,109 @@
+// Package docstest helps repositories of charts test the documentation helm-docs generates for them against golden
+// files, so that regressions in their templates or values comments fail their own go tests
+package docstest
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/norwoodj/helm-docs/pkg/docs"
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// UpdateGoldenEnvironmentVariable is the environment variable which, set to true, makes assertions write the
+// documentation generated to their golden files rather than comparing it with them, e.g.
+// HELM_DOCS_UPDATE_GOLDEN=true go test ./...
+const UpdateGoldenEnvironmentVariable = "HELM_DOCS_UPDATE_GOLDEN"
+
+func updateGoldenFiles() bool {
+	update, _ := strconv.ParseBool(os.Getenv(UpdateGoldenEnvironmentVariable))
+	return update
+}
+
+func splitDocumentLines(document string) []string {
+	lines := strings.SplitAfter(document, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	return lines
+}
+
+// AssertChartDocs asserts that the documentation generated for a chart, with the default options, is the same as the
+// contents of a golden file, failing the test with a diff of the two if it isn't
+func AssertChartDocs(t testing.TB, chartDirectory string, goldenFile string) bool {
+	t.Helper()
+	return AssertChartDocsWithOptions(t, docs.Options{ChartDirectory: chartDirectory}, goldenFile)
+}
+
+// AssertChartDocsWithOptions asserts that the documentation generated for a chart with the given options is the same as
+// the contents of a golden file, failing the test with a diff of the two if it isn't. The chart's own output files are
+// never written. Unless the options say otherwise, the date documentation was generated on is left out, so that golden
+// files don't go stale from one day to the next
+func AssertChartDocsWithOptions(t testing.TB, opts docs.Options, goldenFile string) bool {
+	t.Helper()
+
+	settings := map[string]interface{}{"omit-generated-date": true}
+	for k, v := range opts.Settings {
+		settings[k] = v
+	}
+
+	opts.Settings = settings
+	opts.Write = false
+
+	result, err := docs.GenerateChart(opts)
+	if err != nil {
+		t.Errorf("Error generating documentation for chart %s: %s", opts.ChartDirectory, err)
+		return false
+	}
+
+	if len(result.Documents) != 1 {
+		t.Errorf("Chart %s has %d documents rather than one to compare with golden file %s", opts.ChartDirectory, len(result.Documents), goldenFile)
+		return false
+	}
+
+	documentation := result.Documents[0].Documentation
+	if updateGoldenFiles() {
+		if err := os.MkdirAll(filepath.Dir(goldenFile), 0755); err != nil {
+			t.Errorf("Error creating directory of golden file %s: %s", goldenFile, err)
+			return false
+		}
+
+		if err := ioutil.WriteFile(goldenFile, []byte(documentation), 0644); err != nil {
+			t.Errorf("Error writing golden file %s: %s", goldenFile, err)
+			return false
+		}
+
+		return true
+	}
+
+	golden, err := ioutil.ReadFile(goldenFile)
+	if err != nil {
+		t.Errorf("Error reading golden file %s, run with %s=true to create it: %s", goldenFile, UpdateGoldenEnvironmentVariable, err)
+		return false
+	}
+
+	if string(golden) == documentation {
+		return true
+	}
+
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        splitDocumentLines(string(golden)),
+		B:        splitDocumentLines(documentation),
+		FromFile: goldenFile,
+		ToFile:   opts.ChartDirectory,
+		Context:  3,
+	})
+
+	if err != nil {
+		t.Errorf("Error diffing documentation for chart %s with golden file %s: %s", opts.ChartDirectory, goldenFile, err)
+		return false
+	}
+
+	t.Errorf("Documentation for chart %s differs from golden file %s, run with %s=true to update it:\n%s", opts.ChartDirectory, goldenFile, UpdateGoldenEnvironmentVariable, diff)
+	return false
+}
@@ -0,0 +1,47 @@
+package docstest
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// recordingT records the failures of assertions, which would otherwise fail the test checking them
+type recordingT struct {
+	testing.TB
+	failures []string
+}
+
+func (t *recordingT) Errorf(format string, args ...interface{}) {
+	t.failures = append(t.failures, fmt.Sprintf(format, args...))
+}
+
+func TestAssertChartDocs(t *testing.T) {
+	assert.True(t, AssertChartDocs(t, "../../example-charts/nginx-ingress", "../../example-charts/nginx-ingress/README.md"))
+
+	goldenDirectory, err := ioutil.TempDir("", "helm-docs-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(goldenDirectory)
+
+	goldenFile := path.Join(goldenDirectory, "README.md")
+	recorder := &recordingT{TB: t}
+	assert.False(t, AssertChartDocs(recorder, "../../example-charts/nginx-ingress", goldenFile))
+	assert.Len(t, recorder.failures, 1)
+
+	os.Setenv(UpdateGoldenEnvironmentVariable, "true")
+	assert.True(t, AssertChartDocs(t, "../../example-charts/nginx-ingress", goldenFile))
+	os.Unsetenv(UpdateGoldenEnvironmentVariable)
+
+	assert.Nil(t, ioutil.WriteFile(goldenFile, []byte("# nginx-ingress\n"), 0644))
+	recorder = &recordingT{TB: t}
+	assert.False(t, AssertChartDocs(recorder, "../../example-charts/nginx-ingress", goldenFile))
+	assert.Len(t, recorder.failures, 1)
+	assert.Contains(t, recorder.failures[0], "+## Chart Values")
+}
@@ -0,0 +1,95 @@
+package document
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// The prefix of the anchors of value rows, keeping them apart from the anchors markdown renderers give headings
+const valueAnchorPrefix = "values-"
+
+var nonAnchorCharactersPattern = regexp.MustCompile(`[^a-z0-9]+`)
+
+// valueAnchor returns the id of the anchor of a value's row in the values table, derived from its key alone so that
+// links to it stay stable as other values come and go, e.g. "values-image-repository" for image.repository
+func valueAnchor(key string) string {
+	return valueAnchorPrefix + strings.Trim(nonAnchorCharactersPattern.ReplaceAllString(strings.ToLower(key), "-"), "-")
+}
+
+// valueLink returns a markdown link to a value's row in the values table, with the value's key as its text, so that
+// prose sections of a template can refer to values, e.g. {{ valueLink "image.repository" }}
+func valueLink(key string) string {
+	return fmt.Sprintf("[%s](#%s)", key, valueAnchor(key))
+}
+
+// anchorValueRows gives each row of the chart's own values table the anchor it's linked to by, returning the anchors by
+// key. Keys differing only in case or punctuation, e.g. image.tag and image.Tag, would share an anchor, so as with
+// headings, repeated anchors get a numeric suffix, numbered in the order of the keys rather than of the table's rows
+func anchorValueRows(valueRows []valueRow) map[string]string {
+	keys := make([]string, 0, len(valueRows))
+	for _, r := range valueRows {
+		keys = append(keys, r.Key)
+	}
+
+	sort.Strings(keys)
+
+	anchors := make(map[string]string, len(keys))
+	usedAnchors := make(map[string]bool, len(keys))
+	anchorCounts := make(map[string]int)
+
+	for _, k := range keys {
+		if _, ok := anchors[k]; ok {
+			continue
+		}
+
+		baseAnchor := valueAnchor(k)
+		anchor := baseAnchor
+		for usedAnchors[anchor] {
+			anchorCounts[baseAnchor]++
+			anchor = fmt.Sprintf("%s-%d", baseAnchor, anchorCounts[baseAnchor])
+		}
+
+		usedAnchors[anchor] = true
+		anchors[k] = anchor
+	}
+
+	for i := range valueRows {
+		valueRows[i].Anchor = anchors[valueRows[i].Key]
+	}
+
+	return anchors
+}
+
+// valueAnchorFuncMap returns the valueAnchor and valueLink template functions for a chart, which give the anchors of
+// the rows of its values table, suffixes included, falling back on the anchors derived from keys for any other key
+func valueAnchorFuncMap(anchors map[string]string) template.FuncMap {
+	chartValueAnchor := func(key string) string {
+		if anchor, ok := anchors[key]; ok {
+			return anchor
+		}
+
+		return valueAnchor(key)
+	}
+
+	return template.FuncMap{
+		"valueAnchor": chartValueAnchor,
+		"valueLink": func(key string) string {
+			return fmt.Sprintf("[%s](#%s)", key, chartValueAnchor(key))
+		},
+	}
+}
+
+// withoutAnchors copies value rows without their anchors, so that the rows of the values table also listed elsewhere,
+// e.g. in the global values table, don't repeat the ids of the anchors in the values table
+func withoutAnchors(valueRows []valueRow) []valueRow {
+	unanchoredRows := make([]valueRow, len(valueRows))
+	for i, r := range valueRows {
+		r.Anchor = ""
+		unanchoredRows[i] = r
+	}
+
+	return unanchoredRows
+}
@@ -0,0 +1,51 @@
+package document
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValueAnchor(t *testing.T) {
+	assert.Equal(t, "values-image-repository", valueAnchor("image.repository"))
+	assert.Equal(t, "values-tolerations-0-key", valueAnchor("tolerations[0].key"))
+	assert.Equal(t, "values-service-annotations-external-dns-alpha-kubernetes-io-hostname", valueAnchor(`service.annotations."external-dns.alpha.kubernetes.io/hostname"`))
+	assert.Equal(t, "[image.repository](#values-image-repository)", valueLink("image.repository"))
+}
+
+func TestAnchorValueRows(t *testing.T) {
+	rows := []valueRow{{Key: "global.imageRegistry"}, {Key: "replicaCount"}}
+	anchorValueRows(rows)
+	assert.Equal(t, "values-global-imageregistry", rows[0].Anchor)
+	assert.Equal(t, "values-replicacount", rows[1].Anchor)
+}
+
+func TestAnchorValueRowsCollisions(t *testing.T) {
+	rows := []valueRow{{Key: "image.tag"}, {Key: "image.Tag"}, {Key: "a.b.c"}, {Key: "a.b-c"}, {Key: "a.b-c-1"}}
+	anchors := anchorValueRows(rows)
+
+	assert.Equal(t, "values-image-tag", anchors["image.Tag"])
+	assert.Equal(t, "values-image-tag-1", anchors["image.tag"])
+	assert.Equal(t, "values-a-b-c", anchors["a.b-c"])
+	assert.Equal(t, "values-a-b-c-1", anchors["a.b-c-1"])
+	assert.Equal(t, "values-a-b-c-2", anchors["a.b.c"])
+	assert.Equal(t, "values-image-tag-1", rows[0].Anchor)
+	assert.Equal(t, "values-a-b-c-2", rows[2].Anchor)
+
+	funcs := valueAnchorFuncMap(anchors)
+	assert.Equal(t, "[image.tag](#values-image-tag-1)", funcs["valueLink"].(func(string) string)("image.tag"))
+	assert.Equal(t, "values-replicas", funcs["valueAnchor"].(func(string) string)("replicas"))
+}
+
+func TestValueRowsUnderWithoutAnchors(t *testing.T) {
+	rows := []valueRow{{Key: "image.repository"}, {Key: "image.tag"}, {Key: "replicas"}}
+	anchorValueRows(rows)
+
+	selectedRows := valueRowsUnder("image", chartTemplateData{Values: rows})
+	assert.Len(t, selectedRows, 2)
+	for _, r := range selectedRows {
+		assert.Equal(t, "", r.Anchor)
+	}
+
+	assert.Equal(t, "values-image-repository", rows[0].Anchor)
+}
@@ -0,0 +1,80 @@
+package document
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/norwoodj/helm-docs/pkg/helm"
+	"gopkg.in/yaml.v2"
+)
+
+// Chart.yaml annotations maintained for Artifact Hub, see https://artifacthub.io/docs/topics/annotations/helm/
+const (
+	artifactHubChangesAnnotation = "artifacthub.io/changes"
+	artifactHubLinksAnnotation   = "artifacthub.io/links"
+)
+
+type linkTemplateData struct {
+	Name string
+	URL  string `yaml:"url"`
+}
+
+type changeTemplateData struct {
+	Kind        string
+	Description string
+	Links       []linkTemplateData
+}
+
+// getChangesTemplateData parses the changes annotation, a yaml list of the changes in the chart's version. Each change
+// is either a description alone, or a map with a kind (added, changed, deprecated, removed, fixed or security), a
+// description and links
+func getChangesTemplateData(chartDocumentationInfo helm.ChartDocumentationInfo) ([]changeTemplateData, error) {
+	changes := make([]changeTemplateData, 0)
+	annotatedChanges, ok := chartDocumentationInfo.Annotations[artifactHubChangesAnnotation]
+	if !ok {
+		return changes, nil
+	}
+
+	var items []interface{}
+	if err := yaml.Unmarshal([]byte(annotatedChanges), &items); err != nil {
+		return nil, fmt.Errorf("invalid %s annotation, must be a list of changes: %s", artifactHubChangesAnnotation, err)
+	}
+
+	for _, item := range items {
+		if description, ok := item.(string); ok {
+			changes = append(changes, changeTemplateData{Description: description})
+			continue
+		}
+
+		// Changes given as maps are unmarshalled again into their structure
+		itemYaml, err := yaml.Marshal(item)
+		if err != nil {
+			return nil, err
+		}
+
+		var change changeTemplateData
+		if err := yaml.Unmarshal(itemYaml, &change); err != nil {
+			return nil, fmt.Errorf("invalid change in %s annotation: %s", artifactHubChangesAnnotation, err)
+		}
+
+		change.Kind = strings.Title(change.Kind)
+		changes = append(changes, change)
+	}
+
+	return changes, nil
+}
+
+// getLinksTemplateData parses the links annotation, a yaml list of maps with the name and url of each link
+func getLinksTemplateData(chartDocumentationInfo helm.ChartDocumentationInfo) ([]linkTemplateData, error) {
+	links := make([]linkTemplateData, 0)
+	annotatedLinks, ok := chartDocumentationInfo.Annotations[artifactHubLinksAnnotation]
+	if !ok {
+		return links, nil
+	}
+
+	if err := yaml.Unmarshal([]byte(annotatedLinks), &links); err != nil {
+		return nil, fmt.Errorf("invalid %s annotation, must be a list of links with names and urls: %s", artifactHubLinksAnnotation, err)
+	}
+
+	return links, nil
+}
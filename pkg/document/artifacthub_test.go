@@ -0,0 +1,38 @@
+package document
+
+import (
+	"testing"
+
+	"github.com/norwoodj/helm-docs/pkg/helm"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetChangesTemplateData(t *testing.T) {
+	chartDocumentationInfo := helm.ChartDocumentationInfo{
+		ChartMeta: helm.ChartMeta{Annotations: map[string]string{
+			artifactHubChangesAnnotation: `
+- kind: added
+  description: Support for ingress classes
+  links:
+    - name: PR 12
+      url: https://github.com/example/charts/pull/12
+- A plain change
+`,
+		}},
+	}
+
+	changes, err := getChangesTemplateData(chartDocumentationInfo)
+	assert.Nil(t, err)
+	assert.Equal(t, []changeTemplateData{
+		{
+			Kind:        "Added",
+			Description: "Support for ingress classes",
+			Links:       []linkTemplateData{{Name: "PR 12", URL: "https://github.com/example/charts/pull/12"}},
+		},
+		{Description: "A plain change"},
+	}, changes)
+
+	chartDocumentationInfo.Annotations[artifactHubChangesAnnotation] = "kind: added"
+	_, err = getChangesTemplateData(chartDocumentationInfo)
+	assert.NotNil(t, err)
+}
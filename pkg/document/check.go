@@ -0,0 +1,44 @@
+package document
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// ErrDocumentationOutOfDate is returned when checking documentation, rather than generating it, finds that a chart's
+// documentation differs from what would be generated
+var ErrDocumentationOutOfDate = errors.New("documentation is out of date")
+
+func splitDocumentLines(document string) []string {
+	lines := strings.SplitAfter(document, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	return lines
+}
+
+// diffDocumentation compares rendered documentation to the contents of its output file, returning a unified diff of
+// the changes generating it would make to the file, or "" if the file is up to date
+func diffDocumentation(outputPath string, documentation string) (string, error) {
+	existingDocumentation, err := ioutil.ReadFile(outputPath)
+	if err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+
+	if string(existingDocumentation) == documentation {
+		return "", nil
+	}
+
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        splitDocumentLines(string(existingDocumentation)),
+		B:        splitDocumentLines(documentation),
+		FromFile: outputPath,
+		ToFile:   outputPath,
+		Context:  3,
+	})
+}
@@ -0,0 +1,34 @@
+package document
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffDocumentation(t *testing.T) {
+	chartDirectory, err := ioutil.TempDir("", "helm-docs-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(chartDirectory)
+
+	outputPath := filepath.Join(chartDirectory, "README.md")
+	assert.Nil(t, ioutil.WriteFile(outputPath, []byte("# nginx\n\nversion 1.0.0\n"), 0644))
+
+	diff, err := diffDocumentation(outputPath, "# nginx\n\nversion 1.0.0\n")
+	assert.Nil(t, err)
+	assert.Equal(t, "", diff)
+
+	diff, err = diffDocumentation(outputPath, "# nginx\n\nversion 1.1.0\n")
+	assert.Nil(t, err)
+	assert.Equal(t, "--- "+outputPath+"\n+++ "+outputPath+"\n@@ -1,3 +1,3 @@\n # nginx\n \n-version 1.0.0\n+version 1.1.0\n", diff)
+
+	diff, err = diffDocumentation(filepath.Join(chartDirectory, "VALUES.md"), "# values\n")
+	assert.Nil(t, err)
+	assert.Contains(t, diff, "+# values\n")
+}
@@ -0,0 +1,30 @@
+package document
+
+import (
+	"github.com/norwoodj/helm-docs/pkg/helm"
+)
+
+// ValuesDescriptionCoverage computes the percentage of the rows in a chart's values tables that have a description, and
+// lists the keys of those that don't
+func ValuesDescriptionCoverage(chartDocumentationInfo helm.ChartDocumentationInfo) (float64, []string, error) {
+	chartTemplateDataObject, err := getChartTemplateData(chartDocumentationInfo)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	valueRows := chartTemplateDataObject.Values
+	undocumentedKeys := make([]string, 0)
+
+	for _, v := range valueRows {
+		if v.Description == "" {
+			undocumentedKeys = append(undocumentedKeys, v.Key)
+		}
+	}
+
+	if len(valueRows) == 0 {
+		return 100, undocumentedKeys, nil
+	}
+
+	documentedCount := len(valueRows) - len(undocumentedKeys)
+	return 100 * float64(documentedCount) / float64(len(valueRows)), undocumentedKeys, nil
+}
@@ -0,0 +1,39 @@
+package document
+
+import (
+	"testing"
+
+	"github.com/norwoodj/helm-docs/pkg/helm"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValuesDescriptionCoverage(t *testing.T) {
+	chartDocumentationInfo := helm.ChartDocumentationInfo{
+		ChartValues: parseYamlValues(`
+global:
+  imageRegistry: ""
+image:
+  repository: nginx
+  tag: latest
+replicaCount: 1
+		`),
+		ChartValuesDescriptions: map[string]helm.ChartValueDescription{
+			"image.repository": {Description: "the image repository"},
+			"replicaCount":     {Description: "number of replicas"},
+		},
+	}
+
+	coverage, undocumentedKeys, err := ValuesDescriptionCoverage(chartDocumentationInfo)
+
+	assert.Nil(t, err)
+	assert.Equal(t, float64(50), coverage)
+	assert.Equal(t, []string{"global.imageRegistry", "image.tag"}, undocumentedKeys)
+}
+
+func TestValuesDescriptionCoverageNoValues(t *testing.T) {
+	coverage, undocumentedKeys, err := ValuesDescriptionCoverage(helm.ChartDocumentationInfo{})
+
+	assert.Nil(t, err)
+	assert.Equal(t, float64(100), coverage)
+	assert.Len(t, undocumentedKeys, 0)
+}
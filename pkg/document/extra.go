@@ -0,0 +1,56 @@
+package document
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v2"
+)
+
+// setExtraValue sets a value at a dotted key path in the extra template data, creating the maps along the way
+func setExtraValue(extra map[interface{}]interface{}, keyPath string, value interface{}) {
+	keys := strings.Split(keyPath, ".")
+	current := extra
+
+	for _, k := range keys[:len(keys)-1] {
+		next, ok := current[k].(map[interface{}]interface{})
+		if !ok {
+			next = make(map[interface{}]interface{})
+			current[k] = next
+		}
+
+		current = next
+	}
+
+	current[keys[len(keys)-1]] = value
+}
+
+// getExtraTemplateData reads the user supplied data exposed to templates as .Extra, from the extra values file,
+// overridden by any key=value pairs given with --set
+func getExtraTemplateData(chartSettings *viper.Viper) (map[interface{}]interface{}, error) {
+	extra := make(map[interface{}]interface{})
+
+	if extraValuesFile := chartSettings.GetString("extra-values"); extraValuesFile != "" {
+		contents, err := ioutil.ReadFile(extraValuesFile)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := yaml.Unmarshal(contents, &extra); err != nil {
+			return nil, fmt.Errorf("failed to parse extra values file %s: %s", extraValuesFile, err)
+		}
+	}
+
+	for _, s := range chartSettings.GetStringSlice("set") {
+		parts := strings.SplitN(s, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf("invalid --set value %q, must be of the form key=value", s)
+		}
+
+		setExtraValue(extra, parts[0], parts[1])
+	}
+
+	return extra, nil
+}
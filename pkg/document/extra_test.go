@@ -0,0 +1,32 @@
+package document
+
+import (
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtraTemplateDataFromSet(t *testing.T) {
+	viper.Set("set", []string{"support.channel=#helm-charts", "support.url=https://example.com/?a=b", "environment=prod"})
+	defer viper.Set("set", nil)
+
+	extra, err := getExtraTemplateData(viper.GetViper())
+
+	assert.Nil(t, err)
+	assert.Equal(t, map[interface{}]interface{}{
+		"environment": "prod",
+		"support": map[interface{}]interface{}{
+			"channel": "#helm-charts",
+			"url":     "https://example.com/?a=b",
+		},
+	}, extra)
+}
+
+func TestExtraTemplateDataInvalidSet(t *testing.T) {
+	viper.Set("set", []string{"environment"})
+	defer viper.Set("set", nil)
+
+	_, err := getExtraTemplateData(viper.GetViper())
+	assert.NotNil(t, err)
+}
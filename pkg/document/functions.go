@@ -0,0 +1,136 @@
+package document
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/norwoodj/helm-docs/pkg/settings"
+	"gopkg.in/yaml.v2"
+)
+
+// toYaml renders a value as a yaml document, in the same way as the helm function of the same name
+func toYaml(v interface{}) string {
+	data, err := yaml.Marshal(v)
+	if err != nil {
+		// Swallow errors inside of a template, as helm does
+		return ""
+	}
+
+	return strings.TrimSuffix(string(data), "\n")
+}
+
+// fromYaml parses a yaml document into a map, in the same way as the helm function of the same name. Errors are
+// reported under the Error key of the map
+func fromYaml(str string) map[string]interface{} {
+	m := make(map[interface{}]interface{})
+	if err := yaml.Unmarshal([]byte(str), &m); err != nil {
+		return map[string]interface{}{"Error": err.Error()}
+	}
+
+	return convertHelmValuesToJsonable(m).(jsonableMap)
+}
+
+// toJson renders a value as json. Unlike the sprig function of the same name, it handles the maps with non-string keys
+// that parsed values are made of
+func toJson(v interface{}) string {
+	data, err := json.Marshal(convertHelmValuesToJsonable(v))
+	if err != nil {
+		return ""
+	}
+
+	return string(data)
+}
+
+func toPrettyJson(v interface{}) string {
+	data, err := json.MarshalIndent(convertHelmValuesToJsonable(v), "", "  ")
+	if err != nil {
+		return ""
+	}
+
+	return string(data)
+}
+
+// fromJson parses a json document into a map, reporting errors under the Error key of the map as fromYaml does
+func fromJson(str string) map[string]interface{} {
+	m := make(map[string]interface{})
+	if err := json.Unmarshal([]byte(str), &m); err != nil {
+		return map[string]interface{}{"Error": err.Error()}
+	}
+
+	return m
+}
+
+// headingWithOffset returns a function returning the markdown prefix of a heading of the given level, shifted by the
+// heading offset so that documentation can be nested under the headings of another document
+func headingWithOffset(offset int) func(int) string {
+	return func(level int) string {
+		level += offset
+		if level < 1 {
+			level = 1
+		} else if level > 6 {
+			level = 6
+		}
+
+		return strings.Repeat("#", level)
+	}
+}
+
+// heading returns the markdown prefix of a heading of the given level, shifted by the configured heading offset
+func heading(level int) string {
+	return headingWithOffset(settings.GetInt("heading-offset"))(level)
+}
+
+// chartFileReader returns a function reading files from within a chart's directory, for templates to embed e.g.
+// example values files. Paths are relative to the chart directory, and may not lead outside of it, even via symlinks
+func chartFileReader(chartDirectory string) func(string) (string, error) {
+	return func(filename string) (string, error) {
+		if filepath.IsAbs(filename) {
+			return "", fmt.Errorf("readFile: path %s must be relative to the chart directory", filename)
+		}
+
+		chartPath, err := filepath.EvalSymlinks(chartDirectory)
+		if err != nil {
+			return "", err
+		}
+
+		filePath, err := filepath.EvalSymlinks(filepath.Join(chartDirectory, filename))
+		if err != nil {
+			return "", fmt.Errorf("readFile: %s", err)
+		}
+
+		relativePath, err := filepath.Rel(chartPath, filePath)
+		if err != nil || relativePath == ".." || strings.HasPrefix(relativePath, ".."+string(filepath.Separator)) {
+			return "", fmt.Errorf("readFile: path %s is outside of the chart directory", filename)
+		}
+
+		contents, err := ioutil.ReadFile(filePath)
+		if err != nil {
+			return "", fmt.Errorf("readFile: %s", err)
+		}
+
+		return string(contents), nil
+	}
+}
+
+func helmDocsFuncMap() template.FuncMap {
+	return template.FuncMap{
+		"toYaml":              toYaml,
+		"fromYaml":            fromYaml,
+		"toJson":              toJson,
+		"toPrettyJson":        toPrettyJson,
+		"fromJson":            fromJson,
+		"codeSpan":            codeSpan,
+		"escapeTableCell":     escapeTableCell,
+		"heading":             heading,
+		"valueRowsUnder":      valueRowsUnder,
+		"valueSections":       valueSections,
+		"extraValueRows":      extraValueRows,
+		"withoutGlobalValues": withoutGlobalValues,
+		"valueAnchor":         valueAnchor,
+		"valueLink":           valueLink,
+	}
+}
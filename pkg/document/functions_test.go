@@ -0,0 +1,58 @@
+package document
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChartFileReader(t *testing.T) {
+	parentDirectory, err := ioutil.TempDir("", "helm-docs-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(parentDirectory)
+
+	chartDirectory := filepath.Join(parentDirectory, "chart")
+	assert.Nil(t, os.MkdirAll(filepath.Join(chartDirectory, "examples"), 0755))
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(chartDirectory, "examples", "values.yaml"), []byte("replicas: 3\n"), 0644))
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(parentDirectory, "secret"), []byte("secret\n"), 0644))
+	assert.Nil(t, os.Symlink(filepath.Join(parentDirectory, "secret"), filepath.Join(chartDirectory, "link")))
+
+	readFile := chartFileReader(chartDirectory)
+
+	contents, err := readFile("examples/values.yaml")
+	assert.Nil(t, err)
+	assert.Equal(t, "replicas: 3\n", contents)
+
+	for _, f := range []string{"../secret", "examples/../../secret", "link", filepath.Join(parentDirectory, "secret"), "missing"} {
+		_, err = readFile(f)
+		assert.NotNil(t, err, f)
+	}
+}
+
+func TestJsonAndYamlFunctions(t *testing.T) {
+	values := parseYamlValues(`
+ingress:
+  hosts:
+    - name: example.com
+  annotations: {}
+	`)
+
+	assert.Equal(t, `{"ingress":{"annotations":{},"hosts":[{"name":"example.com"}]}}`, toJson(values))
+	assert.Equal(t, "ingress:\n  annotations: {}\n  hosts:\n  - name: example.com", toYaml(values))
+	assert.Equal(t, map[string]interface{}{"a": map[string]interface{}{"b": 1}}, fromYaml("a:\n  b: 1\n"))
+	assert.Equal(t, map[string]interface{}{"a": []interface{}{1.0}}, fromJson(`{"a": [1]}`))
+	assert.Contains(t, fromYaml("a: [")["Error"], "yaml")
+}
+
+func TestTranslator(t *testing.T) {
+	assert.Equal(t, "Chart-Werte", translator("de")("Chart Values"))
+	assert.Equal(t, "Valeurs du sous-chart redis", translator("fr")("%s Subchart Values", "redis"))
+	assert.Equal(t, "Chart Values (values-prod.yaml)", translator("en")("Chart Values (%s)", "values-prod.yaml"))
+	assert.Equal(t, "Untranslated", translator("ja")("Untranslated"))
+}
@@ -1,57 +1,318 @@
 package document
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/norwoodj/helm-docs/pkg/helm"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 )
 
-func getOutputFile(chartDirectory string, dryRun bool) (*os.File, error) {
-	if dryRun {
-		return os.Stdout, nil
+// getOutputDirectory returns the directory output file paths are relative to, the chart's directory. The documentation
+// of a packaged chart goes in a directory named after the archive, e.g. dist/app-1.0.0 for dist/app-1.0.0.tgz
+func getOutputDirectory(chartDocumentationInfo helm.ChartDocumentationInfo) string {
+	if helm.IsChartArchive(chartDocumentationInfo.ChartDirectory) {
+		return strings.TrimSuffix(chartDocumentationInfo.ChartDirectory, ".tgz")
 	}
 
-	outputFile := viper.GetString("output-file")
-	f, err := os.Create(fmt.Sprintf("%s/%s", chartDirectory, outputFile))
+	return chartDocumentationInfo.ChartDirectory
+}
+
+// getOutputPath returns the path of the file a chart's documentation is written to, which is set by the chart's output
+// file annotation if present, or otherwise by the command line
+func getOutputPath(chartDocumentationInfo helm.ChartDocumentationInfo) string {
+	outputFile := chartSetting(chartDocumentationInfo, outputFileAnnotation, "output-file")
+	return filepath.Join(getOutputDirectory(chartDocumentationInfo), outputFile)
+}
+
+// documentationOutput is a file to which a chart's documentation is written, rendered from the given template files
+type documentationOutput struct {
+	templateFiles []string
+	outputPath    string
+}
 
+// getDocumentationOutputs returns the files to write a chart's documentation to. By default that is the chart's output
+// file, rendered from its template files, but any number of template files may instead be mapped to output files of
+// their own with the template output option, e.g. "VALUES.md.gotmpl=docs/values.md". Mapped template files that a chart
+// doesn't have are skipped
+func getDocumentationOutputs(chartDocumentationInfo helm.ChartDocumentationInfo) ([]documentationOutput, error) {
+	renderer, err := getRenderer(chartDocumentationInfo.Settings())
 	if err != nil {
 		return nil, err
 	}
 
-	return f, err
+	templateOutputs := chartDocumentationInfo.Settings().GetStringSlice("template-output")
+	if len(templateOutputs) == 0 {
+		return []documentationOutput{{
+			templateFiles: chartSettingList(chartDocumentationInfo, templateFileAnnotation, "template-file"),
+			outputPath:    rendererOutputPath(renderer, getOutputPath(chartDocumentationInfo)),
+		}}, nil
+	}
+
+	outputs := make([]documentationOutput, 0, len(templateOutputs))
+	for _, templateOutput := range templateOutputs {
+		mapping := strings.SplitN(templateOutput, "=", 2)
+		if len(mapping) != 2 || mapping[0] == "" || mapping[1] == "" {
+			return nil, fmt.Errorf("invalid template output %s, must be of the form template-file=output-file", templateOutput)
+		}
+
+		if _, err := os.Stat(templateFilePath(chartDocumentationInfo.ChartDirectory, mapping[0])); os.IsNotExist(err) {
+			log.Debugf("Did not find template file %s for chart %s", mapping[0], chartDocumentationInfo.ChartDirectory)
+			continue
+		}
+
+		outputs = append(outputs, documentationOutput{
+			templateFiles: []string{mapping[0]},
+			outputPath:    rendererOutputPath(renderer, filepath.Join(getOutputDirectory(chartDocumentationInfo), mapping[1])),
+		})
+	}
+
+	return outputs, nil
 }
 
-func PrintDocumentation(chartDocumentationInfo helm.ChartDocumentationInfo, dryRun bool) {
-	log.Infof("Generating README Documentation for chart %s", chartDocumentationInfo.ChartDirectory)
+// linkDependencyDocumentation returns a copy of a chart's dependencies in which those on charts found in the chart
+// search roots link, relative to the output file being rendered, to the first output file of the chart they're on
+func linkDependencyDocumentation(chartSettings *viper.Viper, dependencies []helm.ChartRequirementsItem, outputPath string) []helm.ChartRequirementsItem {
+	linked := make([]helm.ChartRequirementsItem, len(dependencies))
+	copy(linked, dependencies)
+
+	for i, d := range linked {
+		if d.LocalChart == nil {
+			continue
+		}
+
+		dependencyOutputs, err := getDocumentationOutputs(helm.ChartDocumentationInfo{
+			ChartMeta:      d.LocalChart.ChartMeta,
+			ChartDirectory: d.LocalChart.Directory,
+		}.WithSettings(chartSettings))
+
+		if err != nil || len(dependencyOutputs) == 0 {
+			continue
+		}
+
+		link, err := filepath.Rel(filepath.Dir(outputPath), dependencyOutputs[0].outputPath)
+		if err != nil {
+			log.Debugf("Error linking dependency %s to its documentation at %s: %s", d.Name, dependencyOutputs[0].outputPath, err)
+			continue
+		}
+
+		linked[i].DocumentationLink = filepath.ToSlash(link)
+	}
+
+	return linked
+}
 
-	chartDocumentationTemplate, err := newChartDocumentationTemplate(chartDocumentationInfo)
+// writeDocumentation writes rendered documentation to its output file, or to stdout on dry runs
+func writeDocumentation(outputPath string, documentation string, dryRun bool) error {
+	// On dry runs the documentation of every chart goes to stdout, so each is headed with the file it would be written
+	// to and followed by a blank line, in the style of tail and head run on multiple files
+	if dryRun {
+		_, err := fmt.Fprintf(os.Stdout, "==> %s <==\n%s\n", outputPath, documentation)
+		return err
+	}
+
+	// Unchanged files aren't written again, so that watching charts, whose values schema may be generated, doesn't see
+	// a change every time their documentation is generated
+	if existingDocumentation, err := ioutil.ReadFile(outputPath); err == nil && string(existingDocumentation) == documentation {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(outputPath, []byte(documentation), 0644)
+}
+
+// RenderedDocument is documentation of a chart rendered in memory, along with the file it is written to
+type RenderedDocument struct {
+	OutputPath    string
+	Documentation string
+}
+
+// RenderDocumentation renders the documentation of a chart without writing it anywhere, returning a document for each
+// of the chart's output files. Errors are logged as well as returned
+func RenderDocumentation(chartDocumentationInfo helm.ChartDocumentationInfo) ([]RenderedDocument, error) {
+	return RenderDocumentationContext(context.Background(), chartDocumentationInfo)
+}
+
+// RenderDocumentationContext renders the documentation of a chart like RenderDocumentation, giving up once the context
+// is done, e.g. on reaching a timeout. The context is checked before each stage of rendering, and each document
+func RenderDocumentationContext(ctx context.Context, chartDocumentationInfo helm.ChartDocumentationInfo) ([]RenderedDocument, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	documents, err := renderDocumentation(ctx, chartDocumentationInfo)
+	if ctx.Err() != nil {
+		log.Warnf("Gave up generating documentation for chart %s: %s", chartDocumentationInfo.ChartDirectory, ctx.Err())
+		return nil, fmt.Errorf("gave up rendering documentation for chart %s: %w", chartDocumentationInfo.ChartDirectory, ctx.Err())
+	}
+
+	return documents, err
+}
+
+func renderDocumentation(ctx context.Context, chartDocumentationInfo helm.ChartDocumentationInfo) ([]RenderedDocument, error) {
+	outputs, err := getDocumentationOutputs(chartDocumentationInfo)
 	if err != nil {
-		log.Warnf("Error generating gotemplates for chart %s: %s", chartDocumentationInfo.ChartDirectory, err)
-		return
+		log.Warnf("Error determining output files for chart %s: %s", chartDocumentationInfo.ChartDirectory, err)
+		return nil, err
+	}
+
+	renderer, err := getRenderer(chartDocumentationInfo.Settings())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
 	}
 
 	chartTemplateDataObject, err := getChartTemplateData(chartDocumentationInfo)
 	if err != nil {
 		log.Warnf("Error generating template data for chart %s: %s", chartDocumentationInfo.ChartDirectory, err)
-		return
+		return nil, err
 	}
 
-	outputFile, err := getOutputFile(chartDocumentationInfo.ChartDirectory, dryRun)
+	documents := make([]RenderedDocument, 0, len(outputs))
+	for _, output := range outputs {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		chartDocumentationTemplate, err := newChartDocumentationTemplate(chartDocumentationInfo, output.templateFiles)
+		if err != nil {
+			log.Warnf("Error generating gotemplates for chart %s: %s", chartDocumentationInfo.ChartDirectory, err)
+			return nil, err
+		}
+
+		chartDocumentationTemplate.Funcs(valueAnchorFuncMap(chartTemplateDataObject.valueAnchors))
+		chartDocumentationTemplate.Funcs(extraValuesFuncMap(chartTemplateDataObject.ExtraValues))
+		chartTemplateDataObject.Dependencies = linkDependencyDocumentation(chartDocumentationInfo.Settings(), chartDocumentationInfo.Dependencies, output.outputPath)
+
+		var renderedDocumentation bytes.Buffer
+		err = chartDocumentationTemplate.Execute(&renderedDocumentation, chartTemplateDataObject)
+		if err != nil {
+			log.Warnf("Error generating documentation for chart %s: %s", chartDocumentationInfo.ChartDirectory, err)
+			return nil, err
+		}
+
+		documentation, err := renderer.Render(chartDocumentationInfo, normalizeTrailingWhitespace(insertTableOfContents(renderedDocumentation.String())))
+		if err != nil {
+			log.Warnf("Error rendering documentation for chart %s as %s: %s", chartDocumentationInfo.ChartDirectory, renderer.Name(), err)
+			return nil, err
+		}
+
+		if chartDocumentationInfo.Settings().GetBool("inject") {
+			documentation, err = injectDocumentationIntoFile(output.outputPath, documentation)
+			if err != nil {
+				log.Warnf("Error injecting documentation for chart %s into %s: %s", chartDocumentationInfo.ChartDirectory, output.outputPath, err)
+				return nil, err
+			}
+		}
+
+		documents = append(documents, RenderedDocument{OutputPath: output.outputPath, Documentation: documentation})
+	}
+
+	if chartDocumentationInfo.Settings().GetBool("generate-values-schema") {
+		valuesSchema, err := renderValuesSchema(chartDocumentationInfo)
+		if err != nil {
+			log.Warnf("Error generating the values schema of chart %s: %s", chartDocumentationInfo.ChartDirectory, err)
+			return nil, err
+		}
+
+		documents = append(documents, valuesSchema)
+	}
+
+	return documents, nil
+}
+
+// UpdateDocumentation renders the documentation of a chart and writes it to those of the chart's output files whose
+// contents differ, returning the paths of the files it updated. Errors are logged as well as returned
+func UpdateDocumentation(chartDocumentationInfo helm.ChartDocumentationInfo) ([]string, error) {
+	log.Infof("Updating README Documentation for chart %s", chartDocumentationInfo.ChartDirectory)
+
+	documents, err := RenderDocumentation(chartDocumentationInfo)
 	if err != nil {
-		log.Warnf("Could not open chart README file %s, skipping chart", filepath.Join(chartDocumentationInfo.ChartDirectory, "README.md"))
-		return
+		return nil, err
 	}
 
-	if !dryRun {
-		defer outputFile.Close()
+	return WriteDocuments(chartDocumentationInfo, documents)
+}
+
+// WriteDocuments writes the documents rendered for a chart to those of its output files whose contents differ,
+// returning the paths of the files it updated. Errors are logged as well as returned
+func WriteDocuments(chartDocumentationInfo helm.ChartDocumentationInfo, documents []RenderedDocument) ([]string, error) {
+	updatedPaths := make([]string, 0)
+	for _, d := range documents {
+		existingDocumentation, err := ioutil.ReadFile(d.OutputPath)
+		if err == nil && string(existingDocumentation) == d.Documentation {
+			continue
+		}
+
+		if err := writeDocumentation(d.OutputPath, d.Documentation, false); err != nil {
+			log.Warnf("Error writing documentation for chart %s to %s: %s", chartDocumentationInfo.ChartDirectory, d.OutputPath, err)
+			return updatedPaths, err
+		}
+
+		updatedPaths = append(updatedPaths, d.OutputPath)
 	}
 
-	err = chartDocumentationTemplate.Execute(outputFile, chartTemplateDataObject)
+	return updatedPaths, nil
+}
+
+// PrintDocumentation renders the documentation of a chart and writes it to the chart's output files, or to stdout on dry
+// runs. When checking documentation, stale output files are diffed instead and ErrDocumentationOutOfDate returned.
+// Errors are logged as well as returned
+func PrintDocumentation(chartDocumentationInfo helm.ChartDocumentationInfo, dryRun bool) error {
+	return PrintDocumentationContext(context.Background(), chartDocumentationInfo, dryRun)
+}
+
+// PrintDocumentationContext renders and writes the documentation of a chart like PrintDocumentation, giving up before
+// writing anything if rendering doesn't finish before the context is done
+func PrintDocumentationContext(ctx context.Context, chartDocumentationInfo helm.ChartDocumentationInfo, dryRun bool) error {
+	log.Infof("Generating README Documentation for chart %s", chartDocumentationInfo.ChartDirectory)
+
+	documents, err := RenderDocumentationContext(ctx, chartDocumentationInfo)
 	if err != nil {
-		log.Warnf("Error generating documentation for chart %s: %s", chartDocumentationInfo.ChartDirectory, err)
+		return err
+	}
+
+	outOfDate := false
+	for _, d := range documents {
+		// When checking documentation, the output file is compared against rather than written to
+		if chartDocumentationInfo.Settings().GetBool("check") {
+			diff, err := diffDocumentation(d.OutputPath, d.Documentation)
+			if err != nil {
+				log.Warnf("Error checking documentation for chart %s against %s: %s", chartDocumentationInfo.ChartDirectory, d.OutputPath, err)
+				return err
+			}
+
+			if diff != "" {
+				log.Errorf("Documentation for chart %s in %s is out of date", chartDocumentationInfo.ChartDirectory, d.OutputPath)
+				fmt.Print(diff)
+				outOfDate = true
+			}
+
+			continue
+		}
+
+		err = writeDocumentation(d.OutputPath, d.Documentation, dryRun)
+		if err != nil {
+			log.Warnf("Error writing documentation for chart %s to %s: %s", chartDocumentationInfo.ChartDirectory, d.OutputPath, err)
+			return err
+		}
 	}
+
+	if outOfDate {
+		return ErrDocumentationOutOfDate
+	}
+
+	return nil
 }
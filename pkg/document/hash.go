@@ -0,0 +1,178 @@
+package document
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/norwoodj/helm-docs/pkg/helm"
+)
+
+// Settings which don't affect the documentation generated, and so are left out of its hash
+var unhashedSettings = []string{"cache-file", "check", "config-file", "dry-run", "log-level", "watch"}
+
+// hashFile adds a file's path and contents to a hash, or just its path if the file doesn't exist
+func hashFile(h hash.Hash, filePath string) error {
+	contents, err := ioutil.ReadFile(filePath)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	fmt.Fprintf(h, "%s\x00%d\x00", filePath, len(contents))
+	h.Write(contents)
+	return nil
+}
+
+// hashDirectory adds the files in a directory and its subdirectories to a hash, other than those with the names of
+// output files, and the file the hashes are cached in
+func hashDirectory(h hash.Hash, directory string, outputFileNames map[string]bool, cacheFile string) error {
+	return filepath.Walk(directory, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() || outputFileNames[info.Name()] {
+			return nil
+		}
+
+		if absolutePath, _ := filepath.Abs(path); absolutePath == cacheFile {
+			return nil
+		}
+
+		return hashFile(h, path)
+	})
+}
+
+// The information about a chart that isn't read from its files, but from git, GitHub, OCI registries or other charts,
+// along with the date the documentation is generated on, all of which the documentation can change with
+type chartExternalInputs struct {
+	GitMetadata   helm.ChartGitMetadata
+	Changelog     []helm.ChartChangelogRelease
+	ValuesDiff    helm.ChartValuesDiff
+	Maintainers   []helm.ChartMetaMaintainer
+	Dependencies  []dependencyExternalInputs
+	GeneratedDate string
+}
+
+type dependencyExternalInputs struct {
+	Name        string
+	Description string
+	AppVersion  string
+	LocalChart  *helm.LocalChart
+}
+
+// ChartDocumentationHash hashes everything a chart's documentation is generated from: the files in the chart's
+// directory and in those of its file:// dependencies, its template files, the shared templates, what's known of the
+// chart from git, GitHub, OCI registries and the other charts found, the date it's generated on, the options helm-docs
+// is run with and its version. The chart's output files are hashed too, so that documentation which has been deleted
+// or edited since it was generated changes the hash. Comparing the hash with that from when the documentation was last
+// generated tells whether it needs to be generated again
+func ChartDocumentationHash(chartDocumentationInfo helm.ChartDocumentationInfo) (string, error) {
+	outputs, err := getDocumentationOutputs(chartDocumentationInfo)
+	if err != nil {
+		return "", err
+	}
+
+	// Output files are hashed separately, and skipped wherever they're found in the chart directory, so that the output
+	// files of subcharts, which may be generated during the same run, don't change the hash
+	outputFileNames := make(map[string]bool)
+	for _, o := range outputs {
+		outputFileNames[filepath.Base(o.outputPath)] = true
+	}
+
+	// The file the hashes are cached in may itself be in the chart directory
+	cacheFile, _ := filepath.Abs(chartDocumentationInfo.Settings().GetString("cache-file"))
+
+	h := sha256.New()
+	if err := hashDirectory(h, chartDocumentationInfo.ChartDirectory, outputFileNames, cacheFile); err != nil {
+		return "", err
+	}
+
+	externalInputs := chartExternalInputs{
+		GitMetadata:   chartDocumentationInfo.ChartGitMetadata,
+		Changelog:     chartDocumentationInfo.ChartChangelog,
+		ValuesDiff:    chartDocumentationInfo.ChartValuesDiff,
+		Maintainers:   chartDocumentationInfo.Maintainers,
+		Dependencies:  make([]dependencyExternalInputs, 0, len(chartDocumentationInfo.Dependencies)),
+		GeneratedDate: getGeneratedDate(chartDocumentationInfo.Settings()),
+	}
+
+	for _, d := range chartDocumentationInfo.Dependencies {
+		externalInputs.Dependencies = append(externalInputs.Dependencies, dependencyExternalInputs{
+			Name:        d.Name,
+			Description: d.Description,
+			AppVersion:  d.AppVersion,
+			LocalChart:  d.LocalChart,
+		})
+
+		// The values of file:// dependencies are documented from their directories, outside of the chart's own
+		if !strings.HasPrefix(d.Repository, "file://") || helm.IsChartArchive(chartDocumentationInfo.ChartDirectory) {
+			continue
+		}
+
+		dependencyDirectory := strings.TrimPrefix(d.Repository, "file://")
+		if !filepath.IsAbs(dependencyDirectory) {
+			dependencyDirectory = filepath.Join(chartDocumentationInfo.ChartDirectory, dependencyDirectory)
+		}
+
+		fmt.Fprintf(h, "%s\x00", dependencyDirectory)
+		if err := hashDirectory(h, dependencyDirectory, outputFileNames, cacheFile); err != nil && !os.IsNotExist(err) {
+			return "", err
+		}
+	}
+
+	externalInputsJSON, err := json.Marshal(externalInputs)
+	if err != nil {
+		return "", err
+	}
+
+	h.Write(externalInputsJSON)
+
+	for _, o := range outputs {
+		for _, t := range o.templateFiles {
+			if err := hashFile(h, templateFilePath(chartDocumentationInfo.ChartDirectory, t)); err != nil {
+				return "", err
+			}
+		}
+
+		if err := hashFile(h, o.outputPath); err != nil {
+			return "", err
+		}
+	}
+
+	sharedTemplates, err := getSharedTemplates(chartDocumentationInfo.Settings())
+	if err != nil {
+		return "", err
+	}
+
+	for _, t := range sharedTemplates {
+		fmt.Fprintf(h, "%d\x00%s", len(t), t)
+	}
+
+	if extraValuesFile := chartDocumentationInfo.Settings().GetString("extra-values"); extraValuesFile != "" {
+		if err := hashFile(h, extraValuesFile); err != nil {
+			return "", err
+		}
+	}
+
+	// Maps are marshalled with sorted keys, so the same settings always hash the same
+	hashedSettings := chartDocumentationInfo.Settings().AllSettings()
+	for _, s := range unhashedSettings {
+		delete(hashedSettings, s)
+	}
+
+	settingsJSON, err := json.Marshal(hashedSettings)
+	if err != nil {
+		return "", err
+	}
+
+	h.Write(settingsJSON)
+	fmt.Fprintf(h, "\x00%s", helmDocsVersion())
+
+	return fmt.Sprintf("%x", h.Sum(nil)), nil
+}
@@ -0,0 +1,106 @@
+package document
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/norwoodj/helm-docs/pkg/helm"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChartDocumentationHash(t *testing.T) {
+	chartDirectory, err := ioutil.TempDir("", "helm-docs-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(chartDirectory)
+
+	viper.Set("output-file", "README.md")
+	defer viper.Set("output-file", nil)
+
+	chartDocumentationInfo := helm.ChartDocumentationInfo{ChartDirectory: chartDirectory}
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(chartDirectory, "Chart.yaml"), []byte("name: nginx\n"), 0644))
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(chartDirectory, "values.yaml"), []byte("replicas: 1\n"), 0644))
+
+	hash, err := ChartDocumentationHash(chartDocumentationInfo)
+	assert.Nil(t, err)
+
+	unchangedHash, err := ChartDocumentationHash(chartDocumentationInfo)
+	assert.Nil(t, err)
+	assert.Equal(t, hash, unchangedHash)
+
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(chartDirectory, "values.yaml"), []byte("replicas: 2\n"), 0644))
+	valuesChangedHash, err := ChartDocumentationHash(chartDocumentationInfo)
+	assert.Nil(t, err)
+	assert.NotEqual(t, hash, valuesChangedHash)
+
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(chartDirectory, "README.md"), []byte("# nginx\n"), 0644))
+	outputChangedHash, err := ChartDocumentationHash(chartDocumentationInfo)
+	assert.Nil(t, err)
+	assert.NotEqual(t, valuesChangedHash, outputChangedHash)
+}
+
+func TestChartDocumentationHashExternalInputs(t *testing.T) {
+	chartsDirectory, err := ioutil.TempDir("", "helm-docs-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(chartsDirectory)
+
+	viper.Set("output-file", "README.md")
+	viper.Set("generated-date", "2026-10-15")
+	defer viper.Set("output-file", nil)
+	defer viper.Set("generated-date", nil)
+
+	chartDirectory := filepath.Join(chartsDirectory, "app")
+	assert.Nil(t, os.MkdirAll(chartDirectory, 0755))
+	assert.Nil(t, os.MkdirAll(filepath.Join(chartsDirectory, "common"), 0755))
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(chartDirectory, "Chart.yaml"), []byte("name: app\n"), 0644))
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(chartsDirectory, "common", "values.yaml"), []byte("labels: {}\n"), 0644))
+
+	chartDocumentationInfo := helm.ChartDocumentationInfo{
+		ChartDirectory:    chartDirectory,
+		ChartMeta:         helm.ChartMeta{Maintainers: []helm.ChartMetaMaintainer{{Name: "John Norwood"}}},
+		ChartRequirements: helm.ChartRequirements{Dependencies: []helm.ChartRequirementsItem{{Name: "common", Repository: "file://../common"}}},
+	}
+
+	hash, err := ChartDocumentationHash(chartDocumentationInfo)
+	assert.Nil(t, err)
+
+	hashes := map[string]string{"unchanged": hash}
+	changes := []struct {
+		name   string
+		change func()
+	}{
+		{"dependency values", func() {
+			assert.Nil(t, ioutil.WriteFile(filepath.Join(chartsDirectory, "common", "values.yaml"), []byte("labels: {team: web}\n"), 0644))
+		}},
+		{"git metadata", func() { chartDocumentationInfo.LastCommitSHA = "711c26f" }},
+		{"changelog", func() {
+			chartDocumentationInfo.ChartChangelog = []helm.ChartChangelogRelease{{Version: "1.0.0"}}
+		}},
+		{"generated date", func() { viper.Set("generated-date", "2026-10-16") }},
+		{"github maintainers", func() { chartDocumentationInfo.Maintainers[0].GitHubUsername = "norwoodj" }},
+		{"linked dependencies", func() {
+			chartDocumentationInfo.Dependencies[0].LocalChart = &helm.LocalChart{Directory: filepath.Join(chartsDirectory, "common")}
+		}},
+	}
+
+	for _, c := range changes {
+		c.change()
+
+		changedHash, err := ChartDocumentationHash(chartDocumentationInfo)
+		assert.Nil(t, err)
+
+		for previous, previousHash := range hashes {
+			assert.NotEqual(t, previousHash, changedHash, "changing the %s gave the hash from %s", c.name, previous)
+		}
+
+		hashes[c.name] = changedHash
+	}
+}
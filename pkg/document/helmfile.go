@@ -0,0 +1,166 @@
+package document
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/Masterminds/sprig"
+	"github.com/norwoodj/helm-docs/pkg/helm"
+	"github.com/norwoodj/helm-docs/pkg/settings"
+	log "github.com/sirupsen/logrus"
+)
+
+const defaultHelmfileReleaseTemplate = `{{ template "helmfile.header" . }}
+
+{{ template "helmfile.releaseTable" . }}
+{{- if .Values }}
+
+{{ template "helmfile.valuesSection" . }}
+{{- end }}
+`
+
+type helmfileReleaseTemplateData struct {
+	helm.HelmfileRelease
+
+	// The helmfile declaring the release
+	Helmfile string
+}
+
+func getHelmfileTemplates() string {
+	helmfileTemplateBuilder := strings.Builder{}
+	helmfileTemplateBuilder.WriteString(`{{ define "helmfile.header" }}# {{ .Name }}{{ end }}`)
+
+	helmfileTemplateBuilder.WriteString(`{{ define "helmfile.releaseTable" }}`)
+	helmfileTemplateBuilder.WriteString("| Chart | Version | Repository | Namespace | Installed |\n")
+	helmfileTemplateBuilder.WriteString("|-------|---------|------------|-----------|-----------|\n")
+	helmfileTemplateBuilder.WriteString("| {{ .Chart }} | {{ .Version }} | {{ .Repository }} | {{ .Namespace }} | {{ .Installed }} |")
+	helmfileTemplateBuilder.WriteString("{{ end }}")
+
+	helmfileTemplateBuilder.WriteString(`{{ define "helmfile.valuesHeader" }}## Values Overrides{{ end }}`)
+
+	helmfileTemplateBuilder.WriteString(`{{ define "helmfile.valuesTable" }}`)
+	helmfileTemplateBuilder.WriteString("| Key | Value | Source |\n")
+	helmfileTemplateBuilder.WriteString("|-----|-------|--------|")
+	helmfileTemplateBuilder.WriteString("  {{- range .Values }}")
+	helmfileTemplateBuilder.WriteString("\n| {{ .Key }} | {{ codeSpan .Value | escapeTableCell }} | {{ .Source }} |")
+	helmfileTemplateBuilder.WriteString("  {{- end }}")
+	helmfileTemplateBuilder.WriteString("{{ end }}")
+
+	helmfileTemplateBuilder.WriteString(`{{ define "helmfile.valuesSection" }}`)
+	helmfileTemplateBuilder.WriteString("{{ if .Values }}")
+	helmfileTemplateBuilder.WriteString(`{{ template "helmfile.valuesHeader" . }}`)
+	helmfileTemplateBuilder.WriteString("\n\n")
+	helmfileTemplateBuilder.WriteString(`{{ template "helmfile.valuesTable" . }}`)
+	helmfileTemplateBuilder.WriteString("{{ end }}")
+	helmfileTemplateBuilder.WriteString("{{ end }}")
+
+	return helmfileTemplateBuilder.String()
+}
+
+// newHelmfileReleaseTemplate parses the helmfile template file if there is one, or otherwise the default helmfile
+// release template, after the built in helmfile templates
+func newHelmfileReleaseTemplate() (*template.Template, error) {
+	helmfileTemplate := template.New("helmfile")
+	helmfileTemplate.Funcs(sprig.TxtFuncMap())
+	helmfileTemplate.Funcs(helmDocsFuncMap())
+
+	if _, err := helmfileTemplate.Parse(getHelmfileTemplates()); err != nil {
+		return nil, err
+	}
+
+	helmfileTemplateContents := defaultHelmfileReleaseTemplate
+	helmfileTemplateFile := settings.GetString("helmfile-template-file")
+	if contents, err := ioutil.ReadFile(helmfileTemplateFile); err == nil {
+		log.Debugf("Using helmfile template file %s", helmfileTemplateFile)
+		helmfileTemplateContents = string(contents)
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	return helmfileTemplate.Parse(helmfileTemplateContents)
+}
+
+// helmfileReleaseOutputPaths names the file each release is documented in after the release, or after its namespace
+// and name where releases of the same name are installed to several namespaces
+func helmfileReleaseOutputPaths(releases []helm.HelmfileRelease, outputDirectory string) []string {
+	releaseNameCounts := make(map[string]int)
+	for _, r := range releases {
+		releaseNameCounts[r.Name]++
+	}
+
+	outputPaths := make([]string, 0, len(releases))
+	for _, r := range releases {
+		outputFile := r.Name
+		if releaseNameCounts[r.Name] > 1 && r.Namespace != "" {
+			outputFile = fmt.Sprintf("%s-%s", r.Namespace, r.Name)
+		}
+
+		outputPaths = append(outputPaths, filepath.Join(outputDirectory, outputFile+".md"))
+	}
+
+	return outputPaths
+}
+
+// PrintHelmfileDocumentation renders a document for each release declared in a helmfile, with the chart it installs
+// and the values it overrides, and writes them to the helmfile output directory, or to stdout on dry runs. When
+// checking documentation, stale documents are diffed instead and ErrDocumentationOutOfDate returned. Errors are logged
+// as well as returned
+func PrintHelmfileDocumentation(helmfilePath string, dryRun bool) error {
+	log.Infof("Generating documentation for the releases of helmfile %s", helmfilePath)
+
+	releases, err := helm.ParseHelmfile(helmfilePath)
+	if err != nil {
+		log.Warnf("Error parsing helmfile %s: %s", helmfilePath, err)
+		return err
+	}
+
+	helmfileTemplate, err := newHelmfileReleaseTemplate()
+	if err != nil {
+		log.Warnf("Error generating helmfile template: %s", err)
+		return err
+	}
+
+	outOfDate := false
+	outputPaths := helmfileReleaseOutputPaths(releases, settings.GetString("helmfile-output-dir"))
+
+	for i, r := range releases {
+		var renderedRelease bytes.Buffer
+		if err := helmfileTemplate.Execute(&renderedRelease, helmfileReleaseTemplateData{HelmfileRelease: r, Helmfile: helmfilePath}); err != nil {
+			log.Warnf("Error generating documentation for release %s of helmfile %s: %s", r.Name, helmfilePath, err)
+			return err
+		}
+
+		documentation := normalizeTrailingWhitespace(renderedRelease.String())
+		if settings.GetBool("check") {
+			diff, err := diffDocumentation(outputPaths[i], documentation)
+			if err != nil {
+				log.Warnf("Error checking documentation of release %s against %s: %s", r.Name, outputPaths[i], err)
+				return err
+			}
+
+			if diff != "" {
+				log.Errorf("Documentation of release %s in %s is out of date", r.Name, outputPaths[i])
+				fmt.Print(diff)
+				outOfDate = true
+			}
+
+			continue
+		}
+
+		if err := writeDocumentation(outputPaths[i], documentation, dryRun); err != nil {
+			log.Warnf("Error writing documentation of release %s to %s: %s", r.Name, outputPaths[i], err)
+			return err
+		}
+	}
+
+	if outOfDate {
+		return ErrDocumentationOutOfDate
+	}
+
+	return nil
+}
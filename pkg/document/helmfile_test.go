@@ -0,0 +1,18 @@
+package document
+
+import (
+	"testing"
+
+	"github.com/norwoodj/helm-docs/pkg/helm"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHelmfileReleaseOutputPaths(t *testing.T) {
+	releases := []helm.HelmfileRelease{
+		{Name: "redis", Namespace: "cache"},
+		{Name: "redis", Namespace: "sessions"},
+		{Name: "app", Namespace: "web"},
+	}
+
+	assert.Equal(t, []string{"docs/cache-redis.md", "docs/sessions-redis.md", "docs/app.md"}, helmfileReleaseOutputPaths(releases, "docs"))
+}
@@ -0,0 +1,164 @@
+package document
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/Masterminds/sprig"
+	"github.com/norwoodj/helm-docs/pkg/helm"
+	"github.com/norwoodj/helm-docs/pkg/settings"
+	log "github.com/sirupsen/logrus"
+)
+
+const defaultIndexTemplate = `{{ template "index.header" . }}
+
+{{ template "index.chartsTable" . }}
+`
+
+type indexChartTemplateData struct {
+	Name        string
+	Version     string
+	AppVersion  string
+	Description string
+	Directory   string
+	Link        string
+}
+
+type indexTemplateData struct {
+	Charts []indexChartTemplateData
+}
+
+func getIndexTemplates() string {
+	indexTemplateBuilder := strings.Builder{}
+	indexTemplateBuilder.WriteString(`{{ define "index.header" }}# Charts{{ end }}`)
+
+	indexTemplateBuilder.WriteString(`{{ define "index.chartsTable" }}`)
+	indexTemplateBuilder.WriteString("| Chart | Version | Description |\n")
+	indexTemplateBuilder.WriteString("|-------|---------|-------------|")
+	indexTemplateBuilder.WriteString("  {{- range .Charts }}")
+	indexTemplateBuilder.WriteString("\n| [{{ .Name }}]({{ .Link }}) | {{ .Version }} | {{ escapeTableCell .Description }} |")
+	indexTemplateBuilder.WriteString("  {{- end }}")
+	indexTemplateBuilder.WriteString("{{ end }}")
+
+	return indexTemplateBuilder.String()
+}
+
+// getIndexTemplateData lists the charts in the index, linking to their documentation relative to the index file
+func getIndexTemplateData(charts []helm.ChartDocumentationInfo, indexPath string) (indexTemplateData, error) {
+	indexCharts := make([]indexChartTemplateData, 0, len(charts))
+
+	for _, c := range charts {
+		outputs, err := getDocumentationOutputs(c)
+		if err != nil {
+			return indexTemplateData{}, err
+		}
+
+		link := ""
+		if len(outputs) > 0 {
+			relativeOutputPath, err := filepath.Rel(filepath.Dir(indexPath), outputs[0].outputPath)
+			if err != nil {
+				return indexTemplateData{}, err
+			}
+
+			link = filepath.ToSlash(relativeOutputPath)
+		}
+
+		indexCharts = append(indexCharts, indexChartTemplateData{
+			Name:        c.Name,
+			Version:     c.Version,
+			AppVersion:  c.AppVersion,
+			Description: c.Description,
+			Directory:   filepath.ToSlash(c.ChartDirectory),
+			Link:        link,
+		})
+	}
+
+	sort.Slice(indexCharts, func(i, j int) bool {
+		if indexCharts[i].Name != indexCharts[j].Name {
+			return indexCharts[i].Name < indexCharts[j].Name
+		}
+
+		return indexCharts[i].Directory < indexCharts[j].Directory
+	})
+
+	return indexTemplateData{Charts: indexCharts}, nil
+}
+
+// newIndexTemplate parses the index template file if there is one, or otherwise the default index template, after the
+// built in index templates
+func newIndexTemplate() (*template.Template, error) {
+	indexTemplate := template.New("index")
+	indexTemplate.Funcs(sprig.TxtFuncMap())
+	indexTemplate.Funcs(helmDocsFuncMap())
+
+	if _, err := indexTemplate.Parse(getIndexTemplates()); err != nil {
+		return nil, err
+	}
+
+	indexTemplateContents := defaultIndexTemplate
+	indexTemplateFile := settings.GetString("index-template-file")
+	if contents, err := ioutil.ReadFile(indexTemplateFile); err == nil {
+		log.Debugf("Using index template file %s", indexTemplateFile)
+		indexTemplateContents = string(contents)
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	return indexTemplate.Parse(indexTemplateContents)
+}
+
+// PrintIndex renders an index of all of the charts documented, linking to their documentation, and writes it to the
+// index file, or to stdout on dry runs. When checking documentation, a stale index file is diffed instead and
+// ErrDocumentationOutOfDate returned. Errors are logged as well as returned
+func PrintIndex(charts []helm.ChartDocumentationInfo, dryRun bool) error {
+	indexPath := settings.GetString("index-file")
+	log.Infof("Generating index of charts %s", indexPath)
+
+	indexTemplate, err := newIndexTemplate()
+	if err != nil {
+		log.Warnf("Error generating index template: %s", err)
+		return err
+	}
+
+	indexTemplateDataObject, err := getIndexTemplateData(charts, indexPath)
+	if err != nil {
+		log.Warnf("Error generating index template data: %s", err)
+		return err
+	}
+
+	var renderedIndex bytes.Buffer
+	if err := indexTemplate.Execute(&renderedIndex, indexTemplateDataObject); err != nil {
+		log.Warnf("Error generating index of charts: %s", err)
+		return err
+	}
+
+	index := normalizeTrailingWhitespace(renderedIndex.String())
+	if settings.GetBool("check") {
+		diff, err := diffDocumentation(indexPath, index)
+		if err != nil {
+			log.Warnf("Error checking index of charts against %s: %s", indexPath, err)
+			return err
+		}
+
+		if diff != "" {
+			log.Errorf("Index of charts in %s is out of date", indexPath)
+			fmt.Print(diff)
+			return ErrDocumentationOutOfDate
+		}
+
+		return nil
+	}
+
+	if err := writeDocumentation(indexPath, index, dryRun); err != nil {
+		log.Warnf("Error writing index of charts to %s: %s", indexPath, err)
+		return err
+	}
+
+	return nil
+}
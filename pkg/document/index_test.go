@@ -0,0 +1,26 @@
+package document
+
+import (
+	"testing"
+
+	"github.com/norwoodj/helm-docs/pkg/helm"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetIndexTemplateData(t *testing.T) {
+	viper.Set("output-file", "README.md")
+	defer viper.Set("output-file", nil)
+
+	charts := []helm.ChartDocumentationInfo{
+		{ChartDirectory: "charts/web", ChartMeta: helm.ChartMeta{Name: "web", Version: "1.2.0"}},
+		{ChartDirectory: "charts/api", ChartMeta: helm.ChartMeta{Name: "api", Version: "0.1.0"}},
+	}
+
+	indexTemplateDataObject, err := getIndexTemplateData(charts, "docs/index.md")
+	assert.Nil(t, err)
+	assert.Equal(t, []indexChartTemplateData{
+		{Name: "api", Version: "0.1.0", Directory: "charts/api", Link: "../charts/api/README.md"},
+		{Name: "web", Version: "1.2.0", Directory: "charts/web", Link: "../charts/web/README.md"},
+	}, indexTemplateDataObject.Charts)
+}
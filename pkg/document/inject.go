@@ -0,0 +1,45 @@
+package document
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+// When injecting documentation into an existing file, only the content between these markers is replaced
+const (
+	injectStartMarker = "<!-- helm-docs:start -->"
+	injectEndMarker   = "<!-- helm-docs:end -->"
+)
+
+// injectDocumentation replaces the content between the markers in an existing document with rendered documentation,
+// leaving the rest of the document as it was
+func injectDocumentation(existingDocument string, documentation string) (string, error) {
+	start := strings.Index(existingDocument, injectStartMarker)
+	end := strings.Index(existingDocument, injectEndMarker)
+	if start < 0 || end < 0 || end < start {
+		return "", fmt.Errorf("did not find %s followed by %s to inject documentation between", injectStartMarker, injectEndMarker)
+	}
+
+	if !strings.HasSuffix(documentation, "\n") {
+		documentation += "\n"
+	}
+
+	return existingDocument[:start+len(injectStartMarker)] + "\n" + documentation + existingDocument[end:], nil
+}
+
+// injectDocumentationIntoFile injects rendered documentation into the current contents of its output file. Output
+// files that don't exist yet are created containing just the markers and the documentation between them
+func injectDocumentationIntoFile(outputPath string, documentation string) (string, error) {
+	existingDocument, err := ioutil.ReadFile(outputPath)
+	if os.IsNotExist(err) {
+		return injectDocumentation(injectStartMarker+"\n"+injectEndMarker+"\n", documentation)
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	return injectDocumentation(string(existingDocument), documentation)
+}
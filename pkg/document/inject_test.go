@@ -0,0 +1,45 @@
+package document
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInjectDocumentation(t *testing.T) {
+	existingDocument := `# My chart
+
+Hand written introduction.
+
+<!-- helm-docs:start -->
+stale documentation
+<!-- helm-docs:end -->
+
+Hand written footer.
+`
+
+	document, err := injectDocumentation(existingDocument, "## Values\n\n| Key |\n")
+	assert.Nil(t, err)
+	assert.Equal(t, `# My chart
+
+Hand written introduction.
+
+<!-- helm-docs:start -->
+## Values
+
+| Key |
+<!-- helm-docs:end -->
+
+Hand written footer.
+`, document)
+
+	reinjectedDocument, err := injectDocumentation(document, "## Values\n\n| Key |")
+	assert.Nil(t, err)
+	assert.Equal(t, document, reinjectedDocument)
+
+	_, err = injectDocumentation("# My chart\n", "## Values\n")
+	assert.NotNil(t, err)
+
+	_, err = injectDocumentation("<!-- helm-docs:end -->\n<!-- helm-docs:start -->\n", "## Values\n")
+	assert.NotNil(t, err)
+}
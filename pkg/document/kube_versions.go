@@ -0,0 +1,86 @@
+package document
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/Masterminds/semver"
+	"github.com/norwoodj/helm-docs/pkg/helm"
+	log "github.com/sirupsen/logrus"
+)
+
+// The highest patch release of a minor Kubernetes version checked against a chart's kubeVersion, to tell whether only
+// some of the minor version's patch releases satisfy it
+const lastKubernetesPatchRelease = 999
+
+var kubeVersionOperatorSpacePattern = regexp.MustCompile(`([<>=!~^]+)\s+`)
+
+type kubeVersionTemplateData struct {
+	Version            string
+	Supported          bool
+	PartiallySupported bool
+}
+
+// parseKubeVersionConstraint parses a chart's kubeVersion constraint. Helm allows the constraints of a range to be
+// separated by spaces as well as commas, e.g. ">= 1.22.0-0 < 1.30.0-0", so they're joined with commas first
+func parseKubeVersionConstraint(kubeVersion string) (*semver.Constraints, error) {
+	ranges := strings.Split(kubeVersion, "||")
+	for i, r := range ranges {
+		r = kubeVersionOperatorSpacePattern.ReplaceAllString(r, "$1")
+		fields := strings.FieldsFunc(r, func(c rune) bool { return c == ',' || unicode.IsSpace(c) })
+
+		// Hyphen ranges, e.g. "1.22 - 1.29", are the one kind of constraint that contains spaces
+		constraints := make([]string, 0, len(fields))
+		for j := 0; j < len(fields); j++ {
+			if fields[j] == "-" && len(constraints) > 0 && j+1 < len(fields) {
+				constraints[len(constraints)-1] += " - " + fields[j+1]
+				j++
+				continue
+			}
+
+			constraints = append(constraints, fields[j])
+		}
+
+		ranges[i] = strings.Join(constraints, ", ")
+	}
+
+	return semver.NewConstraint(strings.Join(ranges, " || "))
+}
+
+// getKubeVersionsTemplateData checks the minor Kubernetes versions of the matrix, set by the kube versions option, e.g.
+// "1.30", against the chart's kubeVersion constraint. A version is supported if all of its patch releases satisfy the
+// constraint, and partially supported if only some of them do. Charts without a valid kubeVersion have no matrix
+func getKubeVersionsTemplateData(chartDocumentationInfo helm.ChartDocumentationInfo) []kubeVersionTemplateData {
+	kubeVersions := make([]kubeVersionTemplateData, 0)
+	if chartDocumentationInfo.KubeVersion == "" {
+		return kubeVersions
+	}
+
+	constraint, err := parseKubeVersionConstraint(chartDocumentationInfo.KubeVersion)
+	if err != nil {
+		log.Warnf("Error parsing kubeVersion %s of chart %s, not documenting the Kubernetes versions it supports: %s", chartDocumentationInfo.KubeVersion, chartDocumentationInfo.ChartDirectory, err)
+		return kubeVersions
+	}
+
+	for _, v := range chartDocumentationInfo.Settings().GetStringSlice("kube-versions") {
+		version, err := semver.NewVersion(v)
+		if err != nil {
+			log.Warnf("Not documenting support for Kubernetes version %s, which is not a semantic version: %s", v, err)
+			continue
+		}
+
+		firstPatch := semver.MustParse(fmt.Sprintf("%d.%d.0", version.Major(), version.Minor()))
+		lastPatch := semver.MustParse(fmt.Sprintf("%d.%d.%d", version.Major(), version.Minor(), lastKubernetesPatchRelease))
+		firstSupported, lastSupported := constraint.Check(firstPatch), constraint.Check(lastPatch)
+
+		kubeVersions = append(kubeVersions, kubeVersionTemplateData{
+			Version:            fmt.Sprintf("%d.%d", version.Major(), version.Minor()),
+			Supported:          firstSupported && lastSupported,
+			PartiallySupported: firstSupported != lastSupported,
+		})
+	}
+
+	return kubeVersions
+}
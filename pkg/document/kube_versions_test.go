@@ -0,0 +1,68 @@
+package document
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/Masterminds/semver"
+	"github.com/norwoodj/helm-docs/pkg/helm"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseKubeVersionConstraint(t *testing.T) {
+	for _, kubeVersion := range []string{">= 1.22.0-0 < 1.30.0-0", ">=1.22.0-0, <1.30.0-0", "1.22 - 1.29.99", "~1.21 || >= 1.22 <1.30"} {
+		constraint, err := parseKubeVersionConstraint(kubeVersion)
+		if !assert.Nil(t, err, kubeVersion) {
+			continue
+		}
+
+		assert.True(t, constraint.Check(semverVersion(t, "1.29.5")), kubeVersion)
+		assert.False(t, constraint.Check(semverVersion(t, "1.30.0")), kubeVersion)
+	}
+
+	_, err := parseKubeVersionConstraint(">= one.two")
+	assert.NotNil(t, err)
+}
+
+func TestKubeVersionsTemplateData(t *testing.T) {
+	viper.Set("kube-versions", []string{"1.27", "1.28", "v1.29", "1.30", "latest"})
+	defer viper.Set("kube-versions", nil)
+
+	kubeVersions := getKubeVersionsTemplateData(helm.ChartDocumentationInfo{ChartMeta: helm.ChartMeta{KubeVersion: ">= 1.28.3-0 < 1.30.0-0"}})
+	assert.Equal(t, []kubeVersionTemplateData{
+		{Version: "1.27"},
+		{Version: "1.28", PartiallySupported: true},
+		{Version: "1.29", Supported: true},
+		{Version: "1.30"},
+	}, kubeVersions)
+
+	assert.Empty(t, getKubeVersionsTemplateData(helm.ChartDocumentationInfo{}))
+	assert.Empty(t, getKubeVersionsTemplateData(helm.ChartDocumentationInfo{ChartMeta: helm.ChartMeta{KubeVersion: "not a constraint"}}))
+}
+
+func TestKubeVersionsTable(t *testing.T) {
+	chartDocumentationInfo := helm.ChartDocumentationInfo{ChartMeta: helm.ChartMeta{Name: "app", KubeVersion: "~1.28 || >=1.30"}}
+	documentationTemplate, err := newChartDocumentationTemplate(chartDocumentationInfo, []string{})
+	assert.Nil(t, err)
+
+	_, err = documentationTemplate.Parse(`{{ template "chart.kubeVersionsTable" . }}`)
+	assert.Nil(t, err)
+
+	var rendered bytes.Buffer
+	assert.Nil(t, documentationTemplate.Execute(&rendered, chartTemplateData{
+		ChartDocumentationInfo: chartDocumentationInfo,
+		KubeVersions:           []kubeVersionTemplateData{{Version: "1.28", Supported: true}, {Version: "1.29"}},
+	}))
+
+	assert.Equal(t, "| kubeVersion | 1.28 | 1.29 |\n|-------------|------|------|\n| `~1.28 \\|\\| >=1.30` | ✅ | ❌ |", rendered.String())
+}
+
+func semverVersion(t *testing.T, version string) *semver.Version {
+	v, err := semver.NewVersion(version)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return v
+}
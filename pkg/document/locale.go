@@ -0,0 +1,138 @@
+package document
+
+import (
+	"fmt"
+
+	"github.com/norwoodj/helm-docs/pkg/helm"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	defaultLocale    = "en"
+	localeAnnotation = "helm-docs.io/locale"
+)
+
+// Translations of the text of the built in templates, keyed by locale and then by the english text, which may be a
+// format string. Text missing from a locale's bundle is rendered in english
+var translationBundles = map[string]map[string]string{
+	"de": {
+		"Chart Annotations":                      "Chart-Annotationen",
+		"License":                                "Lizenz",
+		"Changes in %s":                          "Änderungen in %s",
+		"Links":                                  "Links",
+		"Changelog":                              "Änderungsprotokoll",
+		"Unreleased":                             "Unveröffentlicht",
+		"Installing the Chart":                   "Installation des Charts",
+		"Post Installation Notes":                "Hinweise nach der Installation",
+		"Chart Tests":                            "Chart-Tests",
+		"Named Templates":                        "Benannte Templates",
+		"Values Schema Validation":               "Validierung der Werte gegen das Schema",
+		"Lint Results":                           "Lint-Ergebnisse",
+		"Custom Resource Definitions":            "Custom Resource Definitions",
+		"Kubernetes Resources":                   "Kubernetes-Ressourcen",
+		"Kubernetes API Deprecations":            "Veraltete Kubernetes-APIs",
+		"Kubernetes Compatibility":               "Kubernetes-Kompatibilität",
+		"Container Images":                       "Container-Images",
+		"Chart Requirements":                     "Chart-Abhängigkeiten",
+		"Chart Values":                           "Chart-Werte",
+		"Chart Values (%s)":                      "Chart-Werte (%s)",
+		"Global Values":                          "Globale Werte",
+		"Other Values":                           "Weitere Werte",
+		"Required Values":                        "Erforderliche Werte",
+		"Upgrade Notes: Values Changes Since %s": "Hinweise zum Upgrade: Änderungen der Werte seit %s",
+		"%s Subchart Values":                     "Werte des Subcharts %s",
+		"Subcharts":                              "Subcharts",
+		"Source Code":                            "Quellcode",
+		"Maintainers":                            "Maintainer",
+	},
+	"fr": {
+		"Chart Annotations":                      "Annotations du chart",
+		"License":                                "Licence",
+		"Changes in %s":                          "Modifications de la version %s",
+		"Links":                                  "Liens",
+		"Changelog":                              "Journal des modifications",
+		"Unreleased":                             "Non publié",
+		"Installing the Chart":                   "Installation du chart",
+		"Post Installation Notes":                "Notes post-installation",
+		"Chart Tests":                            "Tests du chart",
+		"Named Templates":                        "Modèles nommés",
+		"Values Schema Validation":               "Validation des valeurs par le schéma",
+		"Lint Results":                           "Résultats du lint",
+		"Custom Resource Definitions":            "Définitions de ressources personnalisées",
+		"Kubernetes Resources":                   "Ressources Kubernetes",
+		"Kubernetes API Deprecations":            "API Kubernetes obsolètes",
+		"Kubernetes Compatibility":               "Compatibilité avec Kubernetes",
+		"Container Images":                       "Images de conteneurs",
+		"Chart Requirements":                     "Dépendances du chart",
+		"Chart Values":                           "Valeurs du chart",
+		"Chart Values (%s)":                      "Valeurs du chart (%s)",
+		"Global Values":                          "Valeurs globales",
+		"Other Values":                           "Autres valeurs",
+		"Required Values":                        "Valeurs requises",
+		"Upgrade Notes: Values Changes Since %s": "Notes de mise à jour : modifications des valeurs depuis %s",
+		"%s Subchart Values":                     "Valeurs du sous-chart %s",
+		"Subcharts":                              "Sous-charts",
+		"Source Code":                            "Code source",
+		"Maintainers":                            "Mainteneurs",
+	},
+	"ja": {
+		"Chart Annotations":                      "チャートのアノテーション",
+		"License":                                "ライセンス",
+		"Changes in %s":                          "%s の変更点",
+		"Links":                                  "リンク",
+		"Changelog":                              "変更履歴",
+		"Unreleased":                             "未リリース",
+		"Installing the Chart":                   "チャートのインストール",
+		"Post Installation Notes":                "インストール後の注意事項",
+		"Chart Tests":                            "チャートのテスト",
+		"Named Templates":                        "名前付きテンプレート",
+		"Values Schema Validation":               "値のスキーマ検証",
+		"Lint Results":                           "Lint の結果",
+		"Custom Resource Definitions":            "カスタムリソース定義",
+		"Kubernetes Resources":                   "Kubernetes リソース",
+		"Kubernetes API Deprecations":            "非推奨の Kubernetes API",
+		"Kubernetes Compatibility":               "Kubernetes の互換性",
+		"Container Images":                       "コンテナイメージ",
+		"Chart Requirements":                     "チャートの依存関係",
+		"Chart Values":                           "チャートの値",
+		"Chart Values (%s)":                      "チャートの値 (%s)",
+		"Global Values":                          "グローバル値",
+		"Other Values":                           "その他の値",
+		"Required Values":                        "必須の値",
+		"Upgrade Notes: Values Changes Since %s": "アップグレードの注意事項: %s 以降の値の変更",
+		"%s Subchart Values":                     "サブチャート %s の値",
+		"Subcharts":                              "サブチャート",
+		"Source Code":                            "ソースコード",
+		"Maintainers":                            "メンテナー",
+	},
+}
+
+// getChartLocale returns the locale in which a chart's documentation is written
+func getChartLocale(chartDocumentationInfo helm.ChartDocumentationInfo) string {
+	locale := chartSetting(chartDocumentationInfo, localeAnnotation, "locale")
+	if locale == "" {
+		return defaultLocale
+	}
+
+	if _, ok := translationBundles[locale]; !ok && locale != defaultLocale {
+		log.Warnf("No translations for locale %s of chart %s, using %s", locale, chartDocumentationInfo.ChartDirectory, defaultLocale)
+		return defaultLocale
+	}
+
+	return locale
+}
+
+// translator returns the template function translating text into a locale, formatting it with any arguments given
+func translator(locale string) func(string, ...interface{}) string {
+	return func(text string, args ...interface{}) string {
+		if translation, ok := translationBundles[locale][text]; ok {
+			text = translation
+		}
+
+		if len(args) == 0 {
+			return text
+		}
+
+		return fmt.Sprintf(text, args...)
+	}
+}
@@ -1,23 +1,496 @@
 package document
 
 import (
+	"fmt"
+	"net/url"
+	"path"
+	"path/filepath"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
 	"github.com/norwoodj/helm-docs/pkg/helm"
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v2"
 )
 
 type valueRow struct {
 	Key         string
 	Type        string
+	Value       interface{}
 	Default     string
 	Description string
+	Required    bool
+	Allowed     []string
+	Section     string
+	SourceURL   string
+	Anchor      string
+}
+
+// valueSection is a section of the values table, holding the rows of the values placed in it
+type valueSection struct {
+	Name   string
+	Values []valueRow
+}
+
+type extraValuesTemplateData struct {
+	ValuesFile string
+	Values     []valueRow
+}
+
+type dependencyValuesTemplateData struct {
+	Name   string
+	Values []valueRow
+}
+
+type subchartTemplateData struct {
+	Name         string
+	Version      string
+	Description  string
+	Path         string
+	ValuesPrefix string
+
+	// The path of the subchart's own documentation relative to that of its parent chart
+	ReadmePath string
+	Values     []valueRow
+}
+
+type chartRepositoryTemplateData struct {
+	Name string
+	URL  string
 }
 
 type chartTemplateData struct {
 	helm.ChartDocumentationInfo
-	Values []valueRow
+	Values           []valueRow
+	GlobalValues     []valueRow
+	RequiredValues   []valueRow
+	ExtraValues      map[string]extraValuesTemplateData
+	DependencyValues []dependencyValuesTemplateData
+	Subcharts        []subchartTemplateData
+	SubchartsMode    string
+	ChartRepository  chartRepositoryTemplateData
+	HeadingOffset    int
+	BadgeStyle       badgeStyleTemplateData
+	Extra            map[interface{}]interface{}
+	HelmDocsVersion  string
+	GeneratedDate    string
+	NamedSources     []namedSourceTemplateData
+	Changes          []changeTemplateData
+	Links            []linkTemplateData
+	KubeVersions     []kubeVersionTemplateData
+
+	// The anchors of the rows of the values table by key, for links to them
+	valueAnchors map[string]string
+}
+
+type namedSourceTemplateData struct {
+	Name string
+	URL  string
+}
+
+// sourceLinkName derives readable link text for a source url from its host and path, e.g. "github.com/org/repo"
+func sourceLinkName(sourceURL string) string {
+	u, err := url.Parse(sourceURL)
+	if err != nil || u.Host == "" {
+		return sourceURL
+	}
+
+	return strings.TrimSuffix(u.Host+u.Path, "/")
+}
+
+// getNamedSourcesTemplateData names the chart's sources, with the names given in the sources annotation of its
+// Chart.yaml, a yaml map of link text to url, followed by the chart's other sources named after their urls
+func getNamedSourcesTemplateData(chartDocumentationInfo helm.ChartDocumentationInfo) ([]namedSourceTemplateData, error) {
+	namedSources := make([]namedSourceTemplateData, 0)
+	namedURLs := make(map[string]bool)
+
+	if annotatedSources, ok := chartDocumentationInfo.Annotations[sourcesAnnotation]; ok {
+		var sources yaml.MapSlice
+		if err := yaml.Unmarshal([]byte(annotatedSources), &sources); err != nil {
+			return nil, fmt.Errorf("invalid %s annotation, must be a map of names to urls: %s", sourcesAnnotation, err)
+		}
+
+		for _, s := range sources {
+			sourceURL := fmt.Sprint(s.Value)
+			namedSources = append(namedSources, namedSourceTemplateData{Name: fmt.Sprint(s.Key), URL: sourceURL})
+			namedURLs[sourceURL] = true
+		}
+	}
+
+	for _, s := range chartDocumentationInfo.Sources {
+		if !namedURLs[s] {
+			namedSources = append(namedSources, namedSourceTemplateData{Name: sourceLinkName(s), URL: s})
+		}
+	}
+
+	return namedSources, nil
+}
+
+// HelmDocsVersion is the version of helm-docs rendering documentation, for templates to mention, set by the helm-docs
+// command
+var HelmDocsVersion string
+
+// The path of the helm-docs module, under which programs embedding helm-docs list it in their build information
+const helmDocsModulePath = "github.com/norwoodj/helm-docs"
+
+// helmDocsVersion returns the version of helm-docs rendering documentation, that of the helm-docs command, or for
+// programs embedding helm-docs, which don't set one, that of the module they were built with
+func helmDocsVersion() string {
+	if HelmDocsVersion != "" {
+		return HelmDocsVersion
+	}
+
+	buildInfo, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+
+	for _, m := range append([]*debug.Module{&buildInfo.Main}, buildInfo.Deps...) {
+		if m.Path == helmDocsModulePath && m.Version != "(devel)" {
+			return m.Version
+		}
+	}
+
+	return ""
+}
+
+// getGeneratedDate returns the date documentation is generated on, unless it's omitted, or pinned for reproducible
+// output
+func getGeneratedDate(chartSettings *viper.Viper) string {
+	if chartSettings.GetBool("omit-generated-date") {
+		return ""
+	}
+
+	if generatedDate := chartSettings.GetString("generated-date"); generatedDate != "" {
+		return generatedDate
+	}
+
+	return time.Now().Format("2006-01-02")
+}
+
+type badgeStyleTemplateData struct {
+	Style   string
+	Color   string
+	BaseURL string
+}
+
+// Chart.yaml annotations overriding the command line settings of the same name for a single chart
+const (
+	chartRepositoryNameAnnotation = "helm-docs.io/repository-name"
+	chartRepositoryURLAnnotation  = "helm-docs.io/repository-url"
+	badgeStyleAnnotation          = "helm-docs.io/badge-style"
+	badgeColorAnnotation          = "helm-docs.io/badge-color"
+	badgeBaseURLAnnotation        = "helm-docs.io/badge-base-url"
+	sourcesAnnotation             = "helm-docs.io/sources"
+	skipSectionsAnnotation        = "helm-docs.io/skip-sections"
+	outputFileAnnotation          = "helm-docs.io/output-file"
+	templateFileAnnotation        = "helm-docs.io/template-file"
+	sortValuesOrderAnnotation     = "helm-docs.io/sort-values-order"
+	headingOffsetAnnotation       = "helm-docs.io/heading-offset"
+)
+
+// chartSetting returns a setting from the chart's Chart.yaml annotations if set there, or otherwise from the settings the
+// chart is documented with
+func chartSetting(chartDocumentationInfo helm.ChartDocumentationInfo, annotation string, flag string) string {
+	if value, ok := chartDocumentationInfo.Annotations[annotation]; ok {
+		return value
+	}
+
+	return chartDocumentationInfo.Settings().GetString(flag)
+}
+
+// chartSettingList returns a list setting from the chart's Chart.yaml annotations, comma separated, if set there, or
+// otherwise from the settings the chart is documented with
+func chartSettingList(chartDocumentationInfo helm.ChartDocumentationInfo, annotation string, flag string) []string {
+	value, ok := chartDocumentationInfo.Annotations[annotation]
+	if !ok {
+		return chartDocumentationInfo.Settings().GetStringSlice(flag)
+	}
+
+	items := make([]string, 0)
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
+// getHeadingOffset returns the number of levels by which to shift the headings of a chart's documentation
+func getHeadingOffset(chartDocumentationInfo helm.ChartDocumentationInfo) (int, error) {
+	value, ok := chartDocumentationInfo.Annotations[headingOffsetAnnotation]
+	if !ok {
+		return chartDocumentationInfo.Settings().GetInt("heading-offset"), nil
+	}
+
+	headingOffset, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s annotation %q, must be a number", headingOffsetAnnotation, value)
+	}
+
+	return headingOffset, nil
+}
+
+// getChartRepositoryTemplateData finds the helm repository a chart is published to
+func getChartRepositoryTemplateData(chartDocumentationInfo helm.ChartDocumentationInfo) chartRepositoryTemplateData {
+	repository := chartRepositoryTemplateData{
+		Name: chartSetting(chartDocumentationInfo, chartRepositoryNameAnnotation, "chart-repository-name"),
+		URL:  chartSetting(chartDocumentationInfo, chartRepositoryURLAnnotation, "chart-repository-url"),
+	}
+
+	if repository.Name == "" {
+		repository.Name = chartDocumentationInfo.Name
+	}
+
+	return repository
+}
+
+func getBadgeStyleTemplateData(chartDocumentationInfo helm.ChartDocumentationInfo) badgeStyleTemplateData {
+	return badgeStyleTemplateData{
+		Style:   chartSetting(chartDocumentationInfo, badgeStyleAnnotation, "badge-style"),
+		Color:   chartSetting(chartDocumentationInfo, badgeColorAnnotation, "badge-color"),
+		BaseURL: strings.TrimSuffix(chartSetting(chartDocumentationInfo, badgeBaseURLAnnotation, "badge-base-url"), "/"),
+	}
+}
+
+func isKeyUnder(key string, root string) bool {
+	return key == root || strings.HasPrefix(key, root+".") || strings.HasPrefix(key, root+"[")
+}
+
+func isGlobalValueKey(key string) bool {
+	return isKeyUnder(key, "global")
+}
+
+// withoutGlobalValues returns the template data of a chart with its global values left out of its values table, for
+// layouts documenting them in the global values section instead. As the values table no longer has the anchors of the
+// global values, the rows of the global values table take them, so that links to global values still resolve, e.g.
+// {{ template "chart.valuesSection" (withoutGlobalValues .) }}
+// {{ template "chart.globalValuesSection" (withoutGlobalValues .) }}
+func withoutGlobalValues(templateData chartTemplateData) chartTemplateData {
+	values := make([]valueRow, 0, len(templateData.Values))
+	globalValues := make([]valueRow, 0, len(templateData.GlobalValues))
+	for _, r := range templateData.Values {
+		if isGlobalValueKey(r.Key) {
+			globalValues = append(globalValues, r)
+		} else {
+			values = append(values, r)
+		}
+	}
+
+	templateData.Values = values
+	templateData.GlobalValues = globalValues
+	return templateData
+}
+
+// valueSections groups the rows of a values table into their sections, in the order in which the first row of each
+// appears, followed by the rows without a section in one with no name. Values tables without any sections aren't
+// grouped, so have no sections
+func valueSections(valueRows []valueRow) []valueSection {
+	sections := make([]valueSection, 0)
+	sectionIndexes := make(map[string]int)
+	unsectionedRows := make([]valueRow, 0)
+
+	for _, r := range valueRows {
+		if r.Section == "" {
+			unsectionedRows = append(unsectionedRows, r)
+			continue
+		}
+
+		i, ok := sectionIndexes[r.Section]
+		if !ok {
+			i = len(sections)
+			sectionIndexes[r.Section] = i
+			sections = append(sections, valueSection{Name: r.Section})
+		}
+
+		sections[i].Values = append(sections[i].Values, r)
+	}
+
+	if len(sections) > 0 && len(unsectionedRows) > 0 {
+		sections = append(sections, valueSection{Values: unsectionedRows})
+	}
+
+	return sections
+}
+
+// valueRowsUnder selects the rows of the chart's values table for the value at a key path and all the values nested
+// under it
+func valueRowsUnder(root string, templateData chartTemplateData) []valueRow {
+	selectedRows := make([]valueRow, 0)
+	for _, r := range templateData.Values {
+		if isKeyUnder(r.Key, root) {
+			selectedRows = append(selectedRows, r)
+		}
+	}
+
+	return withoutAnchors(selectedRows)
+}
+
+func getExtraValuesTemplateData(chartDocumentationInfo helm.ChartDocumentationInfo, sourceURL string) (map[string]extraValuesTemplateData, error) {
+	extraValues := make(map[string]extraValuesTemplateData)
+
+	for valuesFilename, valuesFile := range chartDocumentationInfo.ChartExtraValues {
+		// Descriptions from the main values file are used for any key not described in the extra values file itself
+		descriptions := make(map[string]helm.ChartValueDescription)
+		for k, d := range chartDocumentationInfo.ChartValuesDescriptions {
+			descriptions[k] = d
+		}
+		for k, d := range valuesFile.Descriptions {
+			descriptions[k] = d
+		}
+
+		valuesTableRows, err := createValueRowsFromObject(chartDocumentationInfo.Settings(), "", valuesFile.Values, descriptions, true)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := sortValueRows(valuesTableRows, chartSetting(chartDocumentationInfo, sortValuesOrderAnnotation, "sort-values-order"), valuesFile.LineNumbers); err != nil {
+			return nil, err
+		}
+
+		linkValueRowsToSource(valuesTableRows, sourceURL, valuesFilename, valuesFile.LineNumbers)
+
+		extraValues[valuesFilename] = extraValuesTemplateData{
+			ValuesFile: valuesFilename,
+			Values:     valuesTableRows,
+		}
+	}
+
+	return extraValues, nil
+}
+
+// extraValueRows returns the rows of the values table of one of a chart's extra values files, for templates rendering
+// the table of a single file, e.g. {{ template "chart.valuesTableFor" "values-production.yaml" }}. It's overridden for
+// each chart by extraValuesFuncMap, as the template data isn't at hand in a template given only the file's name
+func extraValueRows(valuesFile string) ([]valueRow, error) {
+	return nil, fmt.Errorf("extraValueRows: %s is not an extra values file of the chart", valuesFile)
+}
+
+// extraValuesFuncMap returns the extraValueRows template function for a chart, selecting the rows of its extra values
+// files
+func extraValuesFuncMap(extraValues map[string]extraValuesTemplateData) template.FuncMap {
+	return template.FuncMap{
+		"extraValueRows": func(valuesFile string) ([]valueRow, error) {
+			if e, ok := extraValues[valuesFile]; ok {
+				return e.Values, nil
+			}
+
+			return extraValueRows(valuesFile)
+		},
+	}
+}
+
+// getDependencyValuesTemplateData builds the values tables of a chart's dependencies, with keys prefixed by the name
+// under which each dependency's values are nested in the parent chart's values
+func getDependencyValuesTemplateData(chartDocumentationInfo helm.ChartDocumentationInfo) ([]dependencyValuesTemplateData, error) {
+	dependencyValues := make([]dependencyValuesTemplateData, 0)
+
+	for _, d := range chartDocumentationInfo.ChartDependencyValues {
+		if len(d.Values) == 0 {
+			continue
+		}
+
+		descriptions := make(map[string]helm.ChartValueDescription)
+		for k, description := range d.Descriptions {
+			descriptions[d.Name+"."+k] = description
+		}
+
+		lineNumbers := make(map[string]int)
+		for k, l := range d.LineNumbers {
+			lineNumbers[d.Name+"."+k] = l
+		}
+
+		valuesTableRows, err := createValueRowsFromObject(chartDocumentationInfo.Settings(), d.Name, d.Values, descriptions, true)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := sortValueRows(valuesTableRows, chartSetting(chartDocumentationInfo, sortValuesOrderAnnotation, "sort-values-order"), lineNumbers); err != nil {
+			return nil, err
+		}
+
+		dependencyValues = append(dependencyValues, dependencyValuesTemplateData{Name: d.Name, Values: valuesTableRows})
+	}
+
+	return dependencyValues, nil
+}
+
+// The ways in which a parent chart documents its local subcharts
+const (
+	embedSubchartsMode = "embed"
+	linkSubchartsMode  = "link"
+)
+
+// subchartReadmePath finds the path of a subchart's documentation relative to the directory its parent chart's
+// documentation is written to, so that it can be linked to
+func subchartReadmePath(chartDocumentationInfo helm.ChartDocumentationInfo, subchart helm.ChartSubchart) string {
+	outputFile := chartSetting(chartDocumentationInfo, outputFileAnnotation, "output-file")
+	subchartOutputFile := chartSetting(helm.ChartDocumentationInfo{ChartMeta: subchart.ChartMeta}.WithSettings(chartDocumentationInfo.Settings()), outputFileAnnotation, "output-file")
+
+	readmePath, err := filepath.Rel(filepath.Dir(outputFile), filepath.Join(subchart.Path, subchartOutputFile))
+	if err != nil {
+		return path.Join(subchart.Path, subchartOutputFile)
+	}
+
+	return filepath.ToSlash(readmePath)
+}
+
+// getSubchartsTemplateData builds the documentation of a chart's local subcharts, with the keys of their values
+// prefixed by the key path under which they're nested in the parent chart's values
+func getSubchartsTemplateData(chartDocumentationInfo helm.ChartDocumentationInfo) ([]subchartTemplateData, string, error) {
+	subchartsMode := chartDocumentationInfo.Settings().GetString("document-subcharts")
+	if subchartsMode != "" && subchartsMode != embedSubchartsMode && subchartsMode != linkSubchartsMode {
+		return nil, "", fmt.Errorf("invalid subcharts mode %s, must be one of %s or %s", subchartsMode, embedSubchartsMode, linkSubchartsMode)
+	}
+
+	subcharts := make([]subchartTemplateData, 0)
+	for _, s := range chartDocumentationInfo.ChartSubcharts {
+		descriptions := make(map[string]helm.ChartValueDescription)
+		for k, description := range s.Descriptions {
+			descriptions[s.ValuesPrefix+"."+k] = description
+		}
+
+		lineNumbers := make(map[string]int)
+		for k, l := range s.LineNumbers {
+			lineNumbers[s.ValuesPrefix+"."+k] = l
+		}
+
+		// Subcharts without values, e.g. library charts, are still linked to, but have no values table
+		valuesTableRows := make([]valueRow, 0)
+		if len(s.Values) > 0 {
+			rows, err := createValueRowsFromObject(chartDocumentationInfo.Settings(), s.ValuesPrefix, s.Values, descriptions, true)
+			if err != nil {
+				return nil, "", err
+			}
+
+			if err := sortValueRows(rows, chartSetting(chartDocumentationInfo, sortValuesOrderAnnotation, "sort-values-order"), lineNumbers); err != nil {
+				return nil, "", err
+			}
+
+			valuesTableRows = rows
+		}
+
+		subcharts = append(subcharts, subchartTemplateData{
+			Name:         s.Name,
+			Version:      s.Version,
+			Description:  s.Description,
+			Path:         s.Path,
+			ValuesPrefix: s.ValuesPrefix,
+			ReadmePath:   subchartReadmePath(chartDocumentationInfo, s),
+			Values:       valuesTableRows,
+		})
+	}
+
+	return subcharts, subchartsMode, nil
 }
 
 func getChartTemplateData(chartDocumentationInfo helm.ChartDocumentationInfo) (chartTemplateData, error) {
 	valuesTableRows, err := createValueRowsFromObject(
+		chartDocumentationInfo.Settings(),
 		"",
 		chartDocumentationInfo.ChartValues,
 		chartDocumentationInfo.ChartValuesDescriptions,
@@ -28,8 +501,90 @@ func getChartTemplateData(chartDocumentationInfo helm.ChartDocumentationInfo) (c
 		return chartTemplateData{}, err
 	}
 
+	valuesTableRows = append(valuesTableRows, createCommentedOutValueRows(valuesTableRows, chartDocumentationInfo.ChartValuesDescriptions)...)
+
+	err = sortValueRows(valuesTableRows, chartSetting(chartDocumentationInfo, sortValuesOrderAnnotation, "sort-values-order"), chartDocumentationInfo.ChartValuesLineNumbers)
+	if err != nil {
+		return chartTemplateData{}, err
+	}
+
+	sourceURL := getValuesSourceURL(chartDocumentationInfo)
+	linkValueRowsToSource(valuesTableRows, sourceURL, "values.yaml", chartDocumentationInfo.ChartValuesLineNumbers)
+	valueAnchors := anchorValueRows(valuesTableRows)
+
+	// Global values are shared with subcharts, and so are also listed on their own for templates to call them out
+	globalValuesTableRows := make([]valueRow, 0)
+	requiredValuesTableRows := make([]valueRow, 0)
+
+	for _, v := range valuesTableRows {
+		if isGlobalValueKey(v.Key) {
+			globalValuesTableRows = append(globalValuesTableRows, v)
+		}
+
+		if v.Required {
+			requiredValuesTableRows = append(requiredValuesTableRows, v)
+		}
+	}
+
+	extraValues, err := getExtraValuesTemplateData(chartDocumentationInfo, sourceURL)
+	if err != nil {
+		return chartTemplateData{}, err
+	}
+
+	dependencyValues, err := getDependencyValuesTemplateData(chartDocumentationInfo)
+	if err != nil {
+		return chartTemplateData{}, err
+	}
+
+	subcharts, subchartsMode, err := getSubchartsTemplateData(chartDocumentationInfo)
+	if err != nil {
+		return chartTemplateData{}, err
+	}
+
+	extra, err := getExtraTemplateData(chartDocumentationInfo.Settings())
+	if err != nil {
+		return chartTemplateData{}, err
+	}
+
+	namedSources, err := getNamedSourcesTemplateData(chartDocumentationInfo)
+	if err != nil {
+		return chartTemplateData{}, err
+	}
+
+	headingOffset, err := getHeadingOffset(chartDocumentationInfo)
+	if err != nil {
+		return chartTemplateData{}, err
+	}
+
+	changes, err := getChangesTemplateData(chartDocumentationInfo)
+	if err != nil {
+		return chartTemplateData{}, err
+	}
+
+	links, err := getLinksTemplateData(chartDocumentationInfo)
+	if err != nil {
+		return chartTemplateData{}, err
+	}
+
 	return chartTemplateData{
 		ChartDocumentationInfo: chartDocumentationInfo,
 		Values:                 valuesTableRows,
+		GlobalValues:           withoutAnchors(globalValuesTableRows),
+		RequiredValues:         withoutAnchors(requiredValuesTableRows),
+		ExtraValues:            extraValues,
+		DependencyValues:       dependencyValues,
+		Subcharts:              subcharts,
+		SubchartsMode:          subchartsMode,
+		ChartRepository:        getChartRepositoryTemplateData(chartDocumentationInfo),
+		HeadingOffset:          headingOffset,
+		BadgeStyle:             getBadgeStyleTemplateData(chartDocumentationInfo),
+		Extra:                  extra,
+		HelmDocsVersion:        strings.TrimPrefix(helmDocsVersion(), "v"),
+		GeneratedDate:          getGeneratedDate(chartDocumentationInfo.Settings()),
+		NamedSources:           namedSources,
+		Changes:                changes,
+		Links:                  links,
+		KubeVersions:           getKubeVersionsTemplateData(chartDocumentationInfo),
+		valueAnchors:           valueAnchors,
 	}, nil
 }
@@ -0,0 +1,90 @@
+package document
+
+import (
+	"testing"
+
+	"github.com/norwoodj/helm-docs/pkg/helm"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChartSettingAnnotations(t *testing.T) {
+	viper.Set("template-file", []string{"README.md.gotmpl"})
+	viper.Set("heading-offset", 1)
+	defer viper.Set("template-file", nil)
+	defer viper.Set("heading-offset", nil)
+
+	chartDocumentationInfo := helm.ChartDocumentationInfo{}
+	assert.Equal(t, []string{"README.md.gotmpl"}, chartSettingList(chartDocumentationInfo, templateFileAnnotation, "template-file"))
+
+	headingOffset, err := getHeadingOffset(chartDocumentationInfo)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, headingOffset)
+
+	chartDocumentationInfo.Annotations = map[string]string{
+		templateFileAnnotation:  "header.gotmpl, README.md.gotmpl,",
+		headingOffsetAnnotation: "2",
+	}
+
+	assert.Equal(t, []string{"header.gotmpl", "README.md.gotmpl"}, chartSettingList(chartDocumentationInfo, templateFileAnnotation, "template-file"))
+
+	headingOffset, err = getHeadingOffset(chartDocumentationInfo)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, headingOffset)
+	assert.Equal(t, "###", headingWithOffset(headingOffset)(1))
+
+	chartDocumentationInfo.Annotations[headingOffsetAnnotation] = "two"
+	_, err = getHeadingOffset(chartDocumentationInfo)
+	assert.NotNil(t, err)
+}
+
+func TestLinkDependencyDocumentation(t *testing.T) {
+	viper.Set("output-file", "README.md")
+	defer viper.Set("output-file", nil)
+
+	dependencies := []helm.ChartRequirementsItem{
+		{Name: "common", LocalChart: &helm.LocalChart{Directory: "charts/common"}},
+		{Name: "db", LocalChart: &helm.LocalChart{
+			ChartMeta: helm.ChartMeta{Annotations: map[string]string{outputFileAnnotation: "docs/db.md"}},
+			Directory: "other/db",
+		}},
+		{Name: "redis"},
+	}
+
+	linked := linkDependencyDocumentation(viper.GetViper(), dependencies, "charts/app/README.md")
+	assert.Equal(t, "../common/README.md", linked[0].DocumentationLink)
+	assert.Equal(t, "../../other/db/docs/db.md", linked[1].DocumentationLink)
+	assert.Equal(t, "", linked[2].DocumentationLink)
+	assert.Equal(t, "", dependencies[0].DocumentationLink)
+}
+
+func TestDependencyValuesTemplateData(t *testing.T) {
+	chartDocumentationInfo := helm.ChartDocumentationInfo{
+		ChartDependencyValues: []helm.ChartDependencyValues{
+			{
+				Name: "cache",
+				ChartValuesFile: helm.ChartValuesFile{
+					Values:       map[interface{}]interface{}{"replicas": 3, "auth": map[interface{}]interface{}{"enabled": true}},
+					Descriptions: map[string]helm.ChartValueDescription{"replicas": {Description: "Number of redis replicas"}},
+					LineNumbers:  map[string]int{"replicas": 2, "auth.enabled": 1},
+				},
+			},
+			{Name: "empty", ChartValuesFile: helm.ChartValuesFile{Values: map[interface{}]interface{}{}}},
+		},
+	}
+
+	dependencyValues, err := getDependencyValuesTemplateData(chartDocumentationInfo)
+	assert.Nil(t, err)
+	assert.Len(t, dependencyValues, 1)
+	assert.Equal(t, "cache", dependencyValues[0].Name)
+
+	keys := make([]string, 0)
+	for _, v := range dependencyValues[0].Values {
+		keys = append(keys, v.Key)
+		if v.Key == "cache.replicas" {
+			assert.Equal(t, "Number of redis replicas", v.Description)
+		}
+	}
+
+	assert.Equal(t, []string{"cache.auth.enabled", "cache.replicas"}, keys)
+}
@@ -0,0 +1,123 @@
+package document
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/norwoodj/helm-docs/pkg/helm"
+	"github.com/russross/blackfriday"
+	"github.com/spf13/viper"
+)
+
+const (
+	markdownRendererName = "markdown"
+	markdownExtension    = ".md"
+)
+
+// Renderer renders the documentation of a chart in an output format. The chart's templates are always markdown, so
+// renderers are given the markdown rendered from them, along with what was parsed from the chart for formats that would
+// rather be built from that directly
+type Renderer interface {
+	// Name is the name of the output format, given to --output-format to choose it
+	Name() string
+
+	// FileExtension is the extension of files in the output format, including the dot, e.g. ".html", which replaces the
+	// markdown extension of output files
+	FileExtension() string
+
+	// Render renders the documentation of a chart in the output format
+	Render(chartDocumentationInfo helm.ChartDocumentationInfo, markdown string) (string, error)
+}
+
+type funcRenderer struct {
+	name          string
+	fileExtension string
+	render        func(chartDocumentationInfo helm.ChartDocumentationInfo, markdown string) (string, error)
+}
+
+func (r funcRenderer) Name() string {
+	return r.name
+}
+
+func (r funcRenderer) FileExtension() string {
+	return r.fileExtension
+}
+
+func (r funcRenderer) Render(chartDocumentationInfo helm.ChartDocumentationInfo, markdown string) (string, error) {
+	return r.render(chartDocumentationInfo, markdown)
+}
+
+// NewRenderer returns a renderer of the given output format from a function rendering documentation in it
+func NewRenderer(name string, fileExtension string, render func(chartDocumentationInfo helm.ChartDocumentationInfo, markdown string) (string, error)) Renderer {
+	return funcRenderer{name: name, fileExtension: fileExtension, render: render}
+}
+
+var renderersMutex sync.RWMutex
+
+var renderers = map[string]Renderer{
+	markdownRendererName: NewRenderer(markdownRendererName, markdownExtension, func(_ helm.ChartDocumentationInfo, markdown string) (string, error) {
+		return markdown, nil
+	}),
+	"html": NewRenderer("html", ".html", func(_ helm.ChartDocumentationInfo, markdown string) (string, error) {
+		return string(blackfriday.MarkdownCommon([]byte(markdown))), nil
+	}),
+}
+
+// RegisterRenderer makes an output format available to --output-format, e.g. from a fork or a program embedding
+// helm-docs. Formats can't be registered twice under the same name
+func RegisterRenderer(renderer Renderer) error {
+	renderersMutex.Lock()
+	defer renderersMutex.Unlock()
+
+	if _, ok := renderers[renderer.Name()]; ok {
+		return fmt.Errorf("an output format named %s is already registered", renderer.Name())
+	}
+
+	renderers[renderer.Name()] = renderer
+	return nil
+}
+
+// RendererNames lists the names of the output formats registered, sorted
+func RendererNames() []string {
+	renderersMutex.RLock()
+	defer renderersMutex.RUnlock()
+
+	names := make([]string, 0, len(renderers))
+	for n := range renderers {
+		names = append(names, n)
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+// getRenderer returns the renderer of the output format documentation is rendered in, markdown by default
+func getRenderer(chartSettings *viper.Viper) (Renderer, error) {
+	name := chartSettings.GetString("output-format")
+	if name == "" {
+		name = markdownRendererName
+	}
+
+	renderersMutex.RLock()
+	renderer, ok := renderers[name]
+	renderersMutex.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("invalid output format %s, must be one of (%s)", name, strings.Join(RendererNames(), ", "))
+	}
+
+	return renderer, nil
+}
+
+// rendererOutputPath gives output files named with the markdown extension, as by default, the extension of the output
+// format instead, e.g. README.html rather than README.md
+func rendererOutputPath(renderer Renderer, outputPath string) string {
+	if filepath.Ext(outputPath) != markdownExtension {
+		return outputPath
+	}
+
+	return strings.TrimSuffix(outputPath, markdownExtension) + renderer.FileExtension()
+}
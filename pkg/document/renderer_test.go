@@ -0,0 +1,37 @@
+package document
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/norwoodj/helm-docs/pkg/helm"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterRenderer(t *testing.T) {
+	wikiRenderer := NewRenderer("test-wiki", ".xml", func(chartDocumentationInfo helm.ChartDocumentationInfo, markdown string) (string, error) {
+		return fmt.Sprintf("<page title=%q/>", chartDocumentationInfo.Name), nil
+	})
+
+	assert.Nil(t, RegisterRenderer(wikiRenderer))
+	defer delete(renderers, "test-wiki")
+	assert.NotNil(t, RegisterRenderer(wikiRenderer))
+	assert.Equal(t, []string{"html", "markdown", "test-wiki"}, RendererNames())
+
+	viper.Set("output-format", "test-wiki")
+	defer viper.Set("output-format", nil)
+
+	renderer, err := getRenderer(viper.GetViper())
+	assert.Nil(t, err)
+	assert.Equal(t, "chart/README.xml", rendererOutputPath(renderer, "chart/README.md"))
+	assert.Equal(t, "chart/VALUES.txt", rendererOutputPath(renderer, "chart/VALUES.txt"))
+
+	rendered, err := renderer.Render(helm.ChartDocumentationInfo{ChartMeta: helm.ChartMeta{Name: "app"}}, "# app")
+	assert.Nil(t, err)
+	assert.Equal(t, `<page title="app"/>`, rendered)
+
+	viper.Set("output-format", "unknown")
+	_, err = getRenderer(viper.GetViper())
+	assert.NotNil(t, err)
+}
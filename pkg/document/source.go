@@ -0,0 +1,140 @@
+package document
+
+import (
+	"fmt"
+	"math"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/norwoodj/helm-docs/pkg/helm"
+	"github.com/norwoodj/helm-docs/pkg/util"
+	log "github.com/sirupsen/logrus"
+)
+
+// Chart.yaml annotation giving the url under which the files of the chart's directory are browsed on its git host, e.g.
+// https://github.com/org/charts/blob/main/charts/app
+const sourceURLAnnotation = "helm-docs.io/source-url"
+
+// Matches urls of a directory on github or gitlab at a git ref, e.g. https://github.com/org/charts/tree/main/charts/app,
+// under which the chart's files are browsed with /blob/ in place of /tree/
+var sourceTreeURLRegex = regexp.MustCompile(`^(https?://[^/]+/[^/]+/[^/]+)/(-/)?tree/(.+?)/?$`)
+
+// Matches urls of a whole repository on github or gitlab, e.g. https://github.com/org/charts
+var sourceRepositoryURLRegex = regexp.MustCompile(`^https?://(github\.com|gitlab\.com)/[^/]+/[^/]+?(\.git)?/?$`)
+
+// chartRepositoryPath finds the path of a chart's directory relative to the root of the git repository it's in, which
+// needn't be that of the working directory. git resolves symlinks in the path of the root it finds, so those in the
+// chart's directory are too
+func chartRepositoryPath(chartDirectory string) (string, error) {
+	gitRepositoryRoot, err := util.FindGitRepositoryRootOf(chartDirectory)
+	if err != nil {
+		return "", fmt.Errorf("failed to find the git repository chart directory %s is in: %s", chartDirectory, err)
+	}
+
+	absoluteChartDirectory, err := filepath.Abs(chartDirectory)
+	if err != nil {
+		return "", err
+	}
+
+	absoluteChartDirectory, err = filepath.EvalSymlinks(absoluteChartDirectory)
+	if err != nil {
+		return "", err
+	}
+
+	relativePath, err := filepath.Rel(gitRepositoryRoot, absoluteChartDirectory)
+	if err != nil {
+		return "", err
+	}
+
+	if strings.HasPrefix(relativePath, "..") {
+		return "", fmt.Errorf("chart directory %s isn't in the git repository at %s", chartDirectory, gitRepositoryRoot)
+	}
+
+	return filepath.ToSlash(relativePath), nil
+}
+
+// joinSourceURL appends a chart's path in its git repository to the url under which the repository's files are browsed
+func joinSourceURL(baseURL string, chartDirectory string) (string, error) {
+	relativePath, err := chartRepositoryPath(chartDirectory)
+	if err != nil {
+		return "", err
+	}
+
+	baseURL = strings.TrimSuffix(baseURL, "/")
+	if relativePath == "." {
+		return baseURL, nil
+	}
+
+	return baseURL + "/" + relativePath, nil
+}
+
+// getChartSourceURL finds the url under which the files of a chart's directory are browsed on its git host. That's
+// given by the chart's source url annotation, or the --source-base-url flag with the chart's path in the repository
+// appended, or otherwise derived from the first of the chart's sources on github or gitlab. Charts whose files can't be
+// located, e.g. packaged charts, have none
+func getChartSourceURL(chartDocumentationInfo helm.ChartDocumentationInfo) (string, error) {
+	if sourceURL, ok := chartDocumentationInfo.Annotations[sourceURLAnnotation]; ok {
+		return strings.TrimSuffix(sourceURL, "/"), nil
+	}
+
+	if helm.IsChartArchive(chartDocumentationInfo.ChartDirectory) {
+		return "", nil
+	}
+
+	if baseURL := chartDocumentationInfo.Settings().GetString("source-base-url"); baseURL != "" {
+		return joinSourceURL(baseURL, chartDocumentationInfo.ChartDirectory)
+	}
+
+	for _, source := range chartDocumentationInfo.Sources {
+		if match := sourceTreeURLRegex.FindStringSubmatch(source); match != nil {
+			return fmt.Sprintf("%s/%sblob/%s", match[1], match[2], match[3]), nil
+		}
+
+		if match := sourceRepositoryURLRegex.FindStringSubmatch(source); match != nil {
+			blobPath := "/blob/HEAD"
+			if match[1] == "gitlab.com" {
+				blobPath = "/-/blob/HEAD"
+			}
+
+			repositoryURL := strings.TrimSuffix(strings.TrimSuffix(source, "/"), ".git")
+			return joinSourceURL(repositoryURL+blobPath, chartDocumentationInfo.ChartDirectory)
+		}
+	}
+
+	return "", nil
+}
+
+// linkValueRowsToSource links each value row to the line on which its key is defined in the given values file, so
+// that readers can jump from the documentation to the default's definition
+func linkValueRowsToSource(valueRows []valueRow, sourceURL string, valuesFilename string, lineNumbers map[string]int) {
+	if sourceURL == "" {
+		return
+	}
+
+	for i := range valueRows {
+		if lineNumber := valueRowLineNumber(valueRows[i].Key, lineNumbers); lineNumber != math.MaxInt32 {
+			valueRows[i].SourceURL = fmt.Sprintf("%s/%s#L%d", sourceURL, valuesFilename, lineNumber)
+		}
+	}
+}
+
+// getValuesSourceURL returns the url of the chart's directory to link value rows to, when enabled. Failing to find it
+// leaves the values unlinked with a warning rather than failing the chart's documentation
+func getValuesSourceURL(chartDocumentationInfo helm.ChartDocumentationInfo) string {
+	if !chartDocumentationInfo.Settings().GetBool("link-values-to-source") {
+		return ""
+	}
+
+	sourceURL, err := getChartSourceURL(chartDocumentationInfo)
+	if err != nil {
+		log.Warnf("Error finding the source url of chart %s, not linking its values: %s", chartDocumentationInfo.ChartDirectory, err)
+		return ""
+	}
+
+	if sourceURL == "" {
+		log.Debugf("Found no source url of chart %s, not linking its values", chartDocumentationInfo.ChartDirectory)
+	}
+
+	return sourceURL
+}
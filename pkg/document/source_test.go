@@ -0,0 +1,105 @@
+package document
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/norwoodj/helm-docs/pkg/helm"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestGitRepository creates a git repository holding an empty chart directory, returning the path of the chart
+// directory. The repository is outside of any other, so that the chart isn't found in the repository being tested
+func newTestGitRepository(t *testing.T, chartPath string) (string, func()) {
+	repositoryDirectory, err := ioutil.TempDir("", "helm-docs-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := exec.Command("git", "init", "-q", repositoryDirectory).Run(); err != nil {
+		os.RemoveAll(repositoryDirectory)
+		t.Skipf("Failed to create a git repository: %s", err)
+	}
+
+	chartDirectory := filepath.Join(repositoryDirectory, chartPath)
+	assert.Nil(t, os.MkdirAll(chartDirectory, 0755))
+
+	return chartDirectory, func() { os.RemoveAll(repositoryDirectory) }
+}
+
+func TestChartRepositoryPath(t *testing.T) {
+	chartDirectory, removeRepository := newTestGitRepository(t, "charts/app")
+	defer removeRepository()
+
+	relativePath, err := chartRepositoryPath(chartDirectory)
+	assert.Nil(t, err)
+	assert.Equal(t, "charts/app", relativePath)
+
+	relativePath, err = chartRepositoryPath(filepath.Dir(filepath.Dir(chartDirectory)))
+	assert.Nil(t, err)
+	assert.Equal(t, ".", relativePath)
+
+	outsideDirectory, err := ioutil.TempDir("", "helm-docs-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(outsideDirectory)
+
+	// Whether or not the temporary directory is itself in a git repository, git looks no further than it
+	defer os.Setenv("GIT_CEILING_DIRECTORIES", os.Getenv("GIT_CEILING_DIRECTORIES"))
+	os.Setenv("GIT_CEILING_DIRECTORIES", filepath.Dir(outsideDirectory))
+
+	_, err = chartRepositoryPath(outsideDirectory)
+	assert.NotNil(t, err)
+}
+
+func TestGetChartSourceURL(t *testing.T) {
+	chartDirectory, removeRepository := newTestGitRepository(t, "example-charts/nginx-ingress")
+	defer removeRepository()
+
+	chartDocumentationInfo := helm.ChartDocumentationInfo{ChartDirectory: chartDirectory}
+
+	sourceURL, err := getChartSourceURL(chartDocumentationInfo)
+	assert.Nil(t, err)
+	assert.Equal(t, "", sourceURL)
+
+	chartDocumentationInfo.Sources = []string{"https://example.com/nginx", "https://github.com/org/charts"}
+	sourceURL, err = getChartSourceURL(chartDocumentationInfo)
+	assert.Nil(t, err)
+	assert.Equal(t, "https://github.com/org/charts/blob/HEAD/example-charts/nginx-ingress", sourceURL)
+
+	chartDocumentationInfo.Sources = []string{"https://gitlab.com/org/charts/-/tree/main/nginx-ingress/"}
+	sourceURL, err = getChartSourceURL(chartDocumentationInfo)
+	assert.Nil(t, err)
+	assert.Equal(t, "https://gitlab.com/org/charts/-/blob/main/nginx-ingress", sourceURL)
+
+	viper.Set("source-base-url", "https://git.example.com/charts/src/branch/main/")
+	defer viper.Set("source-base-url", nil)
+
+	sourceURL, err = getChartSourceURL(chartDocumentationInfo)
+	assert.Nil(t, err)
+	assert.Equal(t, "https://git.example.com/charts/src/branch/main/example-charts/nginx-ingress", sourceURL)
+
+	chartDocumentationInfo.Annotations = map[string]string{sourceURLAnnotation: "https://github.com/org/nginx/blob/v1/chart/"}
+	sourceURL, err = getChartSourceURL(chartDocumentationInfo)
+	assert.Nil(t, err)
+	assert.Equal(t, "https://github.com/org/nginx/blob/v1/chart", sourceURL)
+}
+
+func TestLinkValueRowsToSource(t *testing.T) {
+	valueRows := []valueRow{{Key: "image.tag"}, {Key: "ingress.hosts[0].host"}, {Key: "commentedOut"}}
+	lineNumbers := map[string]int{"image": 3, "image.tag": 5, "ingress.hosts": 9}
+
+	linkValueRowsToSource(valueRows, "", "values.yaml", lineNumbers)
+	assert.Equal(t, "", valueRows[0].SourceURL)
+
+	linkValueRowsToSource(valueRows, "https://github.com/org/charts/blob/HEAD/app", "values.yaml", lineNumbers)
+	assert.Equal(t, "https://github.com/org/charts/blob/HEAD/app/values.yaml#L5", valueRows[0].SourceURL)
+	assert.Equal(t, "https://github.com/org/charts/blob/HEAD/app/values.yaml#L9", valueRows[1].SourceURL)
+	assert.Equal(t, "", valueRows[2].SourceURL)
+}
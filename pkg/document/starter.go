@@ -0,0 +1,102 @@
+package document
+
+// StarterDocumentationTemplate is the template file written by helm-docs init. Its sections are those of the default
+// template, so that it renders the same documentation until it's edited, with the other built in sections commented
+// out where they'd fit
+const StarterDocumentationTemplate = `{{/*
+  This template is rendered into the chart's documentation by helm-docs. Each {{ template "chart.<name>" . }} action
+  renders one of helm-docs' built in templates. Optional sections are commented out in blocks like this one: move
+  them out of the comments to include them, and rearrange the sections or add your own markdown between them as you
+  like. See https://github.com/norwoodj/helm-docs#available-templates for all of the templates available.
+*/ -}}
+{{ template "chart.header" . }}
+{{/* Badges of the chart's version, type, app version, kubeVersion and license:
+{{ template "chart.badgesSection" . }}
+
+*/ -}}
+{{/* The description from Chart.yaml */ -}}
+{{ template "chart.description" . }}
+
+{{/* A table of contents linking each of the headings that follow it:
+{{ template "chart.toc" . }}
+
+*/ -}}
+{{ template "chart.versionLine" . }}
+
+{{/* The app version, type and keywords from Chart.yaml:
+{{ template "chart.appVersionLine" . }}
+
+{{ template "chart.typeLine" . }}
+
+{{ template "chart.keywordsLine" . }}
+
+*/ -}}
+{{/* The chart's home page and sources, as a single line or as a list:
+{{ template "chart.sourcesSection" . }}
+*/ -}}
+{{ template "chart.sourceLinkLine" . }}
+
+{{/* Commands installing the chart from the repository given with --chart-repository-url, the notes shown after
+installing it, and the tests run by helm test:
+{{ template "chart.installSection" . }}
+
+{{ template "chart.notesSection" . }}
+
+{{ template "chart.testsSection" . }}
+
+*/ -}}
+{{/* The chart's dependencies from Chart.yaml or requirements.yaml */ -}}
+{{ template "chart.requirementsSection" . }}
+
+{{/* The custom resource definitions in crds/, the resources installed with --render-resources along with those of
+API versions removed in later Kubernetes releases, and the images used:
+{{ template "chart.crdsSection" . }}
+
+{{ template "chart.resourcesSection" . }}
+
+{{ template "chart.apiDeprecationsSection" . }}
+
+{{ template "chart.imagesSection" . }}
+
+*/ -}}
+{{/* The named templates a library chart provides, defined in its templates/_*.tpl files:
+{{ template "chart.namedTemplatesSection" . }}
+
+*/ -}}
+{{/* The values that must be set when installing the chart, whether the default values are valid against
+values.schema.json, the messages linting reports with --helm-lint, and the values changed since the version given
+with --compare-values-to:
+{{ template "chart.requiredValuesSection" . }}
+
+{{ template "chart.schemaValidationSection" . }}
+
+{{ template "chart.lintSection" . }}
+
+{{ template "chart.valuesDiffSection" . }}
+
+*/ -}}
+{{/* The chart's values, documented by comments in values.yaml, followed by those of its dependencies with
+--document-dependency-values, and its subcharts with --document-subcharts */ -}}
+{{ template "chart.valuesSection" (withoutGlobalValues .) }}
+{{- if .GlobalValues }}
+
+{{ template "chart.globalValuesSection" (withoutGlobalValues .) }}
+{{- end }}
+{{- template "chart.dependencyValuesSections" . }}
+{{- template "chart.subchartsSection" . }}
+{{/* The chart's annotations, the changes and links from its Artifact Hub annotations, its changelog from git with
+--git-changelog, its license, and a footer stating when the documentation was generated:
+
+{{ template "chart.annotationsSection" . }}
+
+{{ template "chart.changesSection" . }}
+
+{{ template "chart.changelogSection" . }}
+
+{{ template "chart.linksSection" . }}
+
+{{ template "chart.licenseSection" . }}
+
+{{ template "chart.generatedFooter" . }}
+*/ -}}
+`
@@ -0,0 +1,38 @@
+package document
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/norwoodj/helm-docs/pkg/helm"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStarterDocumentationTemplate(t *testing.T) {
+	chartDirectory, err := ioutil.TempDir("", "helm-docs-starter")
+	assert.Nil(t, err)
+	defer os.RemoveAll(chartDirectory)
+
+	chartDocumentationInfo := helm.ChartDocumentationInfo{
+		ChartDirectory: chartDirectory,
+		ChartMeta:      helm.ChartMeta{Name: "starter", Version: "0.1.0", Description: "A starter chart"},
+	}
+
+	render := func() string {
+		documentationTemplate, err := newChartDocumentationTemplate(chartDocumentationInfo, []string{"README.md.gotmpl"})
+		assert.Nil(t, err)
+
+		var rendered bytes.Buffer
+		assert.Nil(t, documentationTemplate.Execute(&rendered, chartTemplateData{ChartDocumentationInfo: chartDocumentationInfo}))
+		return rendered.String()
+	}
+
+	defaultDocumentation := render()
+
+	templatePath := filepath.Join(chartDirectory, "README.md.gotmpl")
+	assert.Nil(t, ioutil.WriteFile(templatePath, []byte(StarterDocumentationTemplate), 0644))
+	assert.Equal(t, defaultDocumentation, render())
+}
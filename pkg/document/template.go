@@ -1,14 +1,18 @@
 package document
 
 import (
+	"fmt"
 	"io/ioutil"
 	"os"
 	"path"
+	"path/filepath"
+	"sort"
 	"strings"
 	"text/template"
 
 	"github.com/Masterminds/sprig"
 	"github.com/norwoodj/helm-docs/pkg/helm"
+	"github.com/norwoodj/helm-docs/pkg/settings"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 )
@@ -22,19 +26,52 @@ const defaultDocumentationTemplate = `{{ template "chart.header" . }}
 
 {{ template "chart.requirementsSection" . }}
 
-{{ template "chart.valuesSection" . }}
+{{ template "chart.valuesSection" (withoutGlobalValues .) }}
+{{- if .GlobalValues }}
+
+{{ template "chart.globalValuesSection" (withoutGlobalValues .) }}
+{{- end }}
+{{- template "chart.dependencyValuesSections" . }}
+{{- template "chart.subchartsSection" . }}
+`
+
+// Library charts have no values to speak of, as they can't be installed, so are documented by their named templates
+const defaultLibraryDocumentationTemplate = `{{ template "chart.header" . }}
+{{ template "chart.description" . }}
+
+{{ template "chart.versionLine" . }}
+
+{{ template "chart.sourceLinkLine" . }}
+
+{{ template "chart.requirementsSection" . }}
+
+{{ template "chart.namedTemplatesSection" . }}
 `
 
+// getDefaultDocumentationTemplate returns the template used for charts without template files of their own
+func getDefaultDocumentationTemplate(chartDocumentationInfo helm.ChartDocumentationInfo) string {
+	if chartDocumentationInfo.IsLibraryChart() {
+		return defaultLibraryDocumentationTemplate
+	}
+
+	return defaultDocumentationTemplate
+}
+
 func getHeaderTemplate() string {
 	headerTemplateBuilder := strings.Builder{}
 	headerTemplateBuilder.WriteString(`{{ define "chart.header" }}`)
+	headerTemplateBuilder.WriteString("{{ if .HeadingOffset }}{{ heading 1 }} {{ .Name }}{{ else }}")
 	headerTemplateBuilder.WriteString("{{ .Name }}\n")
 	headerTemplateBuilder.WriteString(`{{ repeat (len .Name) "=" }}`)
-	headerTemplateBuilder.WriteString("{{ end }}")
+	headerTemplateBuilder.WriteString("{{ end }}{{ end }}")
 
 	return headerTemplateBuilder.String()
 }
 
+func getTableOfContentsTemplate() string {
+	return `{{ define "chart.toc" }}` + tableOfContentsMarker + "{{ end }}"
+}
+
 func getDescriptionTemplate() string {
 	descriptionBuilder := strings.Builder{}
 	descriptionBuilder.WriteString(`{{ define "chart.description" }}`)
@@ -44,6 +81,15 @@ func getDescriptionTemplate() string {
 	return descriptionBuilder.String()
 }
 
+func getIconTemplate() string {
+	iconBuilder := strings.Builder{}
+	iconBuilder.WriteString(`{{ define "chart.icon" }}`)
+	iconBuilder.WriteString(`{{ if .Icon }}<img src="{{ .Icon }}" alt="{{ .Name }}" width="100">{{ end }}`)
+	iconBuilder.WriteString("{{ end }}")
+
+	return iconBuilder.String()
+}
+
 func getTypeTemplate() string {
 	typeBuilder := strings.Builder{}
 	typeBuilder.WriteString(`{{ define "chart.type" }}{{ .Type }}{{ end }}\n`)
@@ -64,6 +110,58 @@ func getVersionTemplates() string {
 	return versionBuilder.String()
 }
 
+func getAppVersionTemplates() string {
+	appVersionBuilder := strings.Builder{}
+	appVersionBuilder.WriteString(`{{ define "chart.appVersion" }}{{ .AppVersion }}{{ end }}\n`)
+	appVersionBuilder.WriteString(`{{ define "chart.appVersionLine" }}`)
+	appVersionBuilder.WriteString("{{ if .AppVersion }}Current app version is `{{ .AppVersion }}`{{ end }}")
+	appVersionBuilder.WriteString("{{ end }}")
+
+	return appVersionBuilder.String()
+}
+
+// Badges are rendered with shields.io, in whose badge paths dashes and underscores must be doubled, and spaces replaced
+// with underscores
+func getBadgeTemplates() string {
+	badgeBuilder := strings.Builder{}
+	badgeBuilder.WriteString(`{{ define "chart.badgeText" }}{{ . | replace "-" "--" | replace "_" "__" | replace " " "_" | urlquery }}{{ end }}`)
+
+	// Renders a badge from a dict of its Label, Message and the Style of the chart's badges
+	badgeBuilder.WriteString(`{{ define "chart.badge" }}`)
+	badgeBuilder.WriteString(`![{{ .Label }}: {{ .Message }}]({{ .Style.BaseURL }}/badge/{{ template "chart.badgeText" .Label }}-{{ template "chart.badgeText" .Message }}-{{ .Style.Color }}?style={{ .Style.Style }})`)
+	badgeBuilder.WriteString("{{ end }}")
+
+	badgeBuilder.WriteString(`{{ define "chart.versionBadge" }}`)
+	badgeBuilder.WriteString(`{{ if .Version }}{{ template "chart.badge" (dict "Label" "Version" "Message" .Version "Style" .BadgeStyle) }}{{ end }}`)
+	badgeBuilder.WriteString("{{ end }}")
+
+	badgeBuilder.WriteString(`{{ define "chart.typeBadge" }}`)
+	badgeBuilder.WriteString(`{{ if .Type }}{{ template "chart.badge" (dict "Label" "Type" "Message" .Type "Style" .BadgeStyle) }}{{ end }}`)
+	badgeBuilder.WriteString("{{ end }}")
+
+	badgeBuilder.WriteString(`{{ define "chart.appVersionBadge" }}`)
+	badgeBuilder.WriteString(`{{ if .AppVersion }}{{ template "chart.badge" (dict "Label" "AppVersion" "Message" .AppVersion "Style" .BadgeStyle) }}{{ end }}`)
+	badgeBuilder.WriteString("{{ end }}")
+
+	badgeBuilder.WriteString(`{{ define "chart.kubeVersionBadge" }}`)
+	badgeBuilder.WriteString(`{{ if .KubeVersion }}{{ template "chart.badge" (dict "Label" "KubeVersion" "Message" .KubeVersion "Style" .BadgeStyle) }}{{ end }}`)
+	badgeBuilder.WriteString("{{ end }}")
+
+	badgeBuilder.WriteString(`{{ define "chart.licenseBadge" }}`)
+	badgeBuilder.WriteString(`{{ if .ChartLicense.ID }}{{ template "chart.badge" (dict "Label" "License" "Message" .ChartLicense.ID "Style" .BadgeStyle) }}{{ end }}`)
+	badgeBuilder.WriteString("{{ end }}")
+
+	badgeBuilder.WriteString(`{{ define "chart.badgesSection" }}`)
+	badgeBuilder.WriteString(`{{ template "chart.versionBadge" . }}`)
+	badgeBuilder.WriteString(`{{ if .Type }} {{ template "chart.typeBadge" . }}{{ end }}`)
+	badgeBuilder.WriteString(`{{ if .AppVersion }} {{ template "chart.appVersionBadge" . }}{{ end }}`)
+	badgeBuilder.WriteString(`{{ if .KubeVersion }} {{ template "chart.kubeVersionBadge" . }}{{ end }}`)
+	badgeBuilder.WriteString(`{{ if .ChartLicense.ID }} {{ template "chart.licenseBadge" . }}{{ end }}`)
+	badgeBuilder.WriteString("{{ end }}")
+
+	return badgeBuilder.String()
+}
+
 func getSourceLinkTemplates() string {
 	sourceLinkBuilder := strings.Builder{}
 	sourceLinkBuilder.WriteString(`{{ define "chart.sourceLink" }}`)
@@ -74,18 +172,505 @@ func getSourceLinkTemplates() string {
 	sourceLinkBuilder.WriteString("{{ if .Home }}Source code can be found [here]({{ .Home }}){{ end }}")
 	sourceLinkBuilder.WriteString("{{ end }}")
 
+	sourceLinkBuilder.WriteString(`{{ define "chart.sources" }}`)
+	sourceLinkBuilder.WriteString("{{ range $i, $source := .Sources }}{{ if $i }}\n{{ end }}* <{{ $source }}>{{ end }}")
+	sourceLinkBuilder.WriteString("{{ end }}")
+
+	sourceLinkBuilder.WriteString(`{{ define "chart.namedSources" }}`)
+	sourceLinkBuilder.WriteString("{{ range $i, $source := .NamedSources }}{{ if $i }}\n{{ end }}* [{{ $source.Name }}]({{ $source.URL }}){{ end }}")
+	sourceLinkBuilder.WriteString("{{ end }}")
+
+	sourceLinkBuilder.WriteString(`{{ define "chart.sourcesHeader" }}{{ heading 2 }} {{ translate "Source Code" }}{{ end }}`)
+
+	sourceLinkBuilder.WriteString(`{{ define "chart.sourcesSection" }}`)
+	sourceLinkBuilder.WriteString("{{ if .NamedSources }}")
+	sourceLinkBuilder.WriteString(`{{ template "chart.sourcesHeader" . }}`)
+	sourceLinkBuilder.WriteString("\n\n")
+	sourceLinkBuilder.WriteString(`{{ template "chart.namedSources" . }}`)
+	sourceLinkBuilder.WriteString("{{ end }}")
+	sourceLinkBuilder.WriteString("{{ end }}")
+
 	return sourceLinkBuilder.String()
 }
 
+func getGeneratedFooterTemplate() string {
+	footerBuilder := strings.Builder{}
+	footerBuilder.WriteString(`{{ define "chart.generatedFooter" }}`)
+	footerBuilder.WriteString("Autogenerated from chart metadata using [helm-docs{{ with .HelmDocsVersion }} v{{ . }}{{ end }}](https://github.com/norwoodj/helm-docs)")
+	footerBuilder.WriteString("{{ with .GeneratedDate }} on {{ . }}{{ end }}")
+	footerBuilder.WriteString("{{ end }}")
+
+	return footerBuilder.String()
+}
+
+func getKeywordsTemplates() string {
+	keywordsBuilder := strings.Builder{}
+	keywordsBuilder.WriteString(`{{ define "chart.keywords" }}`)
+	keywordsBuilder.WriteString("{{ range $i, $keyword := .Keywords }}{{ if $i }} {{ end }}{{ codeSpan $keyword }}{{ end }}")
+	keywordsBuilder.WriteString("{{ end }}\n")
+
+	keywordsBuilder.WriteString(`{{ define "chart.keywordsLine" }}`)
+	keywordsBuilder.WriteString(`{{ if .Keywords }}Keywords: {{ template "chart.keywords" . }}{{ end }}`)
+	keywordsBuilder.WriteString("{{ end }}")
+
+	return keywordsBuilder.String()
+}
+
+func getAnnotationsTableTemplates() string {
+	annotationsSectionBuilder := strings.Builder{}
+	annotationsSectionBuilder.WriteString(`{{ define "chart.annotationsHeader" }}{{ heading 2 }} {{ translate "Chart Annotations" }}{{ end }}`)
+
+	annotationsSectionBuilder.WriteString(`{{ define "chart.annotationsTable" }}`)
+	annotationsSectionBuilder.WriteString("| Annotation | Value |\n")
+	annotationsSectionBuilder.WriteString("|------------|-------|\n")
+	annotationsSectionBuilder.WriteString("  {{- range $key, $value := .Annotations }}")
+	annotationsSectionBuilder.WriteString("\n| {{ escapeTableCell $key }} | {{ escapeTableCell $value }} |")
+	annotationsSectionBuilder.WriteString("  {{- end }}")
+	annotationsSectionBuilder.WriteString("{{ end }}")
+
+	annotationsSectionBuilder.WriteString(`{{ define "chart.annotationsSection" }}`)
+	annotationsSectionBuilder.WriteString("{{ if .Annotations }}")
+	annotationsSectionBuilder.WriteString(`{{ template "chart.annotationsHeader" . }}`)
+	annotationsSectionBuilder.WriteString("\n\n")
+	annotationsSectionBuilder.WriteString(`{{ template "chart.annotationsTable" . }}`)
+	annotationsSectionBuilder.WriteString("{{ end }}")
+	annotationsSectionBuilder.WriteString("{{ end }}")
+
+	return annotationsSectionBuilder.String()
+}
+
+func getMaintainersTableTemplates() string {
+	maintainersSectionBuilder := strings.Builder{}
+	maintainersSectionBuilder.WriteString(`{{ define "chart.maintainersHeader" }}{{ heading 2 }} {{ translate "Maintainers" }}{{ end }}`)
+
+	// Maintainers with GitHub accounts, found with --github-maintainers, get a column of their avatars linking to their
+	// profiles
+	maintainersSectionBuilder.WriteString(`{{ define "chart.maintainersTable" }}`)
+	maintainersSectionBuilder.WriteString("{{- $github := false }}{{ range .Maintainers }}{{ if .GitHubUsername }}{{ $github = true }}{{ end }}{{ end }}")
+	maintainersSectionBuilder.WriteString("{{- if $github }}")
+	maintainersSectionBuilder.WriteString("| | Name | Email | Url |\n")
+	maintainersSectionBuilder.WriteString("|-|------|-------|-----|\n")
+	maintainersSectionBuilder.WriteString("  {{- range .Maintainers }}")
+	maintainersSectionBuilder.WriteString("\n| {{ with .GitHubUsername }}[![@{{ . }}](https://github.com/{{ . }}.png?size=32)](https://github.com/{{ . }}){{ end }} | ")
+	maintainersSectionBuilder.WriteString("{{ if .GitHubUsername }}[{{ escapeTableCell .Name }}](https://github.com/{{ .GitHubUsername }}){{ else }}{{ escapeTableCell .Name }}{{ end }} | ")
+	maintainersSectionBuilder.WriteString("{{ with .Email }}<{{ . }}>{{ end }} | {{ with .Url }}<{{ . }}>{{ end }} |")
+	maintainersSectionBuilder.WriteString("  {{- end }}")
+	maintainersSectionBuilder.WriteString("{{- else }}")
+	maintainersSectionBuilder.WriteString("| Name | Email | Url |\n")
+	maintainersSectionBuilder.WriteString("|------|-------|-----|\n")
+	maintainersSectionBuilder.WriteString("  {{- range .Maintainers }}")
+	maintainersSectionBuilder.WriteString("\n| {{ escapeTableCell .Name }} | {{ with .Email }}<{{ . }}>{{ end }} | {{ with .Url }}<{{ . }}>{{ end }} |")
+	maintainersSectionBuilder.WriteString("  {{- end }}")
+	maintainersSectionBuilder.WriteString("{{- end }}")
+	maintainersSectionBuilder.WriteString("{{ end }}")
+
+	maintainersSectionBuilder.WriteString(`{{ define "chart.maintainersSection" }}`)
+	maintainersSectionBuilder.WriteString("{{ if .Maintainers }}")
+	maintainersSectionBuilder.WriteString(`{{ template "chart.maintainersHeader" . }}`)
+	maintainersSectionBuilder.WriteString("\n\n")
+	maintainersSectionBuilder.WriteString(`{{ template "chart.maintainersTable" . }}`)
+	maintainersSectionBuilder.WriteString("{{ end }}")
+	maintainersSectionBuilder.WriteString("{{ end }}")
+
+	return maintainersSectionBuilder.String()
+}
+
+func getLicenseTemplates() string {
+	licenseSectionBuilder := strings.Builder{}
+	licenseSectionBuilder.WriteString(`{{ define "chart.licenseHeader" }}{{ heading 2 }} {{ translate "License" }}{{ end }}`)
+
+	licenseSectionBuilder.WriteString(`{{ define "chart.licenseSection" }}`)
+	licenseSectionBuilder.WriteString("{{ if or .ChartLicense.File .ChartLicense.ID }}")
+	licenseSectionBuilder.WriteString(`{{ template "chart.licenseHeader" . }}`)
+	licenseSectionBuilder.WriteString("\n\n")
+	licenseSectionBuilder.WriteString("{{ if .ChartLicense.ID }}This chart is licensed under the `{{ .ChartLicense.ID }}` license.{{ end }}")
+	licenseSectionBuilder.WriteString("{{ if and .ChartLicense.ID .ChartLicense.File }} {{ end }}")
+	licenseSectionBuilder.WriteString("{{ if .ChartLicense.File }}See [{{ .ChartLicense.File }}]({{ .ChartLicense.File }}) for the full license text.{{ end }}")
+	licenseSectionBuilder.WriteString("{{ end }}")
+	licenseSectionBuilder.WriteString("{{ end }}")
+
+	return licenseSectionBuilder.String()
+}
+
+func getChangesTemplates() string {
+	changesSectionBuilder := strings.Builder{}
+	changesSectionBuilder.WriteString(`{{ define "chart.changesHeader" }}{{ heading 2 }} {{ translate "Changes in %s" .Version }}{{ end }}`)
+
+	changesSectionBuilder.WriteString(`{{ define "chart.changes" }}`)
+	changesSectionBuilder.WriteString("  {{- range $i, $change := .Changes }}")
+	changesSectionBuilder.WriteString("{{ if $i }}\n{{ end }}")
+	changesSectionBuilder.WriteString("* {{ if .Kind }}**{{ .Kind }}**: {{ end }}{{ .Description }}")
+	changesSectionBuilder.WriteString("{{ range .Links }} ([{{ .Name }}]({{ .URL }})){{ end }}")
+	changesSectionBuilder.WriteString("  {{- end }}")
+	changesSectionBuilder.WriteString("{{ end }}")
+
+	changesSectionBuilder.WriteString(`{{ define "chart.changesSection" }}`)
+	changesSectionBuilder.WriteString("{{ if .Changes }}")
+	changesSectionBuilder.WriteString(`{{ template "chart.changesHeader" . }}`)
+	changesSectionBuilder.WriteString("\n\n")
+	changesSectionBuilder.WriteString(`{{ template "chart.changes" . }}`)
+	changesSectionBuilder.WriteString("{{ end }}")
+	changesSectionBuilder.WriteString("{{ end }}")
+
+	return changesSectionBuilder.String()
+}
+
+func getChangelogTemplates() string {
+	changelogSectionBuilder := strings.Builder{}
+	changelogSectionBuilder.WriteString(`{{ define "chart.changelogHeader" }}{{ heading 2 }} {{ translate "Changelog" }}{{ end }}`)
+
+	changelogSectionBuilder.WriteString(`{{ define "chart.changelog" }}`)
+	changelogSectionBuilder.WriteString("  {{- range $i, $release := .ChartChangelog }}")
+	changelogSectionBuilder.WriteString("{{ if $i }}\n\n{{ end }}")
+	changelogSectionBuilder.WriteString("{{ heading 3 }} {{ if .Version }}{{ .Version }}{{ if .Date }} ({{ .Date }}){{ end }}{{ else }}{{ translate \"Unreleased\" }}{{ end }}")
+	changelogSectionBuilder.WriteString("\n\n")
+	changelogSectionBuilder.WriteString("{{ range $j, $commit := .Commits }}")
+	changelogSectionBuilder.WriteString("{{ if $j }}\n{{ end }}")
+	changelogSectionBuilder.WriteString("* {{ .Subject }} ({{ codeSpan .ShortHash }})")
+	changelogSectionBuilder.WriteString("{{ end }}")
+	changelogSectionBuilder.WriteString("  {{- end }}")
+	changelogSectionBuilder.WriteString("{{ end }}")
+
+	changelogSectionBuilder.WriteString(`{{ define "chart.changelogSection" }}`)
+	changelogSectionBuilder.WriteString("{{ if .ChartChangelog }}")
+	changelogSectionBuilder.WriteString(`{{ template "chart.changelogHeader" . }}`)
+	changelogSectionBuilder.WriteString("\n\n")
+	changelogSectionBuilder.WriteString(`{{ template "chart.changelog" . }}`)
+	changelogSectionBuilder.WriteString("{{ end }}")
+	changelogSectionBuilder.WriteString("{{ end }}")
+
+	return changelogSectionBuilder.String()
+}
+
+func getLinksTemplates() string {
+	linksSectionBuilder := strings.Builder{}
+	linksSectionBuilder.WriteString(`{{ define "chart.linksHeader" }}{{ heading 2 }} {{ translate "Links" }}{{ end }}`)
+
+	linksSectionBuilder.WriteString(`{{ define "chart.links" }}`)
+	linksSectionBuilder.WriteString("  {{- range $i, $link := .Links }}")
+	linksSectionBuilder.WriteString("{{ if $i }}\n{{ end }}")
+	linksSectionBuilder.WriteString("* [{{ .Name }}]({{ .URL }})")
+	linksSectionBuilder.WriteString("  {{- end }}")
+	linksSectionBuilder.WriteString("{{ end }}")
+
+	linksSectionBuilder.WriteString(`{{ define "chart.linksSection" }}`)
+	linksSectionBuilder.WriteString("{{ if .Links }}")
+	linksSectionBuilder.WriteString(`{{ template "chart.linksHeader" . }}`)
+	linksSectionBuilder.WriteString("\n\n")
+	linksSectionBuilder.WriteString(`{{ template "chart.links" . }}`)
+	linksSectionBuilder.WriteString("{{ end }}")
+	linksSectionBuilder.WriteString("{{ end }}")
+
+	return linksSectionBuilder.String()
+}
+
+func getInstallTemplates() string {
+	installSectionBuilder := strings.Builder{}
+	installSectionBuilder.WriteString(`{{ define "chart.installHeader" }}{{ heading 2 }} {{ translate "Installing the Chart" }}{{ end }}`)
+
+	installSectionBuilder.WriteString(`{{ define "chart.installCommands" }}`)
+	installSectionBuilder.WriteString("```console\n")
+	installSectionBuilder.WriteString("$ helm repo add {{ .ChartRepository.Name }} {{ .ChartRepository.URL }}\n")
+	installSectionBuilder.WriteString("$ helm install my-release {{ .ChartRepository.Name }}/{{ .Name }} --version {{ .Version }}\n")
+	installSectionBuilder.WriteString("```")
+	installSectionBuilder.WriteString("{{ end }}")
+
+	installSectionBuilder.WriteString(`{{ define "chart.installSection" }}`)
+	installSectionBuilder.WriteString("{{ if .ChartRepository.URL }}")
+	installSectionBuilder.WriteString(`{{ template "chart.installHeader" . }}`)
+	installSectionBuilder.WriteString("\n\n")
+	installSectionBuilder.WriteString("To install the chart with the release name `my-release`:\n\n")
+	installSectionBuilder.WriteString(`{{ template "chart.installCommands" . }}`)
+	installSectionBuilder.WriteString("{{ end }}")
+	installSectionBuilder.WriteString("{{ end }}")
+
+	return installSectionBuilder.String()
+}
+
+func getNotesTemplates() string {
+	notesSectionBuilder := strings.Builder{}
+	notesSectionBuilder.WriteString(`{{ define "chart.notesHeader" }}{{ heading 2 }} {{ translate "Post Installation Notes" }}{{ end }}`)
+
+	notesSectionBuilder.WriteString(`{{ define "chart.notes" }}`)
+	notesSectionBuilder.WriteString("```\n{{ .ChartNotes }}\n```")
+	notesSectionBuilder.WriteString("{{ end }}")
+
+	notesSectionBuilder.WriteString(`{{ define "chart.notesSection" }}`)
+	notesSectionBuilder.WriteString("{{ if .ChartNotes }}")
+	notesSectionBuilder.WriteString(`{{ template "chart.notesHeader" . }}`)
+	notesSectionBuilder.WriteString("\n\n")
+	notesSectionBuilder.WriteString(`{{ template "chart.notes" . }}`)
+	notesSectionBuilder.WriteString("{{ end }}")
+	notesSectionBuilder.WriteString("{{ end }}")
+
+	return notesSectionBuilder.String()
+}
+
+func getTestsTemplates() string {
+	testsSectionBuilder := strings.Builder{}
+	testsSectionBuilder.WriteString(`{{ define "chart.testsHeader" }}{{ heading 2 }} {{ translate "Chart Tests" }}{{ end }}`)
+
+	testsSectionBuilder.WriteString(`{{ define "chart.testsTable" }}`)
+	testsSectionBuilder.WriteString("| Test | Kind | Template |\n")
+	testsSectionBuilder.WriteString("|------|------|----------|\n")
+	testsSectionBuilder.WriteString("  {{- range .ChartTests }}")
+	testsSectionBuilder.WriteString("\n| {{ .Name }} | {{ .Kind }} | `{{ .File }}` |")
+	testsSectionBuilder.WriteString("  {{- end }}")
+	testsSectionBuilder.WriteString("{{ end }}")
+
+	testsSectionBuilder.WriteString(`{{ define "chart.testsCommands" }}`)
+	testsSectionBuilder.WriteString("```console\n")
+	testsSectionBuilder.WriteString("$ helm test my-release\n")
+	testsSectionBuilder.WriteString("```")
+	testsSectionBuilder.WriteString("{{ end }}")
+
+	testsSectionBuilder.WriteString(`{{ define "chart.testsSection" }}`)
+	testsSectionBuilder.WriteString("{{ if .ChartTests }}")
+	testsSectionBuilder.WriteString(`{{ template "chart.testsHeader" . }}`)
+	testsSectionBuilder.WriteString("\n\n")
+	testsSectionBuilder.WriteString("The chart ships tests verifying a release, which can be run once the release `my-release` is installed with:\n\n")
+	testsSectionBuilder.WriteString(`{{ template "chart.testsCommands" . }}`)
+	testsSectionBuilder.WriteString("\n\n")
+	testsSectionBuilder.WriteString(`{{ template "chart.testsTable" . }}`)
+	testsSectionBuilder.WriteString("{{ end }}")
+	testsSectionBuilder.WriteString("{{ end }}")
+
+	return testsSectionBuilder.String()
+}
+
+func getNamedTemplatesTableTemplates() string {
+	namedTemplatesSectionBuilder := strings.Builder{}
+	namedTemplatesSectionBuilder.WriteString(`{{ define "chart.namedTemplatesHeader" }}{{ heading 2 }} {{ translate "Named Templates" }}{{ end }}`)
+
+	namedTemplatesSectionBuilder.WriteString(`{{ define "chart.namedTemplatesTable" }}`)
+	namedTemplatesSectionBuilder.WriteString("| Template | Description |\n")
+	namedTemplatesSectionBuilder.WriteString("|----------|-------------|\n")
+	namedTemplatesSectionBuilder.WriteString("  {{- range .ChartNamedTemplates }}")
+	namedTemplatesSectionBuilder.WriteString("\n| {{ codeSpan .Name }} | {{ escapeTableCell .Description }} |")
+	namedTemplatesSectionBuilder.WriteString("  {{- end }}")
+	namedTemplatesSectionBuilder.WriteString("{{ end }}")
+
+	namedTemplatesSectionBuilder.WriteString(`{{ define "chart.namedTemplatesSection" }}`)
+	namedTemplatesSectionBuilder.WriteString("{{ if .ChartNamedTemplates }}")
+	namedTemplatesSectionBuilder.WriteString(`{{ template "chart.namedTemplatesHeader" . }}`)
+	namedTemplatesSectionBuilder.WriteString("\n\n")
+	namedTemplatesSectionBuilder.WriteString(`{{ template "chart.namedTemplatesTable" . }}`)
+	namedTemplatesSectionBuilder.WriteString("{{ end }}")
+	namedTemplatesSectionBuilder.WriteString("{{ end }}")
+
+	return namedTemplatesSectionBuilder.String()
+}
+
+func getSchemaValidationTemplates() string {
+	schemaValidationSectionBuilder := strings.Builder{}
+	schemaValidationSectionBuilder.WriteString(`{{ define "chart.schemaValidationHeader" }}{{ heading 2 }} {{ translate "Values Schema Validation" }}{{ end }}`)
+
+	schemaValidationSectionBuilder.WriteString(`{{ define "chart.schemaValidationTable" }}`)
+	schemaValidationSectionBuilder.WriteString("| Key | Violation |\n")
+	schemaValidationSectionBuilder.WriteString("|-----|-----------|\n")
+	schemaValidationSectionBuilder.WriteString("  {{- range .ChartSchemaValidation.Violations }}")
+	schemaValidationSectionBuilder.WriteString("\n| {{ .Key }} | {{ escapeTableCell .Description }} |")
+	schemaValidationSectionBuilder.WriteString("  {{- end }}")
+	schemaValidationSectionBuilder.WriteString("{{ end }}")
+
+	schemaValidationSectionBuilder.WriteString(`{{ define "chart.schemaValidationSection" }}`)
+	schemaValidationSectionBuilder.WriteString("{{ with .ChartSchemaValidation.SchemaFile }}")
+	schemaValidationSectionBuilder.WriteString(`{{ template "chart.schemaValidationHeader" $ }}`)
+	schemaValidationSectionBuilder.WriteString("\n\n")
+	schemaValidationSectionBuilder.WriteString("{{ if $.ChartSchemaValidation.Violations }}")
+	schemaValidationSectionBuilder.WriteString("The default values are not valid against `{{ . }}`:\n\n")
+	schemaValidationSectionBuilder.WriteString(`{{ template "chart.schemaValidationTable" $ }}`)
+	schemaValidationSectionBuilder.WriteString("{{ else }}")
+	schemaValidationSectionBuilder.WriteString("The default values are valid against `{{ . }}`.")
+	schemaValidationSectionBuilder.WriteString("{{ end }}")
+	schemaValidationSectionBuilder.WriteString("{{ end }}")
+	schemaValidationSectionBuilder.WriteString("{{ end }}")
+
+	return schemaValidationSectionBuilder.String()
+}
+
+func getLintTemplates() string {
+	lintSectionBuilder := strings.Builder{}
+	lintSectionBuilder.WriteString(`{{ define "chart.lintHeader" }}{{ heading 2 }} {{ translate "Lint Results" }}{{ end }}`)
+
+	lintSectionBuilder.WriteString(`{{ define "chart.lintSummary" }}`)
+	lintSectionBuilder.WriteString("Linting the chart reports {{ .ChartLint.Count \"ERROR\" }} errors, {{ .ChartLint.Count \"WARNING\" }} warnings")
+	lintSectionBuilder.WriteString(" and {{ .ChartLint.Count \"INFO\" }} informational messages for the chart.")
+	lintSectionBuilder.WriteString("{{ end }}")
+
+	lintSectionBuilder.WriteString(`{{ define "chart.lintTable" }}`)
+	lintSectionBuilder.WriteString("| Severity | File | Message |\n")
+	lintSectionBuilder.WriteString("|----------|------|---------|\n")
+	lintSectionBuilder.WriteString("  {{- range .ChartLint.Messages }}")
+	lintSectionBuilder.WriteString("\n| {{ .Severity }} | {{ if .File }}`{{ .File }}`{{ end }} | {{ escapeTableCell .Message }} |")
+	lintSectionBuilder.WriteString("  {{- end }}")
+	lintSectionBuilder.WriteString("{{ end }}")
+
+	lintSectionBuilder.WriteString(`{{ define "chart.lintSection" }}`)
+	lintSectionBuilder.WriteString("{{ if .ChartLint.Linted }}")
+	lintSectionBuilder.WriteString(`{{ template "chart.lintHeader" . }}`)
+	lintSectionBuilder.WriteString("\n\n")
+	lintSectionBuilder.WriteString(`{{ template "chart.lintSummary" . }}`)
+	lintSectionBuilder.WriteString("{{ if .ChartLint.Messages }}")
+	lintSectionBuilder.WriteString("\n\n")
+	lintSectionBuilder.WriteString(`{{ template "chart.lintTable" . }}`)
+	lintSectionBuilder.WriteString("{{ end }}")
+	lintSectionBuilder.WriteString("{{ end }}")
+	lintSectionBuilder.WriteString("{{ end }}")
+
+	return lintSectionBuilder.String()
+}
+
+func getCRDsTableTemplates() string {
+	crdsSectionBuilder := strings.Builder{}
+	crdsSectionBuilder.WriteString(`{{ define "chart.crdsHeader" }}{{ heading 2 }} {{ translate "Custom Resource Definitions" }}{{ end }}`)
+
+	crdsSectionBuilder.WriteString(`{{ define "chart.crdsTable" }}`)
+	crdsSectionBuilder.WriteString("| Group | Kind | Versions | Scope |\n")
+	crdsSectionBuilder.WriteString("|-------|------|----------|-------|\n")
+	crdsSectionBuilder.WriteString("  {{- range .ChartCRDs }}")
+	crdsSectionBuilder.WriteString("\n| {{ escapeTableCell .Group }} | {{ escapeTableCell .Kind }} | {{ join \", \" .Versions | escapeTableCell }} | {{ escapeTableCell .Scope }} |")
+	crdsSectionBuilder.WriteString("  {{- end }}")
+	crdsSectionBuilder.WriteString("{{ end }}")
+
+	crdsSectionBuilder.WriteString(`{{ define "chart.crdsSection" }}`)
+	crdsSectionBuilder.WriteString("{{ if .ChartCRDs }}")
+	crdsSectionBuilder.WriteString(`{{ template "chart.crdsHeader" . }}`)
+	crdsSectionBuilder.WriteString("\n\n")
+	crdsSectionBuilder.WriteString(`{{ template "chart.crdsTable" . }}`)
+	crdsSectionBuilder.WriteString("{{ end }}")
+	crdsSectionBuilder.WriteString("{{ end }}")
+
+	return crdsSectionBuilder.String()
+}
+
+func getResourcesTableTemplates() string {
+	resourcesSectionBuilder := strings.Builder{}
+	resourcesSectionBuilder.WriteString(`{{ define "chart.resourcesHeader" }}{{ heading 2 }} {{ translate "Kubernetes Resources" }}{{ end }}`)
+
+	resourcesSectionBuilder.WriteString(`{{ define "chart.resourcesTable" }}`)
+	resourcesSectionBuilder.WriteString("| Kind | Name | Namespace |\n")
+	resourcesSectionBuilder.WriteString("|------|------|-----------|\n")
+	resourcesSectionBuilder.WriteString("  {{- range .ChartResources }}")
+	resourcesSectionBuilder.WriteString("\n| {{ escapeTableCell .Kind }} | {{ escapeTableCell .Name }} | {{ escapeTableCell .Namespace }} |")
+	resourcesSectionBuilder.WriteString("  {{- end }}")
+	resourcesSectionBuilder.WriteString("{{ end }}")
+
+	resourcesSectionBuilder.WriteString(`{{ define "chart.resourcesSection" }}`)
+	resourcesSectionBuilder.WriteString("{{ if .ChartResources }}")
+	resourcesSectionBuilder.WriteString(`{{ template "chart.resourcesHeader" . }}`)
+	resourcesSectionBuilder.WriteString("\n\n")
+	resourcesSectionBuilder.WriteString(`{{ template "chart.resourcesTable" . }}`)
+	resourcesSectionBuilder.WriteString("{{ end }}")
+	resourcesSectionBuilder.WriteString("{{ end }}")
+
+	return resourcesSectionBuilder.String()
+}
+
+func getKubeVersionsTableTemplates() string {
+	kubeVersionsSectionBuilder := strings.Builder{}
+	kubeVersionsSectionBuilder.WriteString(`{{ define "chart.kubeVersionsHeader" }}{{ heading 2 }} {{ translate "Kubernetes Compatibility" }}{{ end }}`)
+
+	// A single row matrix of the minor Kubernetes versions checked, headed by the kubeVersion constraint they're checked
+	// against
+	kubeVersionsSectionBuilder.WriteString(`{{ define "chart.kubeVersionsTable" }}`)
+	kubeVersionsSectionBuilder.WriteString("| kubeVersion |{{ range .KubeVersions }} {{ .Version }} |{{ end }}\n")
+	kubeVersionsSectionBuilder.WriteString("|-------------|{{ range .KubeVersions }}------|{{ end }}\n")
+	kubeVersionsSectionBuilder.WriteString("| {{ codeSpan .KubeVersion | escapeTableCell }} |")
+	kubeVersionsSectionBuilder.WriteString("{{ range .KubeVersions }} {{ if .Supported }}✅{{ else if .PartiallySupported }}⚠️{{ else }}❌{{ end }} |{{ end }}")
+	kubeVersionsSectionBuilder.WriteString("{{ end }}")
+
+	kubeVersionsSectionBuilder.WriteString(`{{ define "chart.kubeVersionsSection" }}`)
+	kubeVersionsSectionBuilder.WriteString("{{ if .KubeVersions }}")
+	kubeVersionsSectionBuilder.WriteString(`{{ template "chart.kubeVersionsHeader" . }}`)
+	kubeVersionsSectionBuilder.WriteString("\n\n")
+	kubeVersionsSectionBuilder.WriteString(`{{ template "chart.kubeVersionsTable" . }}`)
+	kubeVersionsSectionBuilder.WriteString("{{ end }}")
+	kubeVersionsSectionBuilder.WriteString("{{ end }}")
+
+	return kubeVersionsSectionBuilder.String()
+}
+
+func getAPIDeprecationsTableTemplates() string {
+	apiDeprecationsSectionBuilder := strings.Builder{}
+	apiDeprecationsSectionBuilder.WriteString(`{{ define "chart.apiDeprecationsHeader" }}{{ heading 2 }} {{ translate "Kubernetes API Deprecations" }}{{ end }}`)
+
+	apiDeprecationsSectionBuilder.WriteString(`{{ define "chart.apiDeprecationsTable" }}`)
+	apiDeprecationsSectionBuilder.WriteString("| Kind | Name | API Version | Removed In | Replacement |\n")
+	apiDeprecationsSectionBuilder.WriteString("|------|------|-------------|------------|-------------|\n")
+	apiDeprecationsSectionBuilder.WriteString("  {{- range .ChartAPIDeprecations }}")
+	apiDeprecationsSectionBuilder.WriteString("\n| {{ escapeTableCell .Kind }} | {{ escapeTableCell .Name }} | {{ escapeTableCell .APIVersion }} | {{ escapeTableCell .RemovedIn }} | {{ escapeTableCell .Replacement }} |")
+	apiDeprecationsSectionBuilder.WriteString("  {{- end }}")
+	apiDeprecationsSectionBuilder.WriteString("{{ end }}")
+
+	apiDeprecationsSectionBuilder.WriteString(`{{ define "chart.apiDeprecationsSection" }}`)
+	apiDeprecationsSectionBuilder.WriteString("{{ if .ChartAPIDeprecations }}")
+	apiDeprecationsSectionBuilder.WriteString(`{{ template "chart.apiDeprecationsHeader" . }}`)
+	apiDeprecationsSectionBuilder.WriteString("\n\n")
+	apiDeprecationsSectionBuilder.WriteString("With its default values the chart installs resources of API versions which are removed in later Kubernetes ")
+	apiDeprecationsSectionBuilder.WriteString("releases, so it can't be installed or upgraded on clusters running those releases:\n\n")
+	apiDeprecationsSectionBuilder.WriteString(`{{ template "chart.apiDeprecationsTable" . }}`)
+	apiDeprecationsSectionBuilder.WriteString("{{ end }}")
+	apiDeprecationsSectionBuilder.WriteString("{{ end }}")
+
+	return apiDeprecationsSectionBuilder.String()
+}
+
+func getImagesTableTemplates() string {
+	imagesSectionBuilder := strings.Builder{}
+	imagesSectionBuilder.WriteString(`{{ define "chart.imagesHeader" }}{{ heading 2 }} {{ translate "Container Images" }}{{ end }}`)
+
+	imagesSectionBuilder.WriteString(`{{ define "chart.imagesTable" }}`)
+	imagesSectionBuilder.WriteString("| Repository | Tag | Pull Policy |\n")
+	imagesSectionBuilder.WriteString("|------------|-----|-------------|\n")
+	imagesSectionBuilder.WriteString("  {{- range .ChartImages }}")
+	imagesSectionBuilder.WriteString("\n| {{ escapeTableCell .Repository }} | {{ escapeTableCell .Tag }} | {{ escapeTableCell .PullPolicy }} |")
+	imagesSectionBuilder.WriteString("  {{- end }}")
+	imagesSectionBuilder.WriteString("{{ end }}")
+
+	imagesSectionBuilder.WriteString(`{{ define "chart.imagesSection" }}`)
+	imagesSectionBuilder.WriteString("{{ if .ChartImages }}")
+	imagesSectionBuilder.WriteString(`{{ template "chart.imagesHeader" . }}`)
+	imagesSectionBuilder.WriteString("\n\n")
+	imagesSectionBuilder.WriteString(`{{ template "chart.imagesTable" . }}`)
+	imagesSectionBuilder.WriteString("{{ end }}")
+	imagesSectionBuilder.WriteString("{{ end }}")
+
+	return imagesSectionBuilder.String()
+}
+
 func getRequirementsTableTemplates() string {
 	requirementsSectionBuilder := strings.Builder{}
-	requirementsSectionBuilder.WriteString(`{{ define "chart.requirementsHeader" }}## Chart Requirements{{ end }}`)
+	requirementsSectionBuilder.WriteString(`{{ define "chart.requirementsHeader" }}{{ heading 2 }} {{ translate "Chart Requirements" }}{{ end }}`)
 
+	// Dependencies on charts found in the chart search roots link to their documentation
+	requirementsSectionBuilder.WriteString(`{{ define "chart.requirementName" }}{{ if .DocumentationLink }}[{{ .Name }}]({{ .DocumentationLink }}){{ else }}{{ .Name }}{{ end }}{{ end }}`)
+
+	// Dependencies fetched from OCI registries are described by their own Chart.yaml, in which case the table gets columns
+	// for their app versions and descriptions
 	requirementsSectionBuilder.WriteString(`{{ define "chart.requirementsTable" }}`)
+	requirementsSectionBuilder.WriteString("{{- $described := false }}{{ range .Dependencies }}{{ if or .Description .AppVersion }}{{ $described = true }}{{ end }}{{ end }}")
+	requirementsSectionBuilder.WriteString("{{- if $described }}")
+	requirementsSectionBuilder.WriteString("| Repository | Name | Version | App Version | Description |\n")
+	requirementsSectionBuilder.WriteString("|------------|------|---------|-------------|-------------|\n")
+	requirementsSectionBuilder.WriteString("  {{- range .Dependencies }}")
+	requirementsSectionBuilder.WriteString("\n| {{ .Repository }} | {{ template \"chart.requirementName\" . }} | {{ .Version }} | {{ .AppVersion }} | {{ .Description }} |")
+	requirementsSectionBuilder.WriteString("  {{- end }}")
+	requirementsSectionBuilder.WriteString("{{- else }}")
 	requirementsSectionBuilder.WriteString("| Repository | Name | Version |\n")
 	requirementsSectionBuilder.WriteString("|------------|------|---------|\n")
 	requirementsSectionBuilder.WriteString("  {{- range .Dependencies }}")
-	requirementsSectionBuilder.WriteString("\n| {{ .Repository }} | {{ .Name }} | {{ .Version }} |")
+	requirementsSectionBuilder.WriteString("\n| {{ .Repository }} | {{ template \"chart.requirementName\" . }} | {{ .Version }} |")
+	requirementsSectionBuilder.WriteString("  {{- end }}")
+	requirementsSectionBuilder.WriteString("{{- end }}")
+	requirementsSectionBuilder.WriteString("{{ end }}")
+
+	requirementsSectionBuilder.WriteString(`{{ define "chart.requirementsTableFull" }}`)
+	requirementsSectionBuilder.WriteString("| Repository | Name | Version | Alias | Condition | Tags | Import Values |\n")
+	requirementsSectionBuilder.WriteString("|------------|------|---------|-------|-----------|------|---------------|\n")
+	requirementsSectionBuilder.WriteString("  {{- range .Dependencies }}")
+	requirementsSectionBuilder.WriteString("\n| {{ .Repository }} | {{ template \"chart.requirementName\" . }} | {{ .Version }} | {{ .Alias }} | {{ .Condition }} | {{ join \", \" .Tags }} | ")
+	requirementsSectionBuilder.WriteString(`{{ range $i, $v := .ImportValues }}{{ if $i }}, {{ end }}{{ if kindIs "string" $v }}{{ $v }}{{ else }}{{ $v.child }} → {{ $v.parent }}{{ end }}{{ end }} |`)
 	requirementsSectionBuilder.WriteString("  {{- end }}")
 	requirementsSectionBuilder.WriteString("{{ end }}")
 
@@ -102,74 +687,468 @@ func getRequirementsTableTemplates() string {
 
 func getValuesTableTemplates() string {
 	valuesSectionBuilder := strings.Builder{}
-	valuesSectionBuilder.WriteString(`{{ define "chart.valuesHeader" }}## Chart Values{{ end }}`)
+	valuesSectionBuilder.WriteString(`{{ define "chart.valuesHeader" }}{{ heading 2 }} {{ translate "Chart Values" }}{{ end }}`)
+
+	valuesSectionBuilder.WriteString(`{{ define "chart.valueAllowedValues" }}`)
+	valuesSectionBuilder.WriteString("{{ if .Allowed }} Allowed values: {{ range $i, $v := .Allowed }}{{ if $i }}, {{ end }}{{ codeSpan $v | escapeTableCell }}{{ end }}{{ end }}")
+	valuesSectionBuilder.WriteString("{{ end }}")
+
+	// Renders the key of a value row, linked to its definition in the values file when values are linked to their source
+	valuesSectionBuilder.WriteString(`{{ define "chart.valueKey" }}`)
+	valuesSectionBuilder.WriteString("{{ if .SourceURL }}[{{ .Key }}]({{ .SourceURL }}){{ else }}{{ .Key }}{{ end }}")
+	valuesSectionBuilder.WriteString("{{ end }}")
+
+	// Renders the anchor of a value row, which the chart's own values have for links to them, e.g. with valueLink
+	valuesSectionBuilder.WriteString(`{{ define "chart.valueAnchor" }}`)
+	valuesSectionBuilder.WriteString(`{{ with .Anchor }}<a id="{{ . }}"></a>{{ end }}`)
+	valuesSectionBuilder.WriteString("{{ end }}")
 
 	valuesSectionBuilder.WriteString(`{{ define "chart.valuesTable" }}`)
 	valuesSectionBuilder.WriteString("| Key | Type | Default | Description |\n")
 	valuesSectionBuilder.WriteString("|-----|------|---------|-------------|\n")
 	valuesSectionBuilder.WriteString("  {{- range .Values }}")
-	valuesSectionBuilder.WriteString("\n| {{ .Key }} | {{ .Type }} | {{ .Default }} | {{ .Description }} |")
+	valuesSectionBuilder.WriteString("\n| {{ template \"chart.valueAnchor\" . }}{{ if .Required }}**{{ template \"chart.valueKey\" . }}**{{ else }}{{ template \"chart.valueKey\" . }}{{ end }} | {{ .Type }} | {{ .Default }} | {{ .Description }}{{ template \"chart.valueAllowedValues\" . }} |")
 	valuesSectionBuilder.WriteString("  {{- end }}")
 	valuesSectionBuilder.WriteString("{{ end }}")
 
+	// Renders the values of one of the chart's extra values files given its name, or the values under a key path given a
+	// dict of the "root" key path and the "ctx" of the chart's template data
+	valuesSectionBuilder.WriteString(`{{ define "chart.valuesTableFor" }}`)
+	valuesSectionBuilder.WriteString(`{{ if kindIs "string" . }}`)
+	valuesSectionBuilder.WriteString(`{{ template "chart.valuesTable" (dict "Values" (extraValueRows .)) }}`)
+	valuesSectionBuilder.WriteString("{{ else }}")
+	valuesSectionBuilder.WriteString(`{{ template "chart.valuesTable" (dict "Values" (valueRowsUnder .root .ctx)) }}`)
+	valuesSectionBuilder.WriteString("{{ end }}")
+	valuesSectionBuilder.WriteString("{{ end }}")
+
+	// Renders the values table, split into a table per section, under a heading of its name, if any value has a section
+	valuesSectionBuilder.WriteString(`{{ define "chart.valuesSectionsTables" }}`)
+	valuesSectionBuilder.WriteString("{{ with valueSections .Values }}")
+	valuesSectionBuilder.WriteString("{{ range $i, $section := . }}")
+	valuesSectionBuilder.WriteString("{{ if $i }}\n\n{{ end }}")
+	valuesSectionBuilder.WriteString(`{{ heading 3 }} {{ with $section.Name }}{{ . }}{{ else }}{{ translate "Other Values" }}{{ end }}`)
+	valuesSectionBuilder.WriteString("\n\n")
+	valuesSectionBuilder.WriteString(`{{ template "chart.valuesTable" $section }}`)
+	valuesSectionBuilder.WriteString("{{ end }}")
+	valuesSectionBuilder.WriteString("{{ else }}")
+	valuesSectionBuilder.WriteString(`{{ template "chart.valuesTable" . }}`)
+	valuesSectionBuilder.WriteString("{{ end }}")
+	valuesSectionBuilder.WriteString("{{ end }}")
+
 	valuesSectionBuilder.WriteString(`{{ define "chart.valuesSection" }}`)
 	valuesSectionBuilder.WriteString("{{ if .Values }}")
 	valuesSectionBuilder.WriteString(`{{ template "chart.valuesHeader" . }}`)
 	valuesSectionBuilder.WriteString("\n\n")
-	valuesSectionBuilder.WriteString(`{{ template "chart.valuesTable" . }}`)
+	valuesSectionBuilder.WriteString(`{{ template "chart.valuesSectionsTables" . }}`)
 	valuesSectionBuilder.WriteString("{{ end }}")
 	valuesSectionBuilder.WriteString("{{ end }}")
 
 	return valuesSectionBuilder.String()
 }
 
-func getDocumentationTemplate(chartDirectory string) (string, error) {
-	templateFile := viper.GetString("template-file")
-	templateFileForChart := path.Join(chartDirectory, templateFile)
+func getGlobalValuesTableTemplates() string {
+	globalValuesSectionBuilder := strings.Builder{}
+	globalValuesSectionBuilder.WriteString(`{{ define "chart.globalValuesHeader" }}{{ heading 2 }} {{ translate "Global Values" }}{{ end }}`)
+
+	globalValuesSectionBuilder.WriteString(`{{ define "chart.globalValuesTable" }}`)
+	globalValuesSectionBuilder.WriteString(`{{ template "chart.valuesTable" (dict "Values" .GlobalValues) }}`)
+	globalValuesSectionBuilder.WriteString("{{ end }}")
+
+	globalValuesSectionBuilder.WriteString(`{{ define "chart.globalValuesSection" }}`)
+	globalValuesSectionBuilder.WriteString("{{ if .GlobalValues }}")
+	globalValuesSectionBuilder.WriteString(`{{ template "chart.globalValuesHeader" . }}`)
+	globalValuesSectionBuilder.WriteString("\n\n")
+	globalValuesSectionBuilder.WriteString(`{{ template "chart.globalValuesTable" . }}`)
+	globalValuesSectionBuilder.WriteString("{{ end }}")
+	globalValuesSectionBuilder.WriteString("{{ end }}")
+
+	return globalValuesSectionBuilder.String()
+}
+
+func getExtraValuesTableTemplates() string {
+	extraValuesSectionBuilder := strings.Builder{}
+	extraValuesSectionBuilder.WriteString(`{{ define "chart.extraValuesHeader" }}{{ heading 2 }} {{ translate "Chart Values (%s)" .ValuesFile }}{{ end }}`)
+
+	extraValuesSectionBuilder.WriteString(`{{ define "chart.extraValuesSections" }}`)
+	extraValuesSectionBuilder.WriteString("{{ range .ExtraValues }}")
+	extraValuesSectionBuilder.WriteString("{{ if .Values }}")
+	extraValuesSectionBuilder.WriteString(`{{ template "chart.extraValuesHeader" . }}`)
+	extraValuesSectionBuilder.WriteString("\n\n")
+	extraValuesSectionBuilder.WriteString(`{{ template "chart.valuesTable" . }}`)
+	extraValuesSectionBuilder.WriteString("\n\n")
+	extraValuesSectionBuilder.WriteString("{{ end }}")
+	extraValuesSectionBuilder.WriteString("{{ end }}")
+	extraValuesSectionBuilder.WriteString("{{ end }}")
+
+	return extraValuesSectionBuilder.String()
+}
+
+func getDependencyValuesTableTemplates() string {
+	dependencyValuesSectionBuilder := strings.Builder{}
+	dependencyValuesSectionBuilder.WriteString(`{{ define "chart.dependencyValuesHeader" }}{{ heading 2 }} {{ translate "%s Subchart Values" .Name }}{{ end }}`)
+
+	// Each section starts with blank lines rather than ending with them, so that the template renders nothing at all
+	// when dependency values aren't documented, and can follow the values section directly
+	dependencyValuesSectionBuilder.WriteString(`{{ define "chart.dependencyValuesSections" }}`)
+	dependencyValuesSectionBuilder.WriteString("{{ range .DependencyValues }}")
+	dependencyValuesSectionBuilder.WriteString("\n\n")
+	dependencyValuesSectionBuilder.WriteString(`{{ template "chart.dependencyValuesHeader" . }}`)
+	dependencyValuesSectionBuilder.WriteString("\n\n")
+	dependencyValuesSectionBuilder.WriteString(`{{ template "chart.valuesTable" . }}`)
+	dependencyValuesSectionBuilder.WriteString("{{ end }}")
+	dependencyValuesSectionBuilder.WriteString("{{ end }}")
+
+	return dependencyValuesSectionBuilder.String()
+}
+
+func getSubchartsTemplates() string {
+	subchartsSectionBuilder := strings.Builder{}
+	subchartsSectionBuilder.WriteString(`{{ define "chart.subchartsHeader" }}{{ heading 2 }} {{ translate "Subcharts" }}{{ end }}`)
+	subchartsSectionBuilder.WriteString(`{{ define "chart.subchartValuesHeader" }}{{ heading 3 }} {{ .ValuesPrefix }}{{ end }}`)
+
+	subchartsSectionBuilder.WriteString(`{{ define "chart.subchartsTable" }}`)
+	subchartsSectionBuilder.WriteString("| Subchart | Version | Values Key | Description |\n")
+	subchartsSectionBuilder.WriteString("|----------|---------|------------|-------------|\n")
+	subchartsSectionBuilder.WriteString("  {{- range .Subcharts }}")
+	subchartsSectionBuilder.WriteString("\n| [{{ .Name }}]({{ .ReadmePath }}) | {{ .Version }} | `{{ .ValuesPrefix }}` | {{ escapeTableCell .Description }} |")
+	subchartsSectionBuilder.WriteString("  {{- end }}")
+	subchartsSectionBuilder.WriteString("{{ end }}")
+
+	subchartsSectionBuilder.WriteString(`{{ define "chart.subchartValuesSections" }}`)
+	subchartsSectionBuilder.WriteString("{{ range .Subcharts }}{{ if .Values }}")
+	subchartsSectionBuilder.WriteString("\n\n")
+	subchartsSectionBuilder.WriteString(`{{ template "chart.subchartValuesHeader" . }}`)
+	subchartsSectionBuilder.WriteString("\n\n")
+	subchartsSectionBuilder.WriteString(`{{ template "chart.valuesTable" . }}`)
+	subchartsSectionBuilder.WriteString("{{ end }}{{ end }}")
+	subchartsSectionBuilder.WriteString("{{ end }}")
+
+	// Like the dependency values sections, starts with blank lines so that it renders nothing at all when subcharts
+	// aren't documented. Subcharts are either linked to their own documentation, or have their values embedded
+	subchartsSectionBuilder.WriteString(`{{ define "chart.subchartsSection" }}`)
+	subchartsSectionBuilder.WriteString("{{ if .Subcharts }}")
+	subchartsSectionBuilder.WriteString("\n\n")
+	subchartsSectionBuilder.WriteString(`{{ template "chart.subchartsHeader" . }}`)
+	subchartsSectionBuilder.WriteString(`{{ if eq .SubchartsMode "link" }}`)
+	subchartsSectionBuilder.WriteString("\n\n")
+	subchartsSectionBuilder.WriteString(`{{ template "chart.subchartsTable" . }}`)
+	subchartsSectionBuilder.WriteString("{{ else }}")
+	subchartsSectionBuilder.WriteString(`{{ template "chart.subchartValuesSections" . }}`)
+	subchartsSectionBuilder.WriteString("{{ end }}")
+	subchartsSectionBuilder.WriteString("{{ end }}")
+	subchartsSectionBuilder.WriteString("{{ end }}")
+
+	return subchartsSectionBuilder.String()
+}
+
+func getValuesDiffTemplates() string {
+	valuesDiffSectionBuilder := strings.Builder{}
+	valuesDiffSectionBuilder.WriteString(`{{ define "chart.valuesDiffHeader" }}{{ heading 2 }} {{ translate "Upgrade Notes: Values Changes Since %s" .ChartValuesDiff.PreviousVersion }}{{ end }}`)
+
+	valuesDiffSectionBuilder.WriteString(`{{ define "chart.valuesDiffTable" }}`)
+	valuesDiffSectionBuilder.WriteString("| Key | Change | Previous Default | New Default |\n")
+	valuesDiffSectionBuilder.WriteString("|-----|--------|------------------|-------------|\n")
+	valuesDiffSectionBuilder.WriteString("  {{- range .ChartValuesDiff.Changes }}")
+	valuesDiffSectionBuilder.WriteString("\n| {{ .Key }} | {{ .Change }} | {{ with .Previous }}{{ codeSpan . | escapeTableCell }}{{ end }} | {{ with .Current }}{{ codeSpan . | escapeTableCell }}{{ end }} |")
+	valuesDiffSectionBuilder.WriteString("  {{- end }}")
+	valuesDiffSectionBuilder.WriteString("{{ end }}")
+
+	valuesDiffSectionBuilder.WriteString(`{{ define "chart.valuesDiffSection" }}`)
+	valuesDiffSectionBuilder.WriteString("{{ if .ChartValuesDiff.PreviousVersion }}")
+	valuesDiffSectionBuilder.WriteString(`{{ template "chart.valuesDiffHeader" . }}`)
+	valuesDiffSectionBuilder.WriteString("\n\n")
+	valuesDiffSectionBuilder.WriteString("{{ if .ChartValuesDiff.Changes }}")
+	valuesDiffSectionBuilder.WriteString(`{{ template "chart.valuesDiffTable" . }}`)
+	valuesDiffSectionBuilder.WriteString("{{ else }}")
+	valuesDiffSectionBuilder.WriteString("The default values haven't changed since version {{ .ChartValuesDiff.PreviousVersion }}.")
+	valuesDiffSectionBuilder.WriteString("{{ end }}")
+	valuesDiffSectionBuilder.WriteString("{{ end }}")
+	valuesDiffSectionBuilder.WriteString("{{ end }}")
+
+	return valuesDiffSectionBuilder.String()
+}
+
+func getRequiredValuesTableTemplates() string {
+	requiredValuesSectionBuilder := strings.Builder{}
+	requiredValuesSectionBuilder.WriteString(`{{ define "chart.requiredValuesHeader" }}{{ heading 2 }} {{ translate "Required Values" }}{{ end }}`)
+
+	requiredValuesSectionBuilder.WriteString(`{{ define "chart.requiredValuesTable" }}`)
+	requiredValuesSectionBuilder.WriteString("| Key | Type | Description |\n")
+	requiredValuesSectionBuilder.WriteString("|-----|------|-------------|\n")
+	requiredValuesSectionBuilder.WriteString("  {{- range .RequiredValues }}")
+	requiredValuesSectionBuilder.WriteString("\n| {{ template \"chart.valueKey\" . }} | {{ .Type }} | {{ .Description }}{{ template \"chart.valueAllowedValues\" . }} |")
+	requiredValuesSectionBuilder.WriteString("  {{- end }}")
+	requiredValuesSectionBuilder.WriteString("{{ end }}")
+
+	requiredValuesSectionBuilder.WriteString(`{{ define "chart.requiredValuesSection" }}`)
+	requiredValuesSectionBuilder.WriteString("{{ if .RequiredValues }}")
+	requiredValuesSectionBuilder.WriteString(`{{ template "chart.requiredValuesHeader" . }}`)
+	requiredValuesSectionBuilder.WriteString("\n\n")
+	requiredValuesSectionBuilder.WriteString(`{{ template "chart.requiredValuesTable" . }}`)
+	requiredValuesSectionBuilder.WriteString("{{ end }}")
+	requiredValuesSectionBuilder.WriteString("{{ end }}")
+
+	return requiredValuesSectionBuilder.String()
+}
 
-	if _, err := os.Stat(templateFileForChart); os.IsNotExist(err) {
-		log.Debugf("Did not find template file %s for chart %s, using default template", templateFile, chartDirectory)
-		return defaultDocumentationTemplate, nil
+// templateFilePath resolves the path of a template file, which is relative to the chart directory unless absolute
+func templateFilePath(chartDirectory string, templateFile string) string {
+	if filepath.IsAbs(templateFile) {
+		return templateFile
 	}
 
-	log.Debugf("Using template file %s for chart %s", templateFile, chartDirectory)
-	templateContents, err := ioutil.ReadFile(templateFileForChart)
-	if err != nil {
-		return "", err
+	// Packaged charts share the template files of the directory they're in
+	if helm.IsChartArchive(chartDirectory) {
+		return path.Join(path.Dir(chartDirectory), templateFile)
 	}
 
-	return string(templateContents), nil
+	return path.Join(chartDirectory, templateFile)
 }
 
-func getDocumentationTemplates(chartDirectory string) ([]string, error) {
-	documentationTemplate, err := getDocumentationTemplate(chartDirectory)
+// getDocumentationTemplate reads each of the chart's template files, in the order given. Later files may override the
+// defines of earlier ones, and the document is rendered from the last of them to have any content outside of defines
+func getDocumentationTemplate(chartSettings *viper.Viper, chartDirectory string, templateFiles []string, defaultTemplate string) ([]string, error) {
+	documentationTemplates := make([]string, 0)
 
-	if err != nil {
-		log.Errorf("Failed to read documentation template for chart %s: %s", chartDirectory, err)
-		return nil, err
+	for _, templateFile := range templateFiles {
+		templateFileForChart := templateFilePath(chartDirectory, templateFile)
+
+		if _, err := os.Stat(templateFileForChart); os.IsNotExist(err) {
+			log.Debugf("Did not find template file %s for chart %s", templateFile, chartDirectory)
+			continue
+		}
+
+		log.Debugf("Using template file %s for chart %s", templateFile, chartDirectory)
+		templateContents, err := ioutil.ReadFile(templateFileForChart)
+		if err != nil {
+			return nil, err
+		}
+
+		documentationTemplates = append(documentationTemplates, string(templateContents))
 	}
 
+	if len(documentationTemplates) == 0 {
+		log.Debugf("Did not find any template files for chart %s, using default template", chartDirectory)
+
+		// The default template is parsed like any template file, so has to be written with the configured delimiters
+		leftDelim, rightDelim, err := getTemplateDelims(chartSettings)
+		if err != nil {
+			return nil, err
+		}
+
+		delimsReplacer := strings.NewReplacer("{{", leftDelim, "}}", rightDelim)
+		return []string{delimsReplacer.Replace(defaultTemplate)}, nil
+	}
+
+	return documentationTemplates, nil
+}
+
+// The file in the directory helm-docs is run from whose defines apply to the documentation of every chart
+const sharedTemplateFile = "_templates.gotmpl"
+
+// SharedTemplatePaths returns the paths of the template files and directories applying to the documentation of every
+// chart, so that a change to any of them changes every chart's documentation: the _templates.gotmpl file, the shared
+// templates directory and any template files given by absolute paths
+func SharedTemplatePaths() []string {
+	sharedPaths := []string{sharedTemplateFile}
+	if sharedTemplatesDir := settings.GetString("shared-templates-dir"); sharedTemplatesDir != "" {
+		sharedPaths = append(sharedPaths, sharedTemplatesDir)
+	}
+
+	for _, t := range settings.GetStringSlice("template-file") {
+		if filepath.IsAbs(t) {
+			sharedPaths = append(sharedPaths, t)
+		}
+	}
+
+	return sharedPaths
+}
+
+// getSharedTemplates reads the templates shared between all charts, from the _templates.gotmpl file and any
+// .gotmpl files in the shared templates directory, in the order in which they're parsed
+func getSharedTemplates(chartSettings *viper.Viper) ([]string, error) {
+	templateFiles := make([]string, 0)
+	if _, err := os.Stat(sharedTemplateFile); err == nil {
+		templateFiles = append(templateFiles, sharedTemplateFile)
+	}
+
+	if sharedTemplatesDir := chartSettings.GetString("shared-templates-dir"); sharedTemplatesDir != "" {
+		dirTemplateFiles, err := filepath.Glob(filepath.Join(sharedTemplatesDir, "*.gotmpl"))
+		if err != nil {
+			return nil, err
+		}
+
+		sort.Strings(dirTemplateFiles)
+		templateFiles = append(templateFiles, dirTemplateFiles...)
+	}
+
+	sharedTemplates := make([]string, 0, len(templateFiles))
+	for _, f := range templateFiles {
+		log.Debugf("Using shared template file %s", f)
+		templateContents, err := ioutil.ReadFile(f)
+		if err != nil {
+			return nil, err
+		}
+
+		sharedTemplates = append(sharedTemplates, string(templateContents))
+	}
+
+	return sharedTemplates, nil
+}
+
+// getBuiltInTemplates returns the templates defined by helm-docs itself, which are parsed before any others
+func getBuiltInTemplates() []string {
 	return []string{
 		getHeaderTemplate(),
 		getDescriptionTemplate(),
+		getTableOfContentsTemplate(),
+		getIconTemplate(),
 		getVersionTemplates(),
 		getTypeTemplate(),
+		getAppVersionTemplates(),
+		getBadgeTemplates(),
 		getSourceLinkTemplates(),
+		getKeywordsTemplates(),
+		getAnnotationsTableTemplates(),
+		getMaintainersTableTemplates(),
+		getLicenseTemplates(),
+		getChangesTemplates(),
+		getChangelogTemplates(),
+		getLinksTemplates(),
+		getInstallTemplates(),
+		getNotesTemplates(),
+		getTestsTemplates(),
+		getNamedTemplatesTableTemplates(),
+		getSchemaValidationTemplates(),
+		getLintTemplates(),
+		getCRDsTableTemplates(),
+		getResourcesTableTemplates(),
+		getKubeVersionsTableTemplates(),
+		getAPIDeprecationsTableTemplates(),
+		getImagesTableTemplates(),
 		getRequirementsTableTemplates(),
 		getValuesTableTemplates(),
-		documentationTemplate,
-	}, nil
+		getRequiredValuesTableTemplates(),
+		getValuesDiffTemplates(),
+		getGlobalValuesTableTemplates(),
+		getExtraValuesTableTemplates(),
+		getDependencyValuesTableTemplates(),
+		getSubchartsTemplates(),
+		getGeneratedFooterTemplate(),
+	}
 }
 
-func newChartDocumentationTemplate(chartDocumentationInfo helm.ChartDocumentationInfo) (*template.Template, error) {
+// getDocumentationTemplates returns the user supplied templates for a chart, parsed after the built in templates so
+// that their defines override the built in ones: the shared templates, followed by the chart's own template files
+func getDocumentationTemplates(chartDocumentationInfo helm.ChartDocumentationInfo, templateFiles []string) ([]string, error) {
+	chartDirectory := chartDocumentationInfo.ChartDirectory
+	documentationTemplate, err := getDocumentationTemplate(chartDocumentationInfo.Settings(), chartDirectory, templateFiles, getDefaultDocumentationTemplate(chartDocumentationInfo))
+
+	if err != nil {
+		log.Errorf("Failed to read documentation template for chart %s: %s", chartDirectory, err)
+		return nil, err
+	}
+
+	sharedTemplates, err := getSharedTemplates(chartDocumentationInfo.Settings())
+	if err != nil {
+		log.Errorf("Failed to read shared documentation templates: %s", err)
+		return nil, err
+	}
+
+	return append(sharedTemplates, documentationTemplate...), nil
+}
+
+// getSkippedSections returns the names of the built in sections not to render for a chart, e.g. "values" for the
+// chart.valuesSection template, from the chart's skip sections annotation if set, or otherwise from the settings
+func getSkippedSections(chartDocumentationInfo helm.ChartDocumentationInfo) []string {
+	if skippedSections, ok := chartDocumentationInfo.Annotations[skipSectionsAnnotation]; ok {
+		return strings.FieldsFunc(skippedSections, func(r rune) bool { return r == ',' || r == ' ' })
+	}
+
+	return chartDocumentationInfo.Settings().GetStringSlice("skip-sections")
+}
+
+// skipSections redefines the built in section templates of the given names to render nothing. As it happens before
+// the user supplied templates are parsed, those can still define the sections themselves
+func skipSections(documentationTemplate *template.Template, sections []string) error {
+	for _, section := range sections {
+		sectionTemplateName := fmt.Sprintf("chart.%sSection", section)
+		if documentationTemplate.Lookup(sectionTemplateName) == nil {
+			sectionTemplateName = fmt.Sprintf("chart.%sSections", section)
+		}
+
+		if documentationTemplate.Lookup(sectionTemplateName) == nil {
+			log.Warnf("Not skipping unknown section %s", section)
+			continue
+		}
+
+		// An empty definition doesn't replace an existing template, so the section is redefined to render an empty string
+		if _, err := documentationTemplate.Parse(fmt.Sprintf(`{{ define "%s" }}{{ "" }}{{ end }}`, sectionTemplateName)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// getTemplateDelims parses the delimiters of actions in user supplied templates, given as the left and right delimiters
+// separated by a space, e.g. "[[ ]]". If none are given the usual "{{" and "}}" are used
+func getTemplateDelims(chartSettings *viper.Viper) (string, string, error) {
+	templateDelims := chartSettings.GetString("template-delims")
+	if templateDelims == "" {
+		return "{{", "}}", nil
+	}
+
+	delims := strings.Fields(templateDelims)
+	if len(delims) != 2 {
+		return "", "", fmt.Errorf("invalid template delimiters %q, must be the left and right delimiters separated by a space, e.g. \"[[ ]]\"", templateDelims)
+	}
+
+	return delims[0], delims[1], nil
+}
+
+func newChartDocumentationTemplate(chartDocumentationInfo helm.ChartDocumentationInfo, templateFiles []string) (*template.Template, error) {
+	headingOffset, err := getHeadingOffset(chartDocumentationInfo)
+	if err != nil {
+		return nil, err
+	}
+
 	documentationTemplate := template.New(chartDocumentationInfo.ChartDirectory)
 	documentationTemplate.Funcs(sprig.TxtFuncMap())
-	goTemplateList, err := getDocumentationTemplates(chartDocumentationInfo.ChartDirectory)
+	documentationTemplate.Funcs(helmDocsFuncMap())
+	documentationTemplate.Funcs(template.FuncMap{
+		"heading":   headingWithOffset(headingOffset),
+		"readFile":  chartFileReader(chartDocumentationInfo.ChartDirectory),
+		"translate": translator(getChartLocale(chartDocumentationInfo)),
+	})
+
+	// In strict mode, referencing keys that don't exist is an error rather than rendering "<no value>"
+	if chartDocumentationInfo.Settings().GetBool("strict") {
+		documentationTemplate.Option("missingkey=error")
+	}
+
+	for _, t := range getBuiltInTemplates() {
+		if _, err := documentationTemplate.Parse(t); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := skipSections(documentationTemplate, getSkippedSections(chartDocumentationInfo)); err != nil {
+		return nil, err
+	}
+
+	leftDelim, rightDelim, err := getTemplateDelims(chartDocumentationInfo.Settings())
+	if err != nil {
+		return nil, err
+	}
+
+	goTemplateList, err := getDocumentationTemplates(chartDocumentationInfo, templateFiles)
 
 	if err != nil {
 		return nil, err
 	}
 
+	documentationTemplate.Delims(leftDelim, rightDelim)
 	for _, t := range goTemplateList {
 		_, err := documentationTemplate.Parse(t)
 
@@ -0,0 +1,220 @@
+package document
+
+import (
+	"bytes"
+	"testing"
+	"text/template"
+
+	"github.com/norwoodj/helm-docs/pkg/helm"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSkipSections(t *testing.T) {
+	documentationTemplate := template.New("test")
+	_, err := documentationTemplate.Parse(`{{ define "chart.valuesSection" }}values{{ end }}{{ define "chart.dependencyValuesSections" }}dependencies{{ end }}`)
+	assert.Nil(t, err)
+
+	assert.Nil(t, skipSections(documentationTemplate, []string{"values", "dependencyValues", "unknown"}))
+
+	_, err = documentationTemplate.Parse(`[{{ template "chart.valuesSection" . }}][{{ template "chart.dependencyValuesSections" . }}]`)
+	assert.Nil(t, err)
+
+	var rendered bytes.Buffer
+	assert.Nil(t, documentationTemplate.Execute(&rendered, nil))
+	assert.Equal(t, "[][]", rendered.String())
+}
+
+func TestMaintainersTable(t *testing.T) {
+	render := func(maintainers []helm.ChartMetaMaintainer) string {
+		chartDocumentationInfo := helm.ChartDocumentationInfo{ChartMeta: helm.ChartMeta{Name: "app", Maintainers: maintainers}}
+		documentationTemplate, err := newChartDocumentationTemplate(chartDocumentationInfo, []string{})
+		assert.Nil(t, err)
+
+		_, err = documentationTemplate.Parse(`{{ template "chart.maintainersTable" . }}`)
+		assert.Nil(t, err)
+
+		var rendered bytes.Buffer
+		assert.Nil(t, documentationTemplate.Execute(&rendered, chartTemplateData{ChartDocumentationInfo: chartDocumentationInfo}))
+		return rendered.String()
+	}
+
+	maintainers := []helm.ChartMetaMaintainer{
+		{Name: "John Norwood", Email: "norwoodj@example.com", Url: "https://github.com/norwoodj"},
+		{Name: "Jane Doe", Email: "jane@example.com"},
+	}
+
+	assert.Equal(t, "| Name | Email | Url |\n"+
+		"|------|-------|-----|\n"+
+		"| John Norwood | <norwoodj@example.com> | <https://github.com/norwoodj> |\n"+
+		"| Jane Doe | <jane@example.com> |  |", render(maintainers))
+
+	maintainers[0].GitHubUsername = "norwoodj"
+	assert.Equal(t, "| | Name | Email | Url |\n"+
+		"|-|------|-------|-----|\n"+
+		"| [![@norwoodj](https://github.com/norwoodj.png?size=32)](https://github.com/norwoodj) | [John Norwood](https://github.com/norwoodj) | <norwoodj@example.com> | <https://github.com/norwoodj> |\n"+
+		"|  | Jane Doe | <jane@example.com> |  |", render(maintainers))
+}
+
+func TestInstallSection(t *testing.T) {
+	render := func(chartDocumentationInfo helm.ChartDocumentationInfo) string {
+		documentationTemplate, err := newChartDocumentationTemplate(chartDocumentationInfo, []string{})
+		assert.Nil(t, err)
+
+		_, err = documentationTemplate.Parse(`{{ template "chart.installSection" . }}`)
+		assert.Nil(t, err)
+
+		templateData := chartTemplateData{
+			ChartDocumentationInfo: chartDocumentationInfo,
+			ChartRepository:        getChartRepositoryTemplateData(chartDocumentationInfo),
+		}
+
+		var rendered bytes.Buffer
+		assert.Nil(t, documentationTemplate.Execute(&rendered, templateData))
+		return rendered.String()
+	}
+
+	chartDocumentationInfo := helm.ChartDocumentationInfo{ChartMeta: helm.ChartMeta{Name: "app", Version: "1.2.3"}}
+	assert.Equal(t, "", render(chartDocumentationInfo))
+
+	viper.Set("chart-repository-url", "https://charts.example.com")
+	defer viper.Set("chart-repository-url", nil)
+
+	assert.Equal(t, "## Installing the Chart\n\n"+
+		"To install the chart with the release name `my-release`:\n\n"+
+		"```console\n"+
+		"$ helm repo add app https://charts.example.com\n"+
+		"$ helm install my-release app/app --version 1.2.3\n"+
+		"```", render(chartDocumentationInfo))
+
+	chartDocumentationInfo.Annotations = map[string]string{
+		chartRepositoryNameAnnotation: "example",
+		chartRepositoryURLAnnotation:  "https://example.github.io/charts",
+	}
+
+	assert.Equal(t, "## Installing the Chart\n\n"+
+		"To install the chart with the release name `my-release`:\n\n"+
+		"```console\n"+
+		"$ helm repo add example https://example.github.io/charts\n"+
+		"$ helm install my-release example/app --version 1.2.3\n"+
+		"```", render(chartDocumentationInfo))
+}
+
+func TestValuesTableFor(t *testing.T) {
+	chartDocumentationInfo := helm.ChartDocumentationInfo{
+		ChartMeta:               helm.ChartMeta{Name: "app"},
+		ChartValues:             map[interface{}]interface{}{"replicas": 1, "ingress": map[interface{}]interface{}{"enabled": false}},
+		ChartValuesDescriptions: map[string]helm.ChartValueDescription{"replicas": {Description: "Number of pods"}},
+		ChartExtraValues: map[string]helm.ChartValuesFile{
+			"values-production.yaml": {Values: map[interface{}]interface{}{"replicas": 3}},
+		},
+	}
+
+	templateData, err := getChartTemplateData(chartDocumentationInfo)
+	assert.Nil(t, err)
+
+	render := func(documentTemplate string) (string, error) {
+		documentationTemplate, err := newChartDocumentationTemplate(chartDocumentationInfo, []string{})
+		assert.Nil(t, err)
+
+		documentationTemplate.Funcs(extraValuesFuncMap(templateData.ExtraValues))
+		_, err = documentationTemplate.Parse(documentTemplate)
+		assert.Nil(t, err)
+
+		var rendered bytes.Buffer
+		err = documentationTemplate.Execute(&rendered, templateData)
+		return rendered.String(), err
+	}
+
+	rendered, err := render(`{{ template "chart.valuesTableFor" "values-production.yaml" }}`)
+	assert.Nil(t, err)
+	assert.Equal(t, "| Key | Type | Default | Description |\n"+
+		"|-----|------|---------|-------------|\n"+
+		"| replicas | int | `3` | Number of pods |", rendered)
+
+	rendered, err = render(`{{ template "chart.valuesTableFor" (dict "root" "ingress" "ctx" .) }}`)
+	assert.Nil(t, err)
+	assert.Equal(t, "| Key | Type | Default | Description |\n"+
+		"|-----|------|---------|-------------|\n"+
+		"| ingress.enabled | bool | `false` |  |", rendered)
+
+	_, err = render(`{{ template "chart.valuesTableFor" "values-staging.yaml" }}`)
+	assert.NotNil(t, err)
+}
+
+func TestValueAllowedValues(t *testing.T) {
+	documentationTemplate, err := newChartDocumentationTemplate(helm.ChartDocumentationInfo{}, []string{})
+	assert.Nil(t, err)
+
+	_, err = documentationTemplate.Parse(`{{ template "chart.valuesTable" . }}`)
+	assert.Nil(t, err)
+
+	templateData := chartTemplateData{Values: []valueRow{
+		{Key: "logFormat", Type: "string", Default: "`\"text\"`", Description: "Format of the logs", Allowed: []string{"text", "json|pretty", "`raw`", "multi\nline"}},
+	}}
+
+	var rendered bytes.Buffer
+	assert.Nil(t, documentationTemplate.Execute(&rendered, templateData))
+	assert.Equal(t, "| Key | Type | Default | Description |\n"+
+		"|-----|------|---------|-------------|\n"+
+		"| logFormat | string | `\"text\"` | Format of the logs Allowed values: `text`, `json\\|pretty`, `` `raw` ``, `multi<br>line` |", rendered.String())
+}
+
+// renderChartTemplate renders a template of the documentation of a chart on its own
+func renderChartTemplate(t *testing.T, name string, chartDocumentationInfo helm.ChartDocumentationInfo) string {
+	documentationTemplate, err := newChartDocumentationTemplate(chartDocumentationInfo, []string{})
+	assert.Nil(t, err)
+
+	_, err = documentationTemplate.Parse(`{{ template "` + name + `" . }}`)
+	assert.Nil(t, err)
+
+	var rendered bytes.Buffer
+	assert.Nil(t, documentationTemplate.Execute(&rendered, chartTemplateData{ChartDocumentationInfo: chartDocumentationInfo}))
+	return rendered.String()
+}
+
+func TestImagesTable(t *testing.T) {
+	chartDocumentationInfo := helm.ChartDocumentationInfo{
+		ChartMeta:   helm.ChartMeta{Name: "app"},
+		ChartImages: []helm.ChartImage{{Repository: "nginx", Tag: "{{ .Values.tag | default \"1.19\" }}", PullPolicy: "IfNotPresent"}},
+	}
+
+	assert.Equal(t, "| Repository | Tag | Pull Policy |\n"+
+		"|------------|-----|-------------|\n"+
+		"| nginx | {{ .Values.tag \\| default \"1.19\" }} | IfNotPresent |", renderChartTemplate(t, "chart.imagesTable", chartDocumentationInfo))
+}
+
+func TestResourcesTable(t *testing.T) {
+	chartDocumentationInfo := helm.ChartDocumentationInfo{
+		ChartMeta:      helm.ChartMeta{Name: "app"},
+		ChartResources: []helm.ChartResource{{APIVersion: "v1", Kind: "ConfigMap", Name: "release-name-app|config", Namespace: "default"}},
+	}
+
+	assert.Equal(t, "| Kind | Name | Namespace |\n"+
+		"|------|------|-----------|\n"+
+		"| ConfigMap | release-name-app\\|config | default |", renderChartTemplate(t, "chart.resourcesTable", chartDocumentationInfo))
+}
+
+func TestCRDsTable(t *testing.T) {
+	chartDocumentationInfo := helm.ChartDocumentationInfo{
+		ChartMeta: helm.ChartMeta{Name: "app"},
+		ChartCRDs: []helm.ChartCRD{{Name: "widgets.example.com", Group: "example.com", Kind: "Widget", Scope: "Namespaced", Versions: []string{"v1|beta", "v1"}}},
+	}
+
+	assert.Equal(t, "| Group | Kind | Versions | Scope |\n"+
+		"|-------|------|----------|-------|\n"+
+		"| example.com | Widget | v1\\|beta, v1 | Namespaced |", renderChartTemplate(t, "chart.crdsTable", chartDocumentationInfo))
+}
+
+func TestAPIDeprecationsTable(t *testing.T) {
+	chartDocumentationInfo := helm.ChartDocumentationInfo{
+		ChartMeta: helm.ChartMeta{Name: "app"},
+		ChartAPIDeprecations: []helm.ChartAPIDeprecation{
+			{Kind: "Ingress", Name: "release-name-app|web", APIVersion: "extensions/v1beta1", RemovedIn: "v1.22", Replacement: "networking.k8s.io/v1"},
+		},
+	}
+
+	assert.Equal(t, "| Kind | Name | API Version | Removed In | Replacement |\n"+
+		"|------|------|-------------|------------|-------------|\n"+
+		"| Ingress | release-name-app\\|web | extensions/v1beta1 | v1.22 | networking.k8s.io/v1 |", renderChartTemplate(t, "chart.apiDeprecationsTable", chartDocumentationInfo))
+}
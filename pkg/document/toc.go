@@ -0,0 +1,120 @@
+package document
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// The chart.toc template only renders this marker, as the table of contents can't be known until the rest of the
+// document has been rendered. It's replaced with the table of contents afterwards
+const tableOfContentsMarker = "<!-- helm-docs:toc -->"
+
+var atxHeadingRegex = regexp.MustCompile(`^ {0,3}(#{1,6})\s+(.*?)(?:\s+#+)?\s*$`)
+var setextUnderlineRegex = regexp.MustCompile(`^ {0,3}(=+|-+)\s*$`)
+var codeFenceRegex = regexp.MustCompile("^ {0,3}(```|~~~)")
+var anchorRemovedCharactersRegex = regexp.MustCompile(`[^\p{L}\p{N}\s_-]`)
+
+type markdownHeading struct {
+	level  int
+	text   string
+	anchor string
+
+	// Whether the heading follows the table of contents marker in the document
+	afterMarker bool
+}
+
+// headingAnchor generates the anchor for a heading the same way that GitHub does, lowercasing it, dropping
+// punctuation and replacing spaces with dashes. Repeated headings get a numeric suffix
+func headingAnchor(text string, anchorCounts map[string]int) string {
+	anchor := strings.ToLower(text)
+	anchor = anchorRemovedCharactersRegex.ReplaceAllString(anchor, "")
+	anchor = strings.ReplaceAll(anchor, " ", "-")
+
+	count := anchorCounts[anchor]
+	anchorCounts[anchor] = count + 1
+	if count > 0 {
+		return fmt.Sprintf("%s-%d", anchor, count)
+	}
+
+	return anchor
+}
+
+func parseMarkdownHeadings(document string) []markdownHeading {
+	headings := make([]markdownHeading, 0)
+	anchorCounts := make(map[string]int)
+	lines := strings.Split(document, "\n")
+	inCodeBlock := false
+	afterMarker := false
+	previousLine := ""
+
+	for _, line := range lines {
+		if codeFenceRegex.MatchString(line) {
+			inCodeBlock = !inCodeBlock
+			previousLine = ""
+			continue
+		}
+
+		if inCodeBlock {
+			continue
+		}
+
+		if strings.Contains(line, tableOfContentsMarker) {
+			afterMarker = true
+			previousLine = ""
+			continue
+		}
+
+		if match := atxHeadingRegex.FindStringSubmatch(line); len(match) > 2 {
+			headings = append(headings, markdownHeading{level: len(match[1]), text: match[2], afterMarker: afterMarker})
+			previousLine = ""
+			continue
+		}
+
+		// A line of only equals signs or dashes turns the paragraph line above it into a heading
+		if match := setextUnderlineRegex.FindStringSubmatch(line); len(match) > 1 && strings.TrimSpace(previousLine) != "" {
+			level := 1
+			if strings.HasPrefix(match[1], "-") {
+				level = 2
+			}
+
+			headings = append(headings, markdownHeading{level: level, text: strings.TrimSpace(previousLine), afterMarker: afterMarker})
+			previousLine = ""
+			continue
+		}
+
+		previousLine = line
+	}
+
+	for i := range headings {
+		headings[i].anchor = headingAnchor(headings[i].text, anchorCounts)
+	}
+
+	return headings
+}
+
+// insertTableOfContents replaces the table of contents marker in a rendered document with a nested list linking each
+// of the headings that follow it
+func insertTableOfContents(document string) string {
+	if !strings.Contains(document, tableOfContentsMarker) {
+		return document
+	}
+
+	headings := parseMarkdownHeadings(document)
+	minLevel := 0
+	for _, h := range headings {
+		if h.afterMarker && (minLevel == 0 || h.level < minLevel) {
+			minLevel = h.level
+		}
+	}
+
+	tocLines := make([]string, 0)
+	for _, h := range headings {
+		if h.afterMarker {
+			indent := strings.Repeat("  ", h.level-minLevel)
+			tocLines = append(tocLines, fmt.Sprintf("%s* [%s](#%s)", indent, h.text, h.anchor))
+		}
+	}
+
+	return strings.ReplaceAll(document, tableOfContentsMarker, strings.Join(tocLines, "\n"))
+}
@@ -0,0 +1,36 @@
+package document
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInsertTableOfContents(t *testing.T) {
+	document := `my-chart
+========
+
+<!-- helm-docs:toc -->
+
+## Chart Values
+
+### Values (values-production.yaml)
+
+` + "```" + `
+## not a heading
+` + "```" + `
+
+Extra Notes
+-----------
+
+## Chart Values
+`
+
+	expected := `* [Chart Values](#chart-values)
+  * [Values (values-production.yaml)](#values-values-productionyaml)
+* [Extra Notes](#extra-notes)
+* [Chart Values](#chart-values-1)`
+
+	assert.Contains(t, insertTableOfContents(document), "========\n\n"+expected+"\n\n## Chart Values")
+	assert.Equal(t, "no marker\n", insertTableOfContents("no marker\n"))
+}
@@ -2,9 +2,10 @@ package document
 
 import (
 	"fmt"
+	"strings"
 )
 
-type jsonableMap map[string]interface{}
+type jsonableMap = map[string]interface{}
 
 func convertMapKeyToString(key interface{}) string {
 	switch key.(type) {
@@ -47,3 +48,66 @@ func convertHelmValuesToJsonable(values interface{}) interface{} {
 		return values
 	}
 }
+
+// escapeTableCell escapes the characters which would otherwise break a markdown table row when they occur in text
+// rendered into a table cell
+func escapeTableCell(text string) string {
+	text = strings.ReplaceAll(text, "|", `\|`)
+	text = strings.ReplaceAll(text, "\r\n", "<br>")
+	return strings.ReplaceAll(text, "\n", "<br>")
+}
+
+// codeSpan wraps text in a markdown code span, using a backtick fence longer than any run of backticks in the text so
+// that the text can't end the span early
+func codeSpan(text string) string {
+	longestRun, currentRun := 0, 0
+	for _, c := range text {
+		if c == '`' {
+			currentRun++
+			if currentRun > longestRun {
+				longestRun = currentRun
+			}
+		} else {
+			currentRun = 0
+		}
+	}
+
+	if longestRun == 0 {
+		return fmt.Sprintf("`%s`", text)
+	}
+
+	fence := strings.Repeat("`", longestRun+1)
+	return fmt.Sprintf("%s %s %s", fence, text, fence)
+}
+
+// normalizeTrailingWhitespace strips the whitespace trailing each line of a rendered document, apart from the two
+// spaces of a markdown hard line break, and ends it with a single newline. The blank lines and spaces templates leave
+// behind, e.g. around sections rendering empty, then don't depend on how the template was written, and line endings
+// don't depend on the platform it was written on. Lines in fenced code blocks are left as they are, as their whitespace
+// is part of the code, e.g. of a heredoc or a diff
+func normalizeTrailingWhitespace(document string) string {
+	lines := strings.Split(strings.ReplaceAll(document, "\r\n", "\n"), "\n")
+	inCodeBlock := false
+
+	for i, l := range lines {
+		if codeFenceRegex.MatchString(l) {
+			inCodeBlock = !inCodeBlock
+		} else if inCodeBlock {
+			continue
+		}
+
+		trimmed := strings.TrimRight(l, " \t\r")
+		if trimmed != "" && strings.HasSuffix(l, "  ") && !strings.HasSuffix(trimmed, "\t") {
+			trimmed += "  "
+		}
+
+		lines[i] = trimmed
+	}
+
+	normalized := strings.TrimRight(strings.Join(lines, "\n"), "\n")
+	if normalized == "" {
+		return ""
+	}
+
+	return normalized + "\n"
+}
@@ -4,65 +4,40 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"html"
+	"math"
 	"regexp"
 	"sort"
 	"strings"
 
 	"github.com/norwoodj/helm-docs/pkg/helm"
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v2"
 )
 
 const (
-	boolType   = "bool"
-	floatType  = "float"
-	intType    = "int"
-	listType   = "list"
-	objectType = "object"
-	stringType = "string"
+	boolType   = helm.BoolValueType
+	floatType  = helm.FloatValueType
+	intType    = helm.IntValueType
+	listType   = helm.ListValueType
+	nullType   = helm.NullValueType
+	objectType = helm.ObjectValueType
+	stringType = helm.StringValueType
 )
 
-var nilValueTypeRegex = regexp.MustCompile("^\\(.*?\\)")
-
-func formatNextListKeyPrefix(prefix string, index int) string {
-	return fmt.Sprintf("%s[%d]", prefix, index)
-}
-
-func formatNextObjectKeyPrefix(prefix string, key string) string {
-	var escapedKey string
-	var nextPrefix string
-
-	if strings.Contains(key, ".") || strings.Contains(key, " ") {
-		escapedKey = fmt.Sprintf(`"%s"`, key)
-	} else {
-		escapedKey = key
-	}
-
-	if prefix != "" {
-		nextPrefix = fmt.Sprintf("%s.%s", prefix, escapedKey)
-	} else {
-		nextPrefix = fmt.Sprintf("%s", escapedKey)
-	}
+const (
+	alphaNumSortOrder = helm.AlphaNumSortOrder
+	fileSortOrder     = helm.FileSortOrder
+)
 
-	return nextPrefix
-}
+const (
+	jsonDefaultFormat = "json"
+	yamlDefaultFormat = "yaml"
+)
 
-func getTypeName(value interface{}) string {
-	switch value.(type) {
-	case bool:
-		return boolType
-	case float64:
-		return floatType
-	case int:
-		return intType
-	case string:
-		return stringType
-	case []interface{}:
-		return listType
-	case jsonableMap:
-		return objectType
-	}
+const redactedDefault = "`<redacted>`"
 
-	return ""
-}
+var nilValueTypeRegex = regexp.MustCompile("^\\(.*?\\)")
 
 func parseNilValueType(key string, description helm.ChartValueDescription) valueRow {
 	// Grab whatever's in between the parentheses of the description and treat it as the type
@@ -72,18 +47,26 @@ func parseNilValueType(key string, description helm.ChartValueDescription) value
 		t = t[1 : len(t)-1]
 		description.Description = description.Description[len(t)+3:]
 	} else {
-		t = stringType
+		t = nullType
 	}
 
-	if description.Default == "" {
+	if description.Type != "" {
+		t = description.Type
+	}
+
+	if description.Sensitive {
+		description.Default = redactedDefault
+	} else if description.Default == "" {
 		description.Default = "`nil`"
 	}
 
 	return valueRow{
 		Key:         key,
 		Type:        t,
-		Default:     description.Default,
-		Description: description.Description,
+		Default:     formatCustomDefault(description.Default, description.Collapse),
+		Description: escapeTableCell(description.Description),
+		Required:    description.Required,
+		Allowed:     description.Allowed,
 	}
 }
 
@@ -100,7 +83,95 @@ func jsonMarshalNoEscape(key string, value interface{}) (string, error) {
 	return strings.TrimRight(outputBuffer.String(), "\n"), nil
 }
 
+func shouldCollapseDefault(chartSettings *viper.Viper, jsonEncodedValue string) bool {
+	collapseLength := chartSettings.GetInt("collapse-defaults-length")
+	return collapseLength > 0 && len(jsonEncodedValue) > collapseLength
+}
+
+// preformattedDefault renders a multi-line default value in an html pre element. Markdown table rows must fit on a
+// single line, so line breaks are rendered as html too
+func preformattedDefault(language string, value string) string {
+	escapedValue := html.EscapeString(value)
+	escapedValue = strings.ReplaceAll(escapedValue, "|", "&#124;")
+	escapedValue = strings.ReplaceAll(escapedValue, "\n", "<br>")
+	if language == "" {
+		return fmt.Sprintf("<pre>%s</pre>", escapedValue)
+	}
+
+	return fmt.Sprintf(`<pre lang="%s">%s</pre>`, language, escapedValue)
+}
+
+// formatCustomDefault renders a default value given by an @default comment. Single line defaults are rendered as they
+// were written, while defaults continued over several lines are rendered preformatted, to keep their line breaks
+func formatCustomDefault(defaultValue string, collapse bool) string {
+	if !strings.Contains(defaultValue, "\n") {
+		return escapeTableCell(defaultValue)
+	}
+
+	if collapse {
+		return collapsedDefault(preformattedDefault("", defaultValue))
+	}
+
+	return preformattedDefault("", defaultValue)
+}
+
+func collapsedDefault(preformattedValue string) string {
+	return fmt.Sprintf("<details><summary>Expand</summary>%s</details>", preformattedValue)
+}
+
+func formatJsonDefault(key string, jsonEncodedValue string, collapse bool) (string, error) {
+	if !collapse {
+		return escapeTableCell(codeSpan(jsonEncodedValue)), nil
+	}
+
+	indentedValue := bytes.Buffer{}
+	if err := json.Indent(&indentedValue, []byte(jsonEncodedValue), "", "  "); err != nil {
+		return "", fmt.Errorf("failed to indent default value for %s: %s", key, err)
+	}
+
+	return collapsedDefault(preformattedDefault("json", indentedValue.String())), nil
+}
+
+func formatYamlDefault(key string, value interface{}, collapse bool) (string, error) {
+	yamlEncodedValue, err := yaml.Marshal(value)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal default value for %s to yaml: %s", key, err)
+	}
+
+	trimmedValue := strings.TrimRight(string(yamlEncodedValue), "\n")
+	valueType := helm.InferValueType(value)
+	isEmpty := trimmedValue == "[]" || trimmedValue == "{}"
+
+	switch {
+	case collapse:
+		return collapsedDefault(preformattedDefault("yaml", trimmedValue)), nil
+	case (valueType == listType || valueType == objectType) && !isEmpty, strings.Contains(trimmedValue, "\n"):
+		return preformattedDefault("yaml", trimmedValue), nil
+	default:
+		return escapeTableCell(codeSpan(trimmedValue)), nil
+	}
+}
+
+func formatDefaultValue(chartSettings *viper.Viper, key string, value interface{}, collapse bool) (string, error) {
+	jsonEncodedValue, err := jsonMarshalNoEscape(key, value)
+	if err != nil {
+		return "", err
+	}
+
+	collapse = collapse || shouldCollapseDefault(chartSettings, jsonEncodedValue)
+
+	switch defaultFormat := chartSettings.GetString("default-format"); defaultFormat {
+	case "", jsonDefaultFormat:
+		return formatJsonDefault(key, jsonEncodedValue, collapse)
+	case yamlDefaultFormat:
+		return formatYamlDefault(key, value, collapse)
+	default:
+		return "", fmt.Errorf("invalid default value format %s, must be one of %s or %s", defaultFormat, jsonDefaultFormat, yamlDefaultFormat)
+	}
+}
+
 func createValueRow(
+	chartSettings *viper.Viper,
 	key string,
 	value interface{},
 	description helm.ChartValueDescription,
@@ -109,36 +180,76 @@ func createValueRow(
 		return parseNilValueType(key, description), nil
 	}
 
-	defaultValue := description.Default
-	if defaultValue == "" {
-		jsonEncodedValue, err := jsonMarshalNoEscape(key, value)
+	defaultValue := formatCustomDefault(description.Default, description.Collapse)
+	if description.Sensitive {
+		defaultValue = redactedDefault
+	} else if defaultValue == "" {
+		var err error
+		defaultValue, err = formatDefaultValue(chartSettings, key, value, description.Collapse)
 		if err != nil {
-			return valueRow{}, fmt.Errorf("failed to marshal default value for %s to json: %s", key, err)
+			return valueRow{}, err
 		}
+	}
 
-		defaultValue = fmt.Sprintf("`%s`", jsonEncodedValue)
+	typeName := description.Type
+	if typeName == "" {
+		typeName = helm.InferValueType(value)
 	}
 
 	return valueRow{
 		Key:         key,
-		Type:        getTypeName(value),
+		Type:        typeName,
+		Value:       value,
 		Default:     defaultValue,
-		Description: description.Description,
+		Description: escapeTableCell(description.Description),
+		Required:    description.Required,
+		Allowed:     description.Allowed,
+		Section:     description.Section,
 	}, nil
 }
 
+// createCommentedOutValueRows documents values which are only present as commented out keys in the values file, and so
+// don't have rows created for them from the parsed values
+func createCommentedOutValueRows(valueRows []valueRow, keysToDescriptions map[string]helm.ChartValueDescription) []valueRow {
+	documentedKeys := make(map[string]bool)
+	for _, r := range valueRows {
+		documentedKeys[r.Key] = true
+	}
+
+	commentedOutRows := make([]valueRow, 0)
+	for key, description := range keysToDescriptions {
+		if description.CommentedOut && !description.Ignored && !documentedKeys[key] {
+			commentedOutRows = append(commentedOutRows, parseNilValueType(key, description))
+		}
+	}
+
+	// Commented out keys have no line numbers, so their order isn't changed when sorting values by file order, and would
+	// otherwise be that of iterating over the descriptions
+	sort.Slice(commentedOutRows, func(i, j int) bool {
+		return commentedOutRows[i].Key < commentedOutRows[j].Key
+	})
+
+	return commentedOutRows
+}
+
 func createRowsFromField(
+	chartSettings *viper.Viper,
 	nextPrefix string,
 	value interface{},
 	keysToDescriptions map[string]helm.ChartValueDescription,
 	documentLeafNodes bool,
 ) ([]valueRow, error) {
+	// Ignored keys are left out of the documentation entirely, along with any fields underneath them
+	if keysToDescriptions[nextPrefix].Ignored {
+		return []valueRow{}, nil
+	}
+
 	switch value.(type) {
 	case map[interface{}]interface{}:
-		return createValueRowsFromObject(nextPrefix, value.(map[interface{}]interface{}), keysToDescriptions, documentLeafNodes)
+		return createValueRowsFromObject(chartSettings, nextPrefix, value.(map[interface{}]interface{}), keysToDescriptions, documentLeafNodes)
 
 	case []interface{}:
-		return createValueRowsFromList(nextPrefix, value.([]interface{}), keysToDescriptions, documentLeafNodes)
+		return createValueRowsFromList(chartSettings, nextPrefix, value.([]interface{}), keysToDescriptions, documentLeafNodes)
 
 	default:
 		description, hasDescription := keysToDescriptions[nextPrefix]
@@ -146,12 +257,13 @@ func createRowsFromField(
 			return []valueRow{}, nil
 		}
 
-		leafValueRow, err := createValueRow(nextPrefix, value, description)
+		leafValueRow, err := createValueRow(chartSettings, nextPrefix, value, description)
 		return []valueRow{leafValueRow}, err
 	}
 }
 
 func createValueRowsFromList(
+	chartSettings *viper.Viper,
 	prefix string,
 	values []interface{},
 	keysToDescriptions map[string]helm.ChartValueDescription,
@@ -166,7 +278,7 @@ func createValueRowsFromList(
 			return []valueRow{}, nil
 		}
 
-		emptyListRow, err := createValueRow(prefix, values, description)
+		emptyListRow, err := createValueRow(chartSettings, prefix, values, description)
 		if err != nil {
 			return nil, err
 		}
@@ -180,7 +292,7 @@ func createValueRowsFromList(
 	// documented without descriptions
 	if hasDescription {
 		jsonableObject := convertHelmValuesToJsonable(values)
-		listRow, err := createValueRow(prefix, jsonableObject, description)
+		listRow, err := createValueRow(chartSettings, prefix, jsonableObject, description)
 
 		if err != nil {
 			return nil, err
@@ -192,8 +304,8 @@ func createValueRowsFromList(
 
 	// Generate documentation rows for all list items and their potential sub-fields
 	for i, v := range values {
-		nextPrefix := formatNextListKeyPrefix(prefix, i)
-		valueRowsForListField, err := createRowsFromField(nextPrefix, v, keysToDescriptions, documentLeafNodes)
+		nextPrefix := helm.FormatListKeyPath(prefix, i)
+		valueRowsForListField, err := createRowsFromField(chartSettings, nextPrefix, v, keysToDescriptions, documentLeafNodes)
 
 		if err != nil {
 			return nil, err
@@ -206,6 +318,7 @@ func createValueRowsFromList(
 }
 
 func createValueRowsFromObject(
+	chartSettings *viper.Viper,
 	prefix string,
 	values map[interface{}]interface{},
 	keysToDescriptions map[string]helm.ChartValueDescription,
@@ -225,7 +338,7 @@ func createValueRowsFromObject(
 			return []valueRow{}, nil
 		}
 
-		documentedRow, err := createValueRow(prefix, jsonableMap{}, description)
+		documentedRow, err := createValueRow(chartSettings, prefix, jsonableMap{}, description)
 		return []valueRow{documentedRow}, err
 	}
 
@@ -235,7 +348,7 @@ func createValueRowsFromObject(
 	// documented without descriptions
 	if hasDescription {
 		jsonableObject := convertHelmValuesToJsonable(values)
-		objectRow, err := createValueRow(prefix, jsonableObject, description)
+		objectRow, err := createValueRow(chartSettings, prefix, jsonableObject, description)
 
 		if err != nil {
 			return nil, err
@@ -246,8 +359,8 @@ func createValueRowsFromObject(
 	}
 
 	for k, v := range values {
-		nextPrefix := formatNextObjectKeyPrefix(prefix, convertMapKeyToString(k))
-		valueRowsForObjectField, err := createRowsFromField(nextPrefix, v, keysToDescriptions, documentLeafNodes)
+		nextPrefix := helm.FormatObjectKeyPath(prefix, convertMapKeyToString(k))
+		valueRowsForObjectField, err := createRowsFromField(chartSettings, nextPrefix, v, keysToDescriptions, documentLeafNodes)
 
 		if err != nil {
 			return nil, err
@@ -265,3 +378,32 @@ func createValueRowsFromObject(
 
 	return valueRows, nil
 }
+
+// valueRowLineNumber finds the line on which the key of a value row is defined in the values file. Keys not spelled out
+// in the file, e.g. those merged in from yaml anchors, take the line of their nearest parent which is
+func valueRowLineNumber(key string, lineNumbers map[string]int) int {
+	for ; key != ""; key = helm.ParentKeyPath(key) {
+		if lineNumber, ok := lineNumbers[key]; ok {
+			return lineNumber
+		}
+	}
+
+	return math.MaxInt32
+}
+
+func sortValueRows(valueRows []valueRow, sortOrder string, lineNumbers map[string]int) error {
+	switch sortOrder {
+	case "", alphaNumSortOrder:
+		sort.SliceStable(valueRows, func(i, j int) bool {
+			return valueRows[i].Key < valueRows[j].Key
+		})
+	case fileSortOrder:
+		sort.SliceStable(valueRows, func(i, j int) bool {
+			return valueRowLineNumber(valueRows[i].Key, lineNumbers) < valueRowLineNumber(valueRows[j].Key, lineNumbers)
+		})
+	default:
+		return fmt.Errorf("invalid values sort order %s, must be one of %s or %s", sortOrder, alphaNumSortOrder, fileSortOrder)
+	}
+
+	return nil
+}
@@ -0,0 +1,122 @@
+package document
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/norwoodj/helm-docs/pkg/helm"
+	"gopkg.in/yaml.v2"
+)
+
+// The file a chart's values schema is generated to, which helm validates values against on install
+const valuesSchemaFile = "values.schema.json"
+
+// The JSON schema draft that helm validates values against
+const valuesSchemaDraft = "http://json-schema.org/draft-07/schema#"
+
+// jsonSchemaTypes maps the types inferred for values to the types of JSON schema
+var jsonSchemaTypes = map[string]string{
+	helm.BoolValueType:   "boolean",
+	helm.FloatValueType:  "number",
+	helm.IntValueType:    "integer",
+	helm.ListValueType:   "array",
+	helm.ObjectValueType: "object",
+	helm.StringValueType: "string",
+}
+
+// schemaEnum converts the allowed values of a value to the enum of its schema. The allowed values are parsed as yaml
+// scalars, so that those of numbers and booleans have the same types as the value, whereas those of strings are kept as
+// written, e.g. "1.0" rather than 1
+func schemaEnum(allowed []string, valueType string) []interface{} {
+	enum := make([]interface{}, 0, len(allowed))
+	for _, a := range allowed {
+		var parsed interface{} = a
+		if valueType != helm.StringValueType {
+			if err := yaml.Unmarshal([]byte(a), &parsed); err != nil || parsed == nil {
+				parsed = a
+			}
+		}
+
+		enum = append(enum, parsed)
+	}
+
+	return enum
+}
+
+// valueSchema builds the schema of a value at a key path and, for maps, of the values nested under it. Ignored values
+// are left out of the schema, as they are of the documentation
+func valueSchema(key string, value interface{}, descriptions map[string]helm.ChartValueDescription) map[string]interface{} {
+	schema := make(map[string]interface{})
+	valueType := helm.InferValueType(value)
+	if jsonType, ok := jsonSchemaTypes[valueType]; ok {
+		schema["type"] = jsonType
+	}
+
+	description := descriptions[key]
+	if description.Description != "" {
+		schema["description"] = description.Description
+	}
+
+	if len(description.Allowed) > 0 {
+		schema["enum"] = schemaEnum(description.Allowed, valueType)
+	}
+
+	nested, ok := value.(map[interface{}]interface{})
+	if !ok {
+		return schema
+	}
+
+	properties := make(map[string]interface{})
+	required := make([]string, 0)
+
+	for k, v := range nested {
+		nestedKey := helm.FormatObjectKeyPath(key, fmt.Sprintf("%v", k))
+		if descriptions[nestedKey].Ignored {
+			continue
+		}
+
+		properties[fmt.Sprintf("%v", k)] = valueSchema(nestedKey, v, descriptions)
+		if descriptions[nestedKey].Required {
+			required = append(required, fmt.Sprintf("%v", k))
+		}
+	}
+
+	schema["properties"] = properties
+	if len(required) > 0 {
+		sort.Strings(required)
+		schema["required"] = required
+	}
+
+	return schema
+}
+
+// renderValuesSchema generates the values.schema.json of a chart from its default values and their descriptions: the
+// type of each value is that of its default, and its description, allowed values and whether it's required are those
+// of its comments
+func renderValuesSchema(chartDocumentationInfo helm.ChartDocumentationInfo) (RenderedDocument, error) {
+	values := chartDocumentationInfo.ChartValues
+	if values == nil {
+		values = make(map[interface{}]interface{})
+	}
+
+	schema := valueSchema("", values, chartDocumentationInfo.ChartValuesDescriptions)
+	schema["$schema"] = valuesSchemaDraft
+
+	// Descriptions often hold markdown, so characters significant in html are left unescaped
+	var schemaJSON bytes.Buffer
+	encoder := json.NewEncoder(&schemaJSON)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+
+	if err := encoder.Encode(schema); err != nil {
+		return RenderedDocument{}, err
+	}
+
+	return RenderedDocument{
+		OutputPath:    filepath.Join(getOutputDirectory(chartDocumentationInfo), valuesSchemaFile),
+		Documentation: schemaJSON.String(),
+	}, nil
+}
@@ -0,0 +1,119 @@
+package document
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/norwoodj/helm-docs/pkg/helm"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderValuesSchema(t *testing.T) {
+	chartDocumentationInfo := helm.ChartDocumentationInfo{
+		ChartDirectory: "charts/app",
+		ChartValues: parseYamlValues(`
+logLevel: info
+replicas: 1
+ratio: 0.5
+debug: false
+image:
+  repository: nginx
+  tag: "1.21"
+hosts: []
+internal:
+  token: abc
+storageClass:
+`),
+		ChartValuesDescriptions: map[string]helm.ChartValueDescription{
+			"logLevel":         {Description: "The log <level> of the application", Allowed: []string{"debug", "info", "warn", "error"}},
+			"replicas":         {Description: "Number of pods", Allowed: []string{"1", "3", "5"}},
+			"image":            {Description: "The image of the application"},
+			"image.tag":        {Allowed: []string{"1.20", "1.21"}},
+			"image.repository": {Required: true},
+			"internal":         {Ignored: true},
+		},
+	}
+
+	valuesSchema, err := renderValuesSchema(chartDocumentationInfo)
+	assert.Nil(t, err)
+	assert.Equal(t, filepath.Join("charts/app", "values.schema.json"), valuesSchema.OutputPath)
+	assert.Contains(t, valuesSchema.Documentation, `"The log <level> of the application"`)
+
+	var schema map[string]interface{}
+	assert.Nil(t, json.Unmarshal([]byte(valuesSchema.Documentation), &schema))
+	assert.Equal(t, "http://json-schema.org/draft-07/schema#", schema["$schema"])
+	assert.Equal(t, "object", schema["type"])
+
+	properties := schema["properties"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{
+		"type":        "string",
+		"description": "The log <level> of the application",
+		"enum":        []interface{}{"debug", "info", "warn", "error"},
+	}, properties["logLevel"])
+
+	assert.Equal(t, map[string]interface{}{
+		"type":        "integer",
+		"description": "Number of pods",
+		"enum":        []interface{}{float64(1), float64(3), float64(5)},
+	}, properties["replicas"])
+
+	assert.Equal(t, map[string]interface{}{"type": "number"}, properties["ratio"])
+	assert.Equal(t, map[string]interface{}{"type": "boolean"}, properties["debug"])
+	assert.Equal(t, map[string]interface{}{"type": "array"}, properties["hosts"])
+	assert.Equal(t, map[string]interface{}{}, properties["storageClass"])
+	assert.NotContains(t, properties, "internal")
+
+	assert.Equal(t, map[string]interface{}{
+		"type":        "object",
+		"description": "The image of the application",
+		"properties": map[string]interface{}{
+			"repository": map[string]interface{}{"type": "string"},
+			"tag":        map[string]interface{}{"type": "string", "enum": []interface{}{"1.20", "1.21"}},
+		},
+		"required": []interface{}{"repository"},
+	}, properties["image"])
+}
+
+func TestRenderDocumentationValuesSchema(t *testing.T) {
+	chartDirectory, err := ioutil.TempDir("", "helm-docs-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(chartDirectory)
+
+	viper.Set("template-file", []string{"README.md.gotmpl"})
+	viper.Set("output-file", "README.md")
+	defer viper.Set("template-file", nil)
+	defer viper.Set("output-file", nil)
+
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(chartDirectory, "README.md.gotmpl"), []byte("# {{ .Name }}\n"), 0644))
+
+	chartDocumentationInfo := helm.ChartDocumentationInfo{
+		ChartDirectory:          chartDirectory,
+		ChartMeta:               helm.ChartMeta{Name: "app"},
+		ChartValues:             parseYamlValues("logLevel: info\n"),
+		ChartValuesDescriptions: map[string]helm.ChartValueDescription{"logLevel": {Allowed: []string{"debug", "info"}}},
+	}
+
+	documents, err := renderDocumentation(context.Background(), chartDocumentationInfo)
+	assert.Nil(t, err)
+	assert.Len(t, documents, 1)
+
+	viper.Set("generate-values-schema", true)
+	defer viper.Set("generate-values-schema", nil)
+
+	documents, err = renderDocumentation(context.Background(), chartDocumentationInfo)
+	assert.Nil(t, err)
+	assert.Len(t, documents, 2)
+	assert.Equal(t, filepath.Join(chartDirectory, "values.schema.json"), documents[1].OutputPath)
+	assert.Contains(t, documents[1].Documentation, `"enum": [
+        "debug",
+        "info"
+      ]`)
+}
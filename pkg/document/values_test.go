@@ -1,10 +1,15 @@
 package document
 
 import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/norwoodj/helm-docs/pkg/helm"
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 	"gopkg.in/yaml.v2"
 )
@@ -21,7 +26,7 @@ func parseYamlValues(yamlValues string) map[interface{}]interface{} {
 }
 
 func TestEmptyValues(t *testing.T) {
-	valuesRows, err := createValueRowsFromObject("", make(map[interface{}]interface{}), make(map[string]helm.ChartValueDescription), true)
+	valuesRows, err := createValueRowsFromObject(viper.GetViper(), "", make(map[interface{}]interface{}), make(map[string]helm.ChartValueDescription), true)
 	assert.Nil(t, err)
 	assert.Len(t, valuesRows, 0)
 }
@@ -34,7 +39,7 @@ hello: "world"
 oscar: 3.14159
 	`)
 
-	valuesRows, err := createValueRowsFromObject("", helmValues, make(map[string]helm.ChartValueDescription), true)
+	valuesRows, err := createValueRowsFromObject(viper.GetViper(), "", helmValues, make(map[string]helm.ChartValueDescription), true)
 
 	assert.Nil(t, err)
 	assert.Len(t, valuesRows, 4)
@@ -75,7 +80,7 @@ oscar: 3.14159
 		"oscar":   {Description: "oscar"},
 	}
 
-	valuesRows, err := createValueRowsFromObject("", helmValues, descriptions, true)
+	valuesRows, err := createValueRowsFromObject(viper.GetViper(), "", helmValues, descriptions, true)
 
 	assert.Nil(t, err)
 	assert.Len(t, valuesRows, 4)
@@ -116,7 +121,7 @@ oscar: 3.14159
 		"oscar":   {Description: "oscar", Default: "values"},
 	}
 
-	valuesRows, err := createValueRowsFromObject("", helmValues, descriptions, true)
+	valuesRows, err := createValueRowsFromObject(viper.GetViper(), "", helmValues, descriptions, true)
 
 	assert.Nil(t, err)
 	assert.Len(t, valuesRows, 4)
@@ -149,7 +154,7 @@ recursive:
 oscar: dog
 	`)
 
-	valuesRows, err := createValueRowsFromObject("", helmValues, make(map[string]helm.ChartValueDescription), true)
+	valuesRows, err := createValueRowsFromObject(viper.GetViper(), "", helmValues, make(map[string]helm.ChartValueDescription), true)
 
 	assert.Nil(t, err)
 	assert.Len(t, valuesRows, 2)
@@ -177,7 +182,7 @@ oscar: dog
 		"oscar":          {Description: "oscar"},
 	}
 
-	valuesRows, err := createValueRowsFromObject("", helmValues, descriptions, true)
+	valuesRows, err := createValueRowsFromObject(viper.GetViper(), "", helmValues, descriptions, true)
 
 	assert.Nil(t, err)
 	assert.Len(t, valuesRows, 2)
@@ -205,7 +210,7 @@ oscar: dog
 		"oscar":          {Description: "oscar", Default: "default"},
 	}
 
-	valuesRows, err := createValueRowsFromObject("", helmValues, descriptions, true)
+	valuesRows, err := createValueRowsFromObject(viper.GetViper(), "", helmValues, descriptions, true)
 
 	assert.Nil(t, err)
 	assert.Len(t, valuesRows, 2)
@@ -227,7 +232,7 @@ recursive: {}
 oscar: dog
 	`)
 
-	valuesRows, err := createValueRowsFromObject("", helmValues, make(map[string]helm.ChartValueDescription), true)
+	valuesRows, err := createValueRowsFromObject(viper.GetViper(), "", helmValues, make(map[string]helm.ChartValueDescription), true)
 
 	assert.Nil(t, err)
 	assert.Len(t, valuesRows, 2)
@@ -253,7 +258,7 @@ oscar: dog
 		"recursive": {Description: "an empty object"},
 	}
 
-	valuesRows, err := createValueRowsFromObject("", helmValues, descriptions, true)
+	valuesRows, err := createValueRowsFromObject(viper.GetViper(), "", helmValues, descriptions, true)
 
 	assert.Nil(t, err)
 	assert.Len(t, valuesRows, 2)
@@ -279,7 +284,7 @@ oscar: dog
 		"recursive": {Description: "an empty object", Default: "default"},
 	}
 
-	valuesRows, err := createValueRowsFromObject("", helmValues, descriptions, true)
+	valuesRows, err := createValueRowsFromObject(viper.GetViper(), "", helmValues, descriptions, true)
 
 	assert.Nil(t, err)
 	assert.Len(t, valuesRows, 2)
@@ -300,7 +305,7 @@ birds: []
 echo: cat
 	`)
 
-	valuesRows, err := createValueRowsFromObject("", helmValues, make(map[string]helm.ChartValueDescription), true)
+	valuesRows, err := createValueRowsFromObject(viper.GetViper(), "", helmValues, make(map[string]helm.ChartValueDescription), true)
 
 	assert.Nil(t, err)
 	assert.Len(t, valuesRows, 2)
@@ -327,7 +332,7 @@ echo: cat
 		"echo":  {Description: "echo"},
 	}
 
-	valuesRows, err := createValueRowsFromObject("", helmValues, descriptions, true)
+	valuesRows, err := createValueRowsFromObject(viper.GetViper(), "", helmValues, descriptions, true)
 
 	assert.Nil(t, err)
 	assert.Len(t, valuesRows, 2)
@@ -354,7 +359,7 @@ echo: cat
 		"echo":  {Description: "echo", Default: "default value"},
 	}
 
-	valuesRows, err := createValueRowsFromObject("", helmValues, descriptions, true)
+	valuesRows, err := createValueRowsFromObject(viper.GetViper(), "", helmValues, descriptions, true)
 
 	assert.Nil(t, err)
 	assert.Len(t, valuesRows, 2)
@@ -375,7 +380,7 @@ func TestListOfStrings(t *testing.T) {
 cats: [echo, foxtrot]
 	`)
 
-	valuesRows, err := createValueRowsFromObject("", helmValues, make(map[string]helm.ChartValueDescription), true)
+	valuesRows, err := createValueRowsFromObject(viper.GetViper(), "", helmValues, make(map[string]helm.ChartValueDescription), true)
 
 	assert.Nil(t, err)
 	assert.Len(t, valuesRows, 2)
@@ -402,7 +407,7 @@ cats: [echo, foxtrot]
 		"cats[1]": {Description: "the friendly one"},
 	}
 
-	valuesRows, err := createValueRowsFromObject("", helmValues, descriptions, true)
+	valuesRows, err := createValueRowsFromObject(viper.GetViper(), "", helmValues, descriptions, true)
 
 	assert.Nil(t, err)
 	assert.Len(t, valuesRows, 2)
@@ -429,7 +434,7 @@ cats: [echo, foxtrot]
 		"cats[1]": {Description: "the friendly one", Default: "default value"},
 	}
 
-	valuesRows, err := createValueRowsFromObject("", helmValues, descriptions, true)
+	valuesRows, err := createValueRowsFromObject(viper.GetViper(), "", helmValues, descriptions, true)
 
 	assert.Nil(t, err)
 	assert.Len(t, valuesRows, 2)
@@ -455,7 +460,7 @@ animals:
     type: dog
 	`)
 
-	valuesRows, err := createValueRowsFromObject("", helmValues, make(map[string]helm.ChartValueDescription), true)
+	valuesRows, err := createValueRowsFromObject(viper.GetViper(), "", helmValues, make(map[string]helm.ChartValueDescription), true)
 
 	assert.Nil(t, err)
 	assert.Len(t, valuesRows, 5)
@@ -501,7 +506,7 @@ animals:
 		"animals[1].elements[0]": {Description: "the sleepy one"},
 	}
 
-	valuesRows, err := createValueRowsFromObject("", helmValues, descriptions, true)
+	valuesRows, err := createValueRowsFromObject(viper.GetViper(), "", helmValues, descriptions, true)
 
 	assert.Nil(t, err)
 	assert.Len(t, valuesRows, 5)
@@ -547,7 +552,7 @@ animals:
 		"animals[1].elements[0]": {Description: "the sleepy one", Default: "value"},
 	}
 
-	valuesRows, err := createValueRowsFromObject("", helmValues, descriptions, true)
+	valuesRows, err := createValueRowsFromObject(viper.GetViper(), "", helmValues, descriptions, true)
 
 	assert.Nil(t, err)
 	assert.Len(t, valuesRows, 5)
@@ -591,7 +596,7 @@ animals:
 		"animals": {Description: "all the animals of the house"},
 	}
 
-	valuesRows, err := createValueRowsFromObject("", helmValues, descriptions, true)
+	valuesRows, err := createValueRowsFromObject(viper.GetViper(), "", helmValues, descriptions, true)
 
 	assert.Nil(t, err)
 	assert.Len(t, valuesRows, 1)
@@ -615,7 +620,7 @@ animals:
 		"animals": {Description: "all the animals of the house", Default: "cat and dog"},
 	}
 
-	valuesRows, err := createValueRowsFromObject("", helmValues, descriptions, true)
+	valuesRows, err := createValueRowsFromObject(viper.GetViper(), "", helmValues, descriptions, true)
 
 	assert.Nil(t, err)
 	assert.Len(t, valuesRows, 1)
@@ -639,7 +644,7 @@ animals:
 		"animals[0]": {Description: "all the cats of the house", Default: "only cats here"},
 	}
 
-	valuesRows, err := createValueRowsFromObject("", helmValues, descriptions, true)
+	valuesRows, err := createValueRowsFromObject(viper.GetViper(), "", helmValues, descriptions, true)
 
 	assert.Nil(t, err)
 	assert.Len(t, valuesRows, 3)
@@ -673,7 +678,7 @@ animals:
 		"animals.byTrait": {Description: "animals listed by their various characteristics"},
 	}
 
-	valuesRows, err := createValueRowsFromObject("", helmValues, descriptions, true)
+	valuesRows, err := createValueRowsFromObject(viper.GetViper(), "", helmValues, descriptions, true)
 
 	assert.Nil(t, err)
 	assert.Len(t, valuesRows, 1)
@@ -697,7 +702,7 @@ animals:
 		"animals.byTrait": {Description: "animals listed by their various characteristics", Default: "animals, you know"},
 	}
 
-	valuesRows, err := createValueRowsFromObject("", helmValues, descriptions, true)
+	valuesRows, err := createValueRowsFromObject(viper.GetViper(), "", helmValues, descriptions, true)
 
 	assert.Nil(t, err)
 	assert.Len(t, valuesRows, 1)
@@ -724,7 +729,7 @@ animals:
 		"animals.byTrait.friendly[0]": {Description: "best cat ever"},
 	}
 
-	valuesRows, err := createValueRowsFromObject("", helmValues, descriptions, true)
+	valuesRows, err := createValueRowsFromObject(viper.GetViper(), "", helmValues, descriptions, true)
 
 	assert.Nil(t, err)
 	assert.Len(t, valuesRows, 4)
@@ -766,7 +771,7 @@ animals:
 		"animals.byTrait.friendly[0]": {Description: "best cat ever", Default: "value"},
 	}
 
-	valuesRows, err := createValueRowsFromObject("", helmValues, descriptions, true)
+	valuesRows, err := createValueRowsFromObject(viper.GetViper(), "", helmValues, descriptions, true)
 
 	assert.Nil(t, err)
 	assert.Len(t, valuesRows, 4)
@@ -806,7 +811,7 @@ animals:
 		"animals.nonWeirdCats": {Description: "the cats that we have that are not weird"},
 	}
 
-	valuesRows, err := createValueRowsFromObject("", helmValues, descriptions, true)
+	valuesRows, err := createValueRowsFromObject(viper.GetViper(), "", helmValues, descriptions, true)
 
 	assert.Nil(t, err)
 	assert.Len(t, valuesRows, 3)
@@ -822,7 +827,7 @@ animals:
 	assert.Equal(t, "the list of birds we have", valuesRows[1].Description)
 
 	assert.Equal(t, "animals.nonWeirdCats", valuesRows[2].Key)
-	assert.Equal(t, stringType, valuesRows[2].Type)
+	assert.Equal(t, nullType, valuesRows[2].Type)
 	assert.Equal(t, "`nil`", valuesRows[2].Default)
 	assert.Equal(t, "the cats that we have that are not weird", valuesRows[2].Description)
 }
@@ -841,7 +846,7 @@ animals:
 		"animals.nonWeirdCats": {Description: "the cats that we have that are not weird", Default: "default"},
 	}
 
-	valuesRows, err := createValueRowsFromObject("", helmValues, descriptions, true)
+	valuesRows, err := createValueRowsFromObject(viper.GetViper(), "", helmValues, descriptions, true)
 
 	assert.Nil(t, err)
 	assert.Len(t, valuesRows, 3)
@@ -857,7 +862,7 @@ animals:
 	assert.Equal(t, "the list of birds we have", valuesRows[1].Description)
 
 	assert.Equal(t, "animals.nonWeirdCats", valuesRows[2].Key)
-	assert.Equal(t, stringType, valuesRows[2].Type)
+	assert.Equal(t, nullType, valuesRows[2].Type)
 	assert.Equal(t, "default", valuesRows[2].Default)
 	assert.Equal(t, "the cats that we have that are not weird", valuesRows[2].Description)
 }
@@ -870,7 +875,7 @@ fullNames:
   John Norwood: me
 `)
 
-	valuesRows, err := createValueRowsFromObject("", helmValues, make(map[string]helm.ChartValueDescription), true)
+	valuesRows, err := createValueRowsFromObject(viper.GetViper(), "", helmValues, make(map[string]helm.ChartValueDescription), true)
 
 	assert.Nil(t, err)
 	assert.Len(t, valuesRows, 2)
@@ -899,7 +904,7 @@ fullNames:
 		`websites."stupidchess.jmn23.com"`: {Description: "status of the stupidchess website"},
 	}
 
-	valuesRows, err := createValueRowsFromObject("", helmValues, descriptions, true)
+	valuesRows, err := createValueRowsFromObject(viper.GetViper(), "", helmValues, descriptions, true)
 
 	assert.Nil(t, err)
 	assert.Len(t, valuesRows, 2)
@@ -928,7 +933,7 @@ fullNames:
 		`websites."stupidchess.jmn23.com"`: {Description: "status of the stupidchess website", Default: "value"},
 	}
 
-	valuesRows, err := createValueRowsFromObject("", helmValues, descriptions, true)
+	valuesRows, err := createValueRowsFromObject(viper.GetViper(), "", helmValues, descriptions, true)
 
 	assert.Nil(t, err)
 	assert.Len(t, valuesRows, 2)
@@ -951,7 +956,7 @@ func TestNonStringKeys(t *testing.T) {
 true: "true"
 `)
 
-	valuesRows, err := createValueRowsFromObject("", helmValues, make(map[string]helm.ChartValueDescription), true)
+	valuesRows, err := createValueRowsFromObject(viper.GetViper(), "", helmValues, make(map[string]helm.ChartValueDescription), true)
 
 	assert.Nil(t, err)
 	assert.Len(t, valuesRows, 3)
@@ -971,3 +976,492 @@ true: "true"
 	assert.Equal(t, "`\"three\"`", valuesRows[2].Default)
 	assert.Equal(t, "", valuesRows[2].Description)
 }
+
+func TestRequiredValues(t *testing.T) {
+	helmValues := parseYamlValues(`
+hello: world
+password:
+	`)
+
+	descriptions := map[string]helm.ChartValueDescription{
+		"hello":    {Description: "hello"},
+		"password": {Description: "(string) the admin password", Required: true},
+	}
+
+	valuesRows, err := createValueRowsFromObject(viper.GetViper(), "", helmValues, descriptions, true)
+
+	assert.Nil(t, err)
+	assert.Len(t, valuesRows, 2)
+
+	assert.Equal(t, "hello", valuesRows[0].Key)
+	assert.False(t, valuesRows[0].Required)
+
+	assert.Equal(t, "password", valuesRows[1].Key)
+	assert.Equal(t, stringType, valuesRows[1].Type)
+	assert.Equal(t, "the admin password", valuesRows[1].Description)
+	assert.True(t, valuesRows[1].Required)
+}
+
+func TestSensitiveValues(t *testing.T) {
+	helmValues := parseYamlValues(`
+credentials:
+  password: hunter2
+  token:
+	`)
+
+	descriptions := map[string]helm.ChartValueDescription{
+		"credentials.password": {Description: "the admin password", Default: "the password", Sensitive: true},
+		"credentials.token":    {Description: "(string) an API token", Sensitive: true},
+	}
+
+	valuesRows, err := createValueRowsFromObject(viper.GetViper(), "", helmValues, descriptions, true)
+
+	assert.Nil(t, err)
+	assert.Len(t, valuesRows, 2)
+
+	assert.Equal(t, "credentials.password", valuesRows[0].Key)
+	assert.Equal(t, stringType, valuesRows[0].Type)
+	assert.Equal(t, "`<redacted>`", valuesRows[0].Default)
+
+	assert.Equal(t, "credentials.token", valuesRows[1].Key)
+	assert.Equal(t, stringType, valuesRows[1].Type)
+	assert.Equal(t, "`<redacted>`", valuesRows[1].Default)
+	assert.Equal(t, "an API token", valuesRows[1].Description)
+}
+
+func TestTypeOverrides(t *testing.T) {
+	helmValues := parseYamlValues(`
+fullnameOverride: "{{ .Release.Name }}-app"
+timeout:
+	`)
+
+	descriptions := map[string]helm.ChartValueDescription{
+		"fullnameOverride": {Description: "templated full name", Type: "tpl/string"},
+		"timeout":          {Description: "(string) how long to wait", Type: "duration"},
+	}
+
+	valuesRows, err := createValueRowsFromObject(viper.GetViper(), "", helmValues, descriptions, true)
+
+	assert.Nil(t, err)
+	assert.Len(t, valuesRows, 2)
+
+	assert.Equal(t, "fullnameOverride", valuesRows[0].Key)
+	assert.Equal(t, "tpl/string", valuesRows[0].Type)
+
+	assert.Equal(t, "timeout", valuesRows[1].Key)
+	assert.Equal(t, "duration", valuesRows[1].Type)
+	assert.Equal(t, "how long to wait", valuesRows[1].Description)
+}
+
+func TestAnchorsAndMergeKeys(t *testing.T) {
+	helmValues := parseYamlValues(`
+defaults: &defaults
+  cpu: 100m
+  memory: 128Mi
+web: *defaults
+worker:
+  <<: *defaults
+  memory: 256Mi
+	`)
+
+	descriptions := map[string]helm.ChartValueDescription{
+		"defaults": {Description: "default resources"},
+		"web":      {Description: "resources for the web pods"},
+	}
+
+	valuesRows, err := createValueRowsFromObject(viper.GetViper(), "", helmValues, descriptions, true)
+
+	assert.Nil(t, err)
+	assert.Len(t, valuesRows, 4)
+
+	assert.Equal(t, "defaults", valuesRows[0].Key)
+	assert.Equal(t, "`{\"cpu\":\"100m\",\"memory\":\"128Mi\"}`", valuesRows[0].Default)
+
+	assert.Equal(t, "web", valuesRows[1].Key)
+	assert.Equal(t, objectType, valuesRows[1].Type)
+	assert.Equal(t, "`{\"cpu\":\"100m\",\"memory\":\"128Mi\"}`", valuesRows[1].Default)
+
+	assert.Equal(t, "worker.cpu", valuesRows[2].Key)
+	assert.Equal(t, "`\"100m\"`", valuesRows[2].Default)
+
+	assert.Equal(t, "worker.memory", valuesRows[3].Key)
+	assert.Equal(t, "`\"256Mi\"`", valuesRows[3].Default)
+}
+
+func TestSortValueRowsInFileOrder(t *testing.T) {
+	valueRows := []valueRow{
+		{Key: "affinity"},
+		{Key: "image.repository"},
+		{Key: "image.tag"},
+		{Key: `podAnnotations."prometheus.io/scrape"`},
+		{Key: "replicaCount"},
+		{Key: "worker.cpu"},
+	}
+
+	lineNumbers := map[string]int{
+		"replicaCount":     1,
+		"image":            2,
+		"image.tag":        3,
+		"image.repository": 4,
+		"worker":           5,
+		"podAnnotations":   8,
+		"affinity":         9,
+	}
+
+	err := sortValueRows(valueRows, fileSortOrder, lineNumbers)
+	assert.Nil(t, err)
+
+	keys := make([]string, 0)
+	for _, r := range valueRows {
+		keys = append(keys, r.Key)
+	}
+
+	assert.Equal(t, []string{"replicaCount", "image.tag", "image.repository", "worker.cpu", `podAnnotations."prometheus.io/scrape"`, "affinity"}, keys)
+	assert.NotNil(t, sortValueRows(valueRows, "random", lineNumbers))
+}
+
+func TestIgnoredValues(t *testing.T) {
+	helmValues := parseYamlValues(`
+image:
+  experimental: false
+  tag: latest
+internal:
+  featureFlag: true
+  nested:
+    value: 1
+	`)
+
+	descriptions := map[string]helm.ChartValueDescription{
+		"image.experimental": {Description: "an experimental image setting", Ignored: true},
+		"internal":           {Ignored: true},
+	}
+
+	valuesRows, err := createValueRowsFromObject(viper.GetViper(), "", helmValues, descriptions, true)
+
+	assert.Nil(t, err)
+	assert.Len(t, valuesRows, 1)
+	assert.Equal(t, "image.tag", valuesRows[0].Key)
+}
+
+func TestCollapsedDefaults(t *testing.T) {
+	helmValues := parseYamlValues(`
+resources:
+  limits:
+    cpu: 100m
+tolerations: []
+	`)
+
+	descriptions := map[string]helm.ChartValueDescription{
+		"resources":   {Description: "resource requests and limits", Collapse: true},
+		"tolerations": {Description: "tolerations for the pods"},
+	}
+
+	valuesRows, err := createValueRowsFromObject(viper.GetViper(), "", helmValues, descriptions, true)
+
+	assert.Nil(t, err)
+	assert.Len(t, valuesRows, 2)
+
+	assert.Equal(t, "resources", valuesRows[0].Key)
+	assert.Equal(t, `<details><summary>Expand</summary><pre lang="json">{<br>  &#34;limits&#34;: {<br>    &#34;cpu&#34;: &#34;100m&#34;<br>  }<br>}</pre></details>`, valuesRows[0].Default)
+
+	assert.Equal(t, "tolerations", valuesRows[1].Key)
+	assert.Equal(t, "`[]`", valuesRows[1].Default)
+}
+
+func TestYamlDefaultFormat(t *testing.T) {
+	viper.Set("default-format", "yaml")
+	defer viper.Set("default-format", "")
+
+	helmValues := parseYamlValues(`
+image:
+  tag: latest
+nodeSelector:
+  disk: ssd
+tolerations: []
+	`)
+
+	descriptions := map[string]helm.ChartValueDescription{
+		"nodeSelector": {Description: "node labels for pod assignment"},
+	}
+
+	valuesRows, err := createValueRowsFromObject(viper.GetViper(), "", helmValues, descriptions, true)
+
+	assert.Nil(t, err)
+	assert.Len(t, valuesRows, 3)
+
+	assert.Equal(t, "image.tag", valuesRows[0].Key)
+	assert.Equal(t, "`latest`", valuesRows[0].Default)
+
+	assert.Equal(t, "nodeSelector", valuesRows[1].Key)
+	assert.Equal(t, `<pre lang="yaml">disk: ssd</pre>`, valuesRows[1].Default)
+
+	assert.Equal(t, "tolerations", valuesRows[2].Key)
+	assert.Equal(t, "`[]`", valuesRows[2].Default)
+}
+
+func TestTableBreakersEscaped(t *testing.T) {
+	helmValues := parseYamlValues(`
+command: "echo foo | grep bar"
+template: "{{ printf ` + "`%s`" + ` .Values.name }}"
+script:
+	`)
+
+	descriptions := map[string]helm.ChartValueDescription{
+		"command": {Description: "a command, piped | through grep"},
+		"script":  {Description: "(string) a script", Default: "echo a | b"},
+	}
+
+	valuesRows, err := createValueRowsFromObject(viper.GetViper(), "", helmValues, descriptions, true)
+
+	assert.Nil(t, err)
+	assert.Len(t, valuesRows, 3)
+
+	assert.Equal(t, "command", valuesRows[0].Key)
+	assert.Equal(t, "`\"echo foo \\| grep bar\"`", valuesRows[0].Default)
+	assert.Equal(t, "a command, piped \\| through grep", valuesRows[0].Description)
+
+	assert.Equal(t, "script", valuesRows[1].Key)
+	assert.Equal(t, "echo a \\| b", valuesRows[1].Default)
+
+	assert.Equal(t, "template", valuesRows[2].Key)
+	assert.Equal(t, "`` \"{{ printf `%s` .Values.name }}\" ``", valuesRows[2].Default)
+}
+
+func TestEscapeTableCell(t *testing.T) {
+	assert.Equal(t, "first line<br>second \\| line<br>third", escapeTableCell("first line\nsecond | line\r\nthird"))
+}
+
+func TestNormalizeTrailingWhitespace(t *testing.T) {
+	assert.Equal(t, "# chart\n\nline one  \nline two\n", normalizeTrailingWhitespace("# chart \r\n\t\nline one    \nline two\t\n\n\n"))
+	assert.Equal(t, "", normalizeTrailingWhitespace("\n  \n"))
+	assert.Equal(t, "```diff\n-a \n+b\t\n```\nafter\n", normalizeTrailingWhitespace("```diff \n-a \n+b\t\n```\nafter \n"))
+}
+
+func TestRenderDocumentationCodeBlockWhitespace(t *testing.T) {
+	chartDirectory, err := ioutil.TempDir("", "helm-docs-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(chartDirectory)
+
+	viper.Set("template-file", []string{"README.md.gotmpl"})
+	viper.Set("output-file", "README.md")
+	defer viper.Set("template-file", nil)
+	defer viper.Set("output-file", nil)
+
+	documentationTemplate := "# {{ .Name }} \n\n```console\n$ cat <<EOF \nreplicas: 1  \n\tEOF\t\n```\n\n"
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(chartDirectory, "README.md.gotmpl"), []byte(documentationTemplate), 0644))
+
+	chartDocumentationInfo := helm.ChartDocumentationInfo{ChartDirectory: chartDirectory, ChartMeta: helm.ChartMeta{Name: "app"}}
+	documents, err := renderDocumentation(context.Background(), chartDocumentationInfo)
+	assert.Nil(t, err)
+	assert.Len(t, documents, 1)
+	assert.Equal(t, "# app\n\n```console\n$ cat <<EOF \nreplicas: 1  \n\tEOF\t\n```\n", documents[0].Documentation)
+
+	// Documentation written by one run is left as it is by the next
+	assert.Nil(t, ioutil.WriteFile(documents[0].OutputPath, []byte(documents[0].Documentation), 0644))
+	rerenderedDocuments, err := renderDocumentation(context.Background(), chartDocumentationInfo)
+	assert.Nil(t, err)
+
+	writtenDocumentation, err := ioutil.ReadFile(documents[0].OutputPath)
+	assert.Nil(t, err)
+	assert.Equal(t, string(writtenDocumentation), rerenderedDocuments[0].Documentation)
+}
+
+func TestCommentedOutValueRowsOrder(t *testing.T) {
+	descriptions := map[string]helm.ChartValueDescription{}
+	for _, k := range []string{"e", "b", "d", "a", "c"} {
+		descriptions[k] = helm.ChartValueDescription{Description: k, CommentedOut: true}
+	}
+
+	for i := 0; i < 10; i++ {
+		rows := createCommentedOutValueRows([]valueRow{}, descriptions)
+		assert.Equal(t, []string{"a", "b", "c", "d", "e"}, []string{rows[0].Key, rows[1].Key, rows[2].Key, rows[3].Key, rows[4].Key})
+	}
+}
+
+func TestGlobalValueKeys(t *testing.T) {
+	assert.True(t, isGlobalValueKey("global"))
+	assert.True(t, isGlobalValueKey("global.imageRegistry"))
+	assert.True(t, isGlobalValueKey("global[0]"))
+	assert.False(t, isGlobalValueKey("globalSettings"))
+	assert.False(t, isGlobalValueKey("image.global"))
+}
+
+func TestCommentedOutValueRows(t *testing.T) {
+	valuesRows := []valueRow{{Key: "persistence.size"}}
+	descriptions := map[string]helm.ChartValueDescription{
+		"persistence.size":         {Description: "size of the claim", CommentedOut: true},
+		"persistence.storageClass": {Description: "(string) storage class of the claim", CommentedOut: true},
+		"persistence.hidden":       {Description: "an ignored value", CommentedOut: true, Ignored: true},
+	}
+
+	commentedOutRows := createCommentedOutValueRows(valuesRows, descriptions)
+
+	assert.Len(t, commentedOutRows, 1)
+	assert.Equal(t, "persistence.storageClass", commentedOutRows[0].Key)
+	assert.Equal(t, stringType, commentedOutRows[0].Type)
+	assert.Equal(t, "`nil`", commentedOutRows[0].Default)
+	assert.Equal(t, "storage class of the claim", commentedOutRows[0].Description)
+}
+
+func TestMultiLineCustomDefault(t *testing.T) {
+	helmValues := parseYamlValues(`
+serverBlock: ""
+collapsed: ""
+	`)
+
+	descriptions := map[string]helm.ChartValueDescription{
+		"serverBlock": {Description: "a server block", Default: "server {\n  listen 8080; # a | b\n}"},
+		"collapsed":   {Description: "a server block", Default: "server {\n}", Collapse: true},
+	}
+
+	valuesRows, err := createValueRowsFromObject(viper.GetViper(), "", helmValues, descriptions, true)
+
+	assert.Nil(t, err)
+	assert.Len(t, valuesRows, 2)
+
+	assert.Equal(t, "collapsed", valuesRows[0].Key)
+	assert.Equal(t, "<details><summary>Expand</summary><pre>server {<br>}</pre></details>", valuesRows[0].Default)
+
+	assert.Equal(t, "serverBlock", valuesRows[1].Key)
+	assert.Equal(t, "<pre>server {<br>  listen 8080; # a &#124; b<br>}</pre>", valuesRows[1].Default)
+}
+
+func TestValueRowsUnder(t *testing.T) {
+	templateData := chartTemplateData{
+		Values: []valueRow{{Key: "global.image"}, {Key: "ingress"}, {Key: "ingress.hosts[0]"}, {Key: "ingressClass"}, {Key: "service.port"}},
+	}
+
+	assert.Equal(t, []valueRow{{Key: "ingress"}, {Key: "ingress.hosts[0]"}}, valueRowsUnder("ingress", templateData))
+	assert.Equal(t, []valueRow{{Key: "global.image"}}, valueRowsUnder("global", templateData))
+	assert.Equal(t, []valueRow{}, valueRowsUnder("persistence", templateData))
+}
+
+func TestWithoutGlobalValues(t *testing.T) {
+	templateData := chartTemplateData{
+		Values:       []valueRow{{Key: "global.image"}, {Key: "globalThing"}, {Key: "replicas"}},
+		GlobalValues: []valueRow{{Key: "global.image"}},
+	}
+
+	assert.Equal(t, []valueRow{{Key: "globalThing"}, {Key: "replicas"}}, withoutGlobalValues(templateData).Values)
+	assert.Equal(t, []valueRow{{Key: "global.image"}}, withoutGlobalValues(templateData).GlobalValues)
+	assert.Len(t, templateData.Values, 3)
+}
+
+func TestValueSections(t *testing.T) {
+	assert.Empty(t, valueSections([]valueRow{{Key: "a"}, {Key: "b"}}))
+
+	assert.Equal(t, []valueSection{
+		{Name: "Networking", Values: []valueRow{{Key: "ingress", Section: "Networking"}, {Key: "service", Section: "Networking"}}},
+		{Name: "Image", Values: []valueRow{{Key: "image", Section: "Image"}}},
+		{Values: []valueRow{{Key: "affinity"}, {Key: "replicas"}}},
+	}, valueSections([]valueRow{
+		{Key: "affinity"},
+		{Key: "ingress", Section: "Networking"},
+		{Key: "image", Section: "Image"},
+		{Key: "replicas"},
+		{Key: "service", Section: "Networking"},
+	}))
+}
+
+func TestRenderDocumentationValueSections(t *testing.T) {
+	chartDirectory, err := ioutil.TempDir("", "helm-docs-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(chartDirectory)
+
+	viper.Set("template-file", []string{"README.md.gotmpl"})
+	viper.Set("output-file", "README.md")
+	defer viper.Set("template-file", nil)
+	defer viper.Set("output-file", nil)
+
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(chartDirectory, "README.md.gotmpl"), []byte(`{{ template "chart.valuesSection" . }}`), 0644))
+
+	chartDocumentationInfo := helm.ChartDocumentationInfo{
+		ChartDirectory: chartDirectory,
+		ChartMeta:      helm.ChartMeta{Name: "app"},
+		ChartValues:    parseYamlValues("replicas: 1\nimage: nginx\n"),
+		ChartValuesDescriptions: map[string]helm.ChartValueDescription{
+			"image":    {Description: "The image", Section: "Image"},
+			"replicas": {Description: "Number of pods"},
+		},
+	}
+
+	documents, err := renderDocumentation(context.Background(), chartDocumentationInfo)
+	assert.Nil(t, err)
+	assert.Equal(t, `## Chart Values
+
+### Image
+
+| Key | Type | Default | Description |
+|-----|------|---------|-------------|
+| <a id="`+valueAnchor("image")+`"></a>image | string | `+"`\"nginx\"`"+` | The image |
+
+### Other Values
+
+| Key | Type | Default | Description |
+|-----|------|---------|-------------|
+| <a id="`+valueAnchor("replicas")+`"></a>replicas | int | `+"`1`"+` | Number of pods |
+`, documents[0].Documentation)
+}
+
+func TestRenderDocumentationGlobalValueLinks(t *testing.T) {
+	chartDirectory, err := ioutil.TempDir("", "helm-docs-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(chartDirectory)
+
+	viper.Set("template-file", []string{"README.md.gotmpl"})
+	viper.Set("output-file", "README.md")
+	defer viper.Set("template-file", nil)
+	defer viper.Set("output-file", nil)
+
+	// The default layout, linking to a global value after it
+	documentationTemplate := defaultDocumentationTemplate + "\nSee {{ valueLink \"global.image\" }} and {{ valueLink \"replicas\" }}\n"
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(chartDirectory, "README.md.gotmpl"), []byte(documentationTemplate), 0644))
+
+	chartDocumentationInfo := helm.ChartDocumentationInfo{
+		ChartDirectory: chartDirectory,
+		ChartMeta:      helm.ChartMeta{Name: "app"},
+		ChartValues:    parseYamlValues("global:\n  image: nginx\nreplicas: 1\n"),
+		ChartValuesDescriptions: map[string]helm.ChartValueDescription{
+			"global.image": {Description: "The image of every pod"},
+			"replicas":     {Description: "Number of pods"},
+		},
+	}
+
+	documents, err := renderDocumentation(context.Background(), chartDocumentationInfo)
+	assert.Nil(t, err)
+	assert.Len(t, documents, 1)
+
+	documentation := documents[0].Documentation
+	assert.Contains(t, documentation, "See [global.image](#"+valueAnchor("global.image")+") and [replicas](#"+valueAnchor("replicas")+")")
+
+	// Each value is anchored exactly once, global values in the global values table, as the values table leaves them out
+	globalValuesSection := documentation[strings.Index(documentation, "Global Values"):]
+	assert.Equal(t, 1, strings.Count(documentation, `<a id="`+valueAnchor("global.image")+`"></a>`))
+	assert.Contains(t, globalValuesSection, `<a id="`+valueAnchor("global.image")+`"></a>`)
+	assert.Equal(t, 1, strings.Count(documentation, `<a id="`+valueAnchor("replicas")+`"></a>`))
+}
+
+func TestNamedSources(t *testing.T) {
+	chartDocumentationInfo := helm.ChartDocumentationInfo{ChartMeta: helm.ChartMeta{
+		Sources:     []string{"https://github.com/org/charts", "https://github.com/nginx/nginx/", "charts"},
+		Annotations: map[string]string{"helm-docs.io/sources": "Chart source: https://github.com/org/charts\n"},
+	}}
+
+	namedSources, err := getNamedSourcesTemplateData(chartDocumentationInfo)
+
+	assert.Nil(t, err)
+	assert.Equal(t, []namedSourceTemplateData{
+		{Name: "Chart source", URL: "https://github.com/org/charts"},
+		{Name: "github.com/nginx/nginx", URL: "https://github.com/nginx/nginx/"},
+		{Name: "charts", URL: "charts"},
+	}, namedSources)
+}
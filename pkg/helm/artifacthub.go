@@ -0,0 +1,53 @@
+package helm
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Chart.yaml annotations maintained for Artifact Hub, see https://artifacthub.io/docs/topics/annotations/helm/
+const (
+	artifactHubImagesAnnotation  = "artifacthub.io/images"
+	artifactHubLicenseAnnotation = "artifacthub.io/license"
+)
+
+// parseArtifactHubImages parses the images annotation, a yaml list of the images the chart uses, e.g.
+// "- name: nginx\n  image: nginx:1.19"
+func parseArtifactHubImages(annotatedImages string) ([]ChartImage, error) {
+	var images []struct {
+		Name  string
+		Image string
+	}
+
+	if err := yaml.Unmarshal([]byte(annotatedImages), &images); err != nil {
+		return nil, fmt.Errorf("invalid %s annotation, must be a list of images: %s", artifactHubImagesAnnotation, err)
+	}
+
+	chartImages := make([]ChartImage, 0, len(images))
+	for _, i := range images {
+		repository, tag := splitImageReference(i.Image)
+		chartImages = append(chartImages, ChartImage{Repository: repository, Tag: tag})
+	}
+
+	return uniqueSortedImages(chartImages), nil
+}
+
+// applyArtifactHubAnnotations documents the images and license a chart declares in its Artifact Hub annotations, which
+// are maintained by hand and so take precedence over those helm-docs finds itself
+func applyArtifactHubAnnotations(chartDocInfo *ChartDocumentationInfo) error {
+	if annotatedImages, ok := chartDocInfo.Annotations[artifactHubImagesAnnotation]; ok {
+		images, err := parseArtifactHubImages(annotatedImages)
+		if err != nil {
+			return err
+		}
+
+		chartDocInfo.ChartImages = images
+	}
+
+	if licenseID, ok := chartDocInfo.Annotations[artifactHubLicenseAnnotation]; ok && licenseID != "" {
+		chartDocInfo.ChartLicense.ID = licenseID
+	}
+
+	return nil
+}
@@ -0,0 +1,117 @@
+package helm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v2"
+)
+
+// ChartChangelogCommit is a commit changing the files of a chart
+type ChartChangelogCommit struct {
+	Hash      string
+	ShortHash string
+	Date      string
+	Author    string
+	Subject   string
+}
+
+// ChartChangelogRelease groups the commits made to a chart by the version of the chart they were released in, that set
+// by the commit bumping the version in Chart.yaml which is the latest of them. Commits made since the version was last
+// bumped are grouped under an empty version
+type ChartChangelogRelease struct {
+	Version string
+	Date    string
+	Commits []ChartChangelogCommit
+}
+
+func parseGitLog(output string) []ChartChangelogCommit {
+	commits := make([]ChartChangelogCommit, 0)
+
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.SplitN(line, gitLogFieldSeparator, 5)
+		if len(fields) != 5 {
+			continue
+		}
+
+		commits = append(commits, ChartChangelogCommit{
+			Hash:      fields[0],
+			ShortHash: fields[1],
+			Date:      fields[2],
+			Author:    fields[3],
+			Subject:   fields[4],
+		})
+	}
+
+	return commits
+}
+
+// findChartVersionBumps finds the commits changing the version in a chart's Chart.yaml, returning the version each set.
+// The Chart.yaml of a packaged chart isn't in git, so none are found for them
+func findChartVersionBumps(ctx context.Context, chartDirectory string) (map[string]string, error) {
+	if IsChartArchive(chartDirectory) {
+		return map[string]string{}, nil
+	}
+
+	output, err := runGit(ctx, chartDirectory, "log", "--format=%H", "-G", "^version:", "--", "Chart.yaml")
+	if err != nil {
+		return nil, err
+	}
+
+	versions := make(map[string]string)
+	for _, hash := range strings.Fields(output) {
+		chartFile, err := runGit(ctx, chartDirectory, "show", fmt.Sprintf("%s:./Chart.yaml", hash))
+		if err != nil {
+			return nil, err
+		}
+
+		var chartMeta ChartMeta
+		if err := yaml.Unmarshal([]byte(chartFile), &chartMeta); err != nil {
+			log.Warnf("Error parsing Chart.yaml of chart %s as of commit %s, ignoring its version: %s", chartDirectory, hash, err)
+			continue
+		}
+
+		versions[hash] = chartMeta.Version
+	}
+
+	return versions, nil
+}
+
+// groupChangelogCommits groups commits, newest first, by the version bump commit at or before them in the history
+func groupChangelogCommits(commits []ChartChangelogCommit, versionBumps map[string]string) []ChartChangelogRelease {
+	releases := make([]ChartChangelogRelease, 0)
+
+	for _, c := range commits {
+		if version, ok := versionBumps[c.Hash]; ok && (len(releases) == 0 || releases[len(releases)-1].Version != version) {
+			releases = append(releases, ChartChangelogRelease{Version: version, Date: c.Date})
+		} else if len(releases) == 0 {
+			releases = append(releases, ChartChangelogRelease{})
+		}
+
+		releases[len(releases)-1].Commits = append(releases[len(releases)-1].Commits, c)
+	}
+
+	return releases
+}
+
+// parseChartChangelog builds a changelog of a chart from the git log of its directory. Charts which aren't in a git
+// repository have no changelog, which is left empty with a warning rather than failing the chart's documentation
+func parseChartChangelog(ctx context.Context, chartDirectory string) []ChartChangelogRelease {
+	format := strings.Join([]string{"%H", "%h", "%ad", "%an", "%s"}, "%x1f")
+	gitDirectory, gitPath := chartGitPath(chartDirectory)
+	output, err := runGit(ctx, gitDirectory, "log", "--date=short", fmt.Sprintf("--format=%s", format), "--", gitPath)
+	if err != nil {
+		log.Warnf("Error reading the git history of chart %s, leaving its changelog empty: %s", chartDirectory, err)
+		return []ChartChangelogRelease{}
+	}
+
+	versionBumps, err := findChartVersionBumps(ctx, chartDirectory)
+	if err != nil {
+		log.Warnf("Error finding the version bumps of chart %s, leaving its changelog empty: %s", chartDirectory, err)
+		return []ChartChangelogRelease{}
+	}
+
+	return groupChangelogCommits(parseGitLog(output), versionBumps)
+}
@@ -0,0 +1,26 @@
+package helm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroupChangelogCommits(t *testing.T) {
+	commits := parseGitLog("e\x1fe1\x1f2020-05-05\x1fjane\x1fFix typo in NOTES.txt\n" +
+		"d\x1fd1\x1f2020-04-04\x1fjane\x1fBump to 1.1.0\n" +
+		"c\x1fc1\x1f2020-03-03\x1fjohn\x1fAdd ingress\n" +
+		"b\x1fb1\x1f2020-02-02\x1fjohn\x1fBump to 1.0.0\n" +
+		"a\x1fa1\x1f2020-01-01\x1fjohn\x1fAdd chart\n")
+
+	releases := groupChangelogCommits(commits, map[string]string{"d": "1.1.0", "b": "1.0.0", "a": "0.1.0"})
+
+	assert.Equal(t, []ChartChangelogRelease{
+		{Commits: []ChartChangelogCommit{commits[0]}},
+		{Version: "1.1.0", Date: "2020-04-04", Commits: []ChartChangelogCommit{commits[1], commits[2]}},
+		{Version: "1.0.0", Date: "2020-02-02", Commits: []ChartChangelogCommit{commits[3]}},
+		{Version: "0.1.0", Date: "2020-01-01", Commits: []ChartChangelogCommit{commits[4]}},
+	}, releases)
+
+	assert.Equal(t, ChartChangelogCommit{Hash: "c", ShortHash: "c1", Date: "2020-03-03", Author: "john", Subject: "Add ingress"}, commits[2])
+}
@@ -0,0 +1,111 @@
+package helm
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// IsChartArchive returns whether a chart path is that of a packaged chart, as made by helm package, rather than of a
+// chart directory
+func IsChartArchive(chartPath string) bool {
+	return strings.HasSuffix(chartPath, ".tgz")
+}
+
+// readPackagedChart reads all of the files of a packaged chart into memory, keyed by their paths relative to the chart's
+// directory within the archive
+func readPackagedChart(archive io.Reader, archivePath string) (map[string][]byte, error) {
+	gzipReader, err := gzip.NewReader(archive)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read chart archive %s: %s", archivePath, err)
+	}
+
+	defer gzipReader.Close()
+
+	files := make(map[string][]byte)
+	tarReader := tar.NewReader(gzipReader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to read chart archive %s: %s", archivePath, err)
+		}
+
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		// Every file in a packaged chart is in a directory named after the chart
+		nameParts := strings.SplitN(path.Clean(header.Name), "/", 2)
+		if len(nameParts) != 2 {
+			continue
+		}
+
+		contents, err := ioutil.ReadAll(tarReader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s from chart archive %s: %s", header.Name, archivePath, err)
+		}
+
+		files[nameParts[1]] = contents
+	}
+
+	if _, ok := files["Chart.yaml"]; !ok {
+		return nil, fmt.Errorf("failed to find Chart.yaml in chart archive %s", archivePath)
+	}
+
+	return files, nil
+}
+
+// readChartArchive reads all of the files of a packaged chart on disk into memory
+func readChartArchive(archivePath string) (map[string][]byte, error) {
+	archiveFile, err := os.Open(archivePath)
+	if err != nil {
+		return nil, err
+	}
+
+	defer archiveFile.Close()
+
+	return readPackagedChart(archiveFile, archivePath)
+}
+
+// findChartArchives returns the packaged charts given, along with those in any directories given
+func findChartArchives(archivePaths []string) ([]string, error) {
+	chartArchives := make([]string, 0)
+
+	for _, p := range archivePaths {
+		info, err := os.Stat(p)
+		if err != nil {
+			return chartArchives, err
+		}
+
+		if !info.IsDir() {
+			chartArchives = append(chartArchives, p)
+			continue
+		}
+
+		directoryArchives, err := filepath.Glob(filepath.Join(p, "*.tgz"))
+		if err != nil {
+			return chartArchives, err
+		}
+
+		chartArchives = append(chartArchives, directoryArchives...)
+	}
+
+	included := make([]string, 0, len(chartArchives))
+	for _, a := range chartArchives {
+		if isChartDirectoryIncluded(a) {
+			included = append(included, a)
+		}
+	}
+
+	return included, nil
+}
@@ -0,0 +1,137 @@
+package helm
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func writeChartArchive(t *testing.T, archivePath string, files map[string]string) {
+	archiveFile, err := os.Create(archivePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer archiveFile.Close()
+
+	gzipWriter := gzip.NewWriter(archiveFile)
+	tarWriter := tar.NewWriter(gzipWriter)
+
+	for name, contents := range files {
+		header := &tar.Header{Name: name, Mode: 0644, Size: int64(len(contents)), Typeflag: tar.TypeReg}
+		if err := tarWriter.WriteHeader(header); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := tarWriter.Write([]byte(contents)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	assert.Nil(t, tarWriter.Close())
+	assert.Nil(t, gzipWriter.Close())
+}
+
+func TestParseChartArchive(t *testing.T) {
+	archiveDirectory, err := ioutil.TempDir("", "helm-docs-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(archiveDirectory)
+
+	archivePath := path.Join(archiveDirectory, "app-1.0.0.tgz")
+	writeChartArchive(t, archivePath, map[string]string{
+		"app/Chart.yaml":          "apiVersion: v2\nname: app\nversion: 1.0.0\ndependencies:\n  - name: redis\n    version: 17.0.0\n",
+		"app/values.yaml":         "# replicas -- Number of pods\nreplicas: 2\n",
+		"app/templates/NOTES.txt": "Thanks for installing app\n",
+	})
+
+	chartDocumentationInfo, err := ParseChartInformation(archivePath)
+	assert.Nil(t, err)
+
+	assert.Equal(t, archivePath, chartDocumentationInfo.ChartDirectory)
+	assert.Equal(t, "app", chartDocumentationInfo.Name)
+	assert.Equal(t, "redis", chartDocumentationInfo.Dependencies[0].Name)
+	assert.Equal(t, 2, chartDocumentationInfo.ChartValues["replicas"])
+	assert.Equal(t, "Number of pods", chartDocumentationInfo.ChartValuesDescriptions["replicas"].Description)
+	assert.Equal(t, "Thanks for installing app", chartDocumentationInfo.ChartNotes)
+}
+
+func TestParseChartArchiveDependencies(t *testing.T) {
+	archiveDirectory, err := ioutil.TempDir("", "helm-docs-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(archiveDirectory)
+
+	redisArchivePath := path.Join(archiveDirectory, "redis-17.0.0.tgz")
+	writeChartArchive(t, redisArchivePath, map[string]string{
+		"redis/Chart.yaml":  "apiVersion: v2\nname: redis\nversion: 17.0.0\n",
+		"redis/values.yaml": "# port -- Port redis listens on\nport: 6379\n",
+	})
+
+	redisArchive, err := ioutil.ReadFile(redisArchivePath)
+	assert.Nil(t, err)
+
+	archivePath := path.Join(archiveDirectory, "app-1.0.0.tgz")
+	writeChartArchive(t, archivePath, map[string]string{
+		"app/Chart.yaml":                     "apiVersion: v2\nname: app\nversion: 1.0.0\ndependencies:\n  - name: redis\n    version: ~17.0.0\n    alias: cache\n  - name: common\n    version: 1.0.0\n",
+		"app/values.yaml":                    "replicas: 2\n",
+		"app/charts/redis-17.0.0.tgz":        string(redisArchive),
+		"app/charts/common/Chart.yaml":       "apiVersion: v2\nname: common\nversion: 1.0.0\n",
+		"app/charts/common/values.yaml":      "# labels -- Labels added to every resource\nlabels: {}\n",
+		"app/templates/tests/test-conn.yaml": "apiVersion: v1\nkind: Pod\nmetadata:\n  name: test-conn\n  annotations:\n    helm.sh/hook: test\n",
+	})
+
+	viper.Set("document-dependency-values", true)
+	viper.Set("document-subcharts", "embed")
+	defer viper.Set("document-dependency-values", false)
+	defer viper.Set("document-subcharts", "")
+
+	chartDocumentationInfo, err := ParseChartInformation(archivePath)
+	assert.Nil(t, err)
+
+	assert.Len(t, chartDocumentationInfo.ChartDependencyValues, 2)
+	assert.Equal(t, "common", chartDocumentationInfo.ChartDependencyValues[0].Name)
+	assert.Equal(t, "Labels added to every resource", chartDocumentationInfo.ChartDependencyValues[0].Descriptions["labels"].Description)
+	assert.Equal(t, "cache", chartDocumentationInfo.ChartDependencyValues[1].Name)
+	assert.Equal(t, "Port redis listens on", chartDocumentationInfo.ChartDependencyValues[1].Descriptions["port"].Description)
+
+	assert.Len(t, chartDocumentationInfo.ChartSubcharts, 1)
+	assert.Equal(t, "charts/common", chartDocumentationInfo.ChartSubcharts[0].Path)
+
+	assert.Len(t, chartDocumentationInfo.ChartTests, 1)
+	assert.Equal(t, "templates/tests/test-conn.yaml", chartDocumentationInfo.ChartTests[0].File)
+}
+
+func TestReadChartArchiveError(t *testing.T) {
+	archiveDirectory, err := ioutil.TempDir("", "helm-docs-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(archiveDirectory)
+
+	// A gzipped file that isn't a tar archive
+	var archive bytes.Buffer
+	gzipWriter := gzip.NewWriter(&archive)
+	_, err = gzipWriter.Write(bytes.Repeat([]byte("not a tar archive "), 64))
+	assert.Nil(t, err)
+	assert.Nil(t, gzipWriter.Close())
+
+	archivePath := path.Join(archiveDirectory, "app-1.0.0.tgz")
+	assert.Nil(t, ioutil.WriteFile(archivePath, archive.Bytes(), 0644))
+
+	_, err = readChartArchive(archivePath)
+	assert.NotNil(t, err)
+	assert.NotContains(t, err.Error(), "failed to find Chart.yaml")
+}
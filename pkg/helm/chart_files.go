@@ -0,0 +1,137 @@
+package helm
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// chartFiles are the files of a chart, read either from its directory or from a packaged chart, so that both are parsed
+// alike. Files are named by their slash separated paths relative to the chart's directory, e.g. templates/NOTES.txt
+type chartFiles interface {
+	// readFile reads a file of the chart, returning an error for which os.IsNotExist is true if the chart has no such file
+	readFile(name string) ([]byte, error)
+
+	// listFiles lists the files in a directory of the chart and its subdirectories, sorted
+	listFiles(directory string) ([]string, error)
+
+	// sub returns the files in a directory of the chart, e.g. those of a subchart unpacked in its charts/ directory
+	sub(directory string) chartFiles
+
+	// path returns the path of a file of the chart by which it's named in messages
+	path(name string) string
+}
+
+// directoryChartFiles are the files of a chart directory, read from disk as they're needed
+type directoryChartFiles string
+
+func (d directoryChartFiles) readFile(name string) ([]byte, error) {
+	return ioutil.ReadFile(d.path(name))
+}
+
+func (d directoryChartFiles) listFiles(directory string) ([]string, error) {
+	files := make([]string, 0)
+	err := filepath.Walk(d.path(directory), func(p string, info os.FileInfo, err error) error {
+		if os.IsNotExist(err) {
+			return filepath.SkipDir
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			return nil
+		}
+
+		name, err := filepath.Rel(string(d), p)
+		if err != nil {
+			return err
+		}
+
+		files = append(files, filepath.ToSlash(name))
+		return nil
+	})
+
+	sort.Strings(files)
+	return files, err
+}
+
+func (d directoryChartFiles) sub(directory string) chartFiles {
+	return directoryChartFiles(d.path(directory))
+}
+
+func (d directoryChartFiles) path(name string) string {
+	return path.Join(string(d), name)
+}
+
+// archiveChartFiles are the files of a packaged chart, all read into memory up front
+type archiveChartFiles struct {
+	archivePath string
+
+	// The directory of the files within the chart, e.g. charts/redis for those of a subchart, or "" for the chart's own
+	directory string
+
+	// The contents of every file in the archive, by their paths relative to the packaged chart's directory
+	files map[string][]byte
+}
+
+func (a archiveChartFiles) readFile(name string) ([]byte, error) {
+	contents, ok := a.files[path.Join(a.directory, name)]
+	if !ok {
+		return nil, &os.PathError{Op: "open", Path: a.path(name), Err: os.ErrNotExist}
+	}
+
+	return contents, nil
+}
+
+func (a archiveChartFiles) listFiles(directory string) ([]string, error) {
+	root := path.Join(a.directory, directory)
+	files := make([]string, 0)
+
+	for f := range a.files {
+		if root != "" && !strings.HasPrefix(f, root+"/") {
+			continue
+		}
+
+		if a.directory != "" {
+			f = strings.TrimPrefix(f, a.directory+"/")
+		}
+
+		files = append(files, f)
+	}
+
+	sort.Strings(files)
+	return files, nil
+}
+
+func (a archiveChartFiles) sub(directory string) chartFiles {
+	return archiveChartFiles{archivePath: a.archivePath, directory: path.Join(a.directory, directory), files: a.files}
+}
+
+func (a archiveChartFiles) path(name string) string {
+	return path.Join(a.archivePath, a.directory, name)
+}
+
+// openChartFiles returns the files of a chart directory, or of a packaged chart, which are read into memory
+func openChartFiles(chartDirectory string) (chartFiles, error) {
+	if !IsChartArchive(chartDirectory) {
+		return directoryChartFiles(chartDirectory), nil
+	}
+
+	files, err := readChartArchive(chartDirectory)
+	if err != nil {
+		return nil, err
+	}
+
+	return archiveChartFiles{archivePath: chartDirectory, files: files}, nil
+}
+
+// hasChartFile returns whether a chart has a file
+func hasChartFile(files chartFiles, name string) bool {
+	_, err := files.readFile(name)
+	return err == nil
+}
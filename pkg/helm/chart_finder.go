@@ -1,42 +1,279 @@
 package helm
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 
+	"github.com/Masterminds/semver"
+	"github.com/norwoodj/helm-docs/pkg/settings"
 	"github.com/norwoodj/helm-docs/pkg/util"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 )
 
+// isChartDirectoryMatched returns whether a chart directory found in the search matches the include patterns, if there
+// are any, and none of the exclude patterns
+func isChartDirectoryMatched(chartSettings *viper.Viper, chartDir string) bool {
+	includePatterns := chartSettings.GetStringSlice("include")
+	if len(includePatterns) > 0 && !util.MatchAnyGlob(includePatterns, chartDir) {
+		return false
+	}
+
+	return !util.MatchAnyGlob(chartSettings.GetStringSlice("exclude"), chartDir)
+}
+
+// isChartDirectoryIncluded returns whether a chart directory found in the search matches the include and exclude
+// patterns, and is of one of the charts named to document
+func isChartDirectoryIncluded(chartDir string) bool {
+	return isChartDirectoryMatched(viper.GetViper(), chartDir) && isChartNameIncluded(chartDir)
+}
+
+// isChartNameIncluded returns whether the name in a chart's Chart.yaml is one of the names of charts to document, if
+// any were given
+func isChartNameIncluded(chartDir string) bool {
+	chartNames := settings.GetStringSlice("chart")
+	if len(chartNames) == 0 {
+		return true
+	}
+
+	chartMeta, err := parseChartFile(chartDir)
+	if err != nil {
+		log.Warnf("Error reading the name of chart %s, skipping: %s", chartDir, err)
+		return false
+	}
+
+	for _, n := range chartNames {
+		if chartMeta.Name == n {
+			return true
+		}
+	}
+
+	return false
+}
+
+// FindChartDirectories searches each of the chart search roots recursively for charts, returning their directories.
+// Charts found under more than one of the roots are only returned once. If chart archives are given, those are returned
+// instead
 func FindChartDirectories() ([]string, error) {
-	ignoreFilename := viper.GetString("ignore-file")
+	if chartArchives := settings.GetStringSlice("chart-archive"); len(chartArchives) > 0 {
+		return findChartArchives(chartArchives)
+	}
+
+	return searchChartDirectories(viper.GetViper(), isChartDirectoryIncluded)
+}
+
+// searchChartDirectories searches each of the chart search roots recursively for the directories of charts that aren't
+// ignored and are included by the given function
+func searchChartDirectories(chartSettings *viper.Viper, isIncluded func(chartDir string) bool) ([]string, error) {
+	ignoreFilename := chartSettings.GetString("ignore-file")
 	ignoreContext := util.NewIgnoreContext(ignoreFilename)
 	chartDirs := make([]string, 0)
+	foundChartDirs := make(map[string]bool)
+
+	chartSearchRoots := chartSettings.GetStringSlice("chart-search-root")
+	if len(chartSearchRoots) == 0 {
+		chartSearchRoots = []string{"."}
+	}
+
+	for _, chartSearchRoot := range chartSearchRoots {
+		err := filepath.Walk(chartSearchRoot, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+
+			absolutePath, _ := filepath.Abs(path)
+
+			if info.IsDir() && ignoreContext.ShouldIgnore(absolutePath, info) {
+				log.Debugf("Ignoring directory %s", path)
+				return filepath.SkipDir
+			}
+
+			if filepath.Base(path) == "Chart.yaml" {
+				if ignoreContext.ShouldIgnore(absolutePath, info) {
+					log.Debugf("Ignoring chart file %s", path)
+					return nil
+				}
+
+				chartDir := filepath.Dir(path)
+				if !isIncluded(chartDir) {
+					log.Debugf("Excluding chart directory %s", chartDir)
+					return nil
+				}
+
+				absoluteChartDir := filepath.Dir(absolutePath)
+				if !foundChartDirs[absoluteChartDir] {
+					foundChartDirs[absoluteChartDir] = true
+					chartDirs = append(chartDirs, chartDir)
+				}
+			}
+
+			return nil
+		})
 
-	err := filepath.Walk(".", func(path string, info os.FileInfo, err error) error {
 		if err != nil {
-			return err
+			return chartDirs, err
 		}
+	}
+
+	return chartDirs, nil
+}
 
-		absolutePath, _ := filepath.Abs(path)
+// FindChartNames returns the names, as given in their Chart.yaml, of the charts found by FindChartDirectories
+func FindChartNames() ([]string, error) {
+	chartDirs, err := FindChartDirectories()
+	if err != nil {
+		return nil, err
+	}
 
-		if info.IsDir() && ignoreContext.ShouldIgnore(absolutePath, info) {
-			log.Debugf("Ignoring directory %s", path)
-			return filepath.SkipDir
+	chartNames := make([]string, 0, len(chartDirs))
+	for _, c := range chartDirs {
+		chartMeta, err := parseChartFile(c)
+		if err != nil {
+			return nil, err
 		}
 
-		if filepath.Base(path) == "Chart.yaml" {
-			if ignoreContext.ShouldIgnore(absolutePath, info) {
-				log.Debugf("Ignoring chart file %s", path)
-				return nil
-			}
+		chartNames = append(chartNames, chartMeta.Name)
+	}
+
+	return chartNames, nil
+}
+
+// LocalChart is a chart found in the chart search roots
+type LocalChart struct {
+	ChartMeta
+	Directory string
+}
+
+// The charts found in the chart search roots, which are searched once for each setting of the options affecting the
+// search, rather than for every chart whose dependencies are matched with them
+var localCharts struct {
+	sync.Mutex
+	searchKey string
+	charts    []LocalChart
+}
 
-			chartDirs = append(chartDirs, filepath.Dir(path))
+// findLocalCharts returns the charts found in the chart search roots, ignoring the names of charts to document, so that
+// dependencies are matched with the same charts however many of them are being documented, packaged charts included
+func findLocalCharts(chartSettings *viper.Viper) []LocalChart {
+	searchKey := fmt.Sprint(
+		chartSettings.GetStringSlice("chart-search-root"),
+		chartSettings.GetString("ignore-file"),
+		chartSettings.GetStringSlice("include"),
+		chartSettings.GetStringSlice("exclude"),
+	)
+
+	localCharts.Lock()
+	defer localCharts.Unlock()
+
+	if localCharts.charts != nil && localCharts.searchKey == searchKey {
+		return localCharts.charts
+	}
+
+	chartDirs, err := searchChartDirectories(chartSettings, func(chartDir string) bool {
+		return isChartDirectoryMatched(chartSettings, chartDir)
+	})
+	if err != nil {
+		log.Warnf("Error searching for charts to link dependencies to: %s", err)
+	}
+
+	charts := make([]LocalChart, 0, len(chartDirs))
+	for _, d := range chartDirs {
+		chartMeta, err := parseChartFile(d)
+		if err != nil {
+			log.Debugf("Error reading chart %s, not linking dependencies to it: %s", d, err)
+			continue
+		}
+
+		charts = append(charts, LocalChart{ChartMeta: chartMeta, Directory: d})
+	}
+
+	localCharts.searchKey = searchKey
+	localCharts.charts = charts
+	return charts
+}
+
+// ResetLocalCharts forgets the charts found in the chart search roots, so that they're searched again the next time
+// dependencies are linked. Processes running for longer than one generation of documentation, which may see charts
+// added, removed or changed in the meantime, call it before generating documentation again
+func ResetLocalCharts() {
+	localCharts.Lock()
+	defer localCharts.Unlock()
+
+	localCharts.searchKey = ""
+	localCharts.charts = nil
+}
+
+// isSameDirectory returns whether two paths, relative to the current directory unless absolute, are of the same directory
+func isSameDirectory(a string, b string) bool {
+	absoluteA, errA := filepath.Abs(a)
+	absoluteB, errB := filepath.Abs(b)
+	return errA == nil && errB == nil && absoluteA == absoluteB
+}
+
+// findLocalDependencyChart returns the chart found in the chart search roots which a chart's dependency is on, if any.
+// Dependencies in local file:// repositories are on the chart in that directory. Otherwise the dependency is on the
+// chart of the same name whose version satisfies the dependency's, other than the chart itself, if there's only one.
+// The paths of file:// repositories are relative to a chart's directory, so those of packaged charts are never found
+func findLocalDependencyChart(chartDirectory string, dependency ChartRequirementsItem, charts []LocalChart) *LocalChart {
+	if strings.HasPrefix(dependency.Repository, "file://") {
+		if IsChartArchive(chartDirectory) {
+			return nil
+		}
+
+		repositoryPath := strings.TrimPrefix(dependency.Repository, "file://")
+		if !filepath.IsAbs(repositoryPath) {
+			repositoryPath = filepath.Join(chartDirectory, repositoryPath)
+		}
+
+		for i := range charts {
+			if isSameDirectory(charts[i].Directory, repositoryPath) {
+				return &charts[i]
+			}
 		}
 
 		return nil
-	})
+	}
+
+	var match *LocalChart
+	for i, c := range charts {
+		if c.Name != dependency.Name || isSameDirectory(c.Directory, chartDirectory) {
+			continue
+		}
+
+		if !isDependencyVersionSatisfied(dependency.Version, c.Version) {
+			continue
+		}
+
+		if match != nil {
+			log.Debugf("Found several charts named %s in the chart search roots, not linking the dependency of chart %s on it", dependency.Name, chartDirectory)
+			return nil
+		}
+
+		match = &charts[i]
+	}
+
+	return match
+}
+
+// isDependencyVersionSatisfied returns whether a chart version satisfies the version constraint of a dependency, which
+// any version does if there's no constraint
+func isDependencyVersionSatisfied(constraint string, version string) bool {
+	if constraint == "" {
+		return true
+	}
+
+	c, err := semver.NewConstraint(constraint)
+	if err != nil {
+		return false
+	}
+
+	v, err := semver.NewVersion(version)
+	if err != nil {
+		return false
+	}
 
-	return chartDirs, err
+	return c.Check(v)
 }
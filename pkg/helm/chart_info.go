@@ -1,25 +1,26 @@
 package helm
 
 import (
-	"bufio"
+	"context"
+	"errors"
 	"fmt"
-	"io/ioutil"
 	"os"
-	"path"
-	"regexp"
 	"sort"
+	"strings"
 
+	"github.com/norwoodj/helm-docs/pkg/settings"
 	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
 	"gopkg.in/yaml.v2"
 )
 
-var valuesDescriptionRegex = regexp.MustCompile("^\\s*# (.*) -- (.*)$")
-var commentContinuationRegex = regexp.MustCompile("^\\s*# (.*)$")
-var defaultValueRegex = regexp.MustCompile("^\\s*# @default -- (.*)$")
-
 type ChartMetaMaintainer struct {
 	Email string
 	Name  string
+	Url   string
+
+	// The maintainer's GitHub account, found when maintainers are linked to their GitHub profiles
+	GitHubUsername string `yaml:"-"`
 }
 
 type ChartMeta struct {
@@ -27,17 +28,38 @@ type ChartMeta struct {
 	Name        string
 	Description string
 	Version     string
+	AppVersion  string `yaml:"appVersion"`
+	KubeVersion string `yaml:"kubeVersion"`
 	Home        string
+	Icon        string
 	Type        string
 	Sources     []string
 	Engine      string
 	Maintainers []ChartMetaMaintainer
+	Keywords    []string
+	Annotations map[string]string
 }
 
 type ChartRequirementsItem struct {
 	Name       string
 	Version    string
 	Repository string
+	Alias      string
+	Condition  string
+	Tags       []string
+
+	// Each item is either the name of a value exported by the dependency, or a map with child and parent keys
+	ImportValues []interface{} `yaml:"import-values"`
+
+	// Read from the dependency's own Chart.yaml, for dependencies published to OCI registries when they're fetched
+	Description string `yaml:"-"`
+	AppVersion  string `yaml:"-"`
+
+	// The chart found in the chart search roots that the dependency is on, if any, when dependencies are linked
+	LocalChart *LocalChart `yaml:"-"`
+
+	// Relative link from the documentation being rendered to that of the local chart, set when rendering documentation
+	DocumentationLink string `yaml:"-"`
 }
 
 type ChartRequirements struct {
@@ -47,42 +69,99 @@ type ChartRequirements struct {
 type ChartValueDescription struct {
 	Description string
 	Default     string
+	Required    bool
+	Allowed     []string
+	Sensitive   bool
+	Type        string
+	Ignored     bool
+	Collapse    bool
+
+	// The section of the values table the value is documented in, values without one being documented after those
+	// with one
+	Section string
+
+	// Whether the value was documented by a commented out key, e.g. "# storageClass: fast", rather than being set in
+	// the values file
+	CommentedOut bool
+}
+
+type ChartValuesFile struct {
+	Values       map[interface{}]interface{}
+	Descriptions map[string]ChartValueDescription
+	LineNumbers  map[string]int
 }
 
 type ChartDocumentationInfo struct {
 	ChartMeta
 	ChartRequirements
+	ChartGitMetadata
 
 	ChartDirectory          string
 	ChartValues             map[interface{}]interface{}
 	ChartValuesDescriptions map[string]ChartValueDescription
+	ChartValuesLineNumbers  map[string]int
+	ChartExtraValues        map[string]ChartValuesFile
+	ChartDependencyValues   []ChartDependencyValues
+	ChartSubcharts          []ChartSubchart
+	ChartLicense            ChartLicense
+	ChartNotes              string
+	ChartCRDs               []ChartCRD
+	ChartTests              []ChartTest
+	ChartNamedTemplates     []ChartNamedTemplate
+	ChartSchemaValidation   ChartSchemaValidation
+	ChartLint               ChartLintResult
+	ChartChangelog          []ChartChangelogRelease
+	ChartValuesDiff         ChartValuesDiff
+	ChartResources          []ChartResource
+	ChartAPIDeprecations    []ChartAPIDeprecation
+	ChartImages             []ChartImage
+
+	settings *viper.Viper
 }
 
-func getYamlFileContents(filename string) ([]byte, error) {
-	if _, err := os.Stat(filename); os.IsNotExist(err) {
-		return nil, err
+// Settings returns the settings the chart was parsed with, from which its documentation is also generated, those of the
+// helm-docs command unless it was parsed with a context carrying others
+func (c ChartDocumentationInfo) Settings() *viper.Viper {
+	if c.settings == nil {
+		return viper.GetViper()
 	}
 
-	yamlFileContents, err := ioutil.ReadFile(filename)
+	return c.settings
+}
+
+// WithSettings returns a copy of the chart documentation information read with other settings, e.g. those of the parent
+// chart it was found through
+func (c ChartDocumentationInfo) WithSettings(v *viper.Viper) ChartDocumentationInfo {
+	c.settings = v
+	return c
+}
+
+func getYamlFileContents(files chartFiles, name string) ([]byte, error) {
+	yamlFileContents, err := files.readFile(name)
+	if os.IsNotExist(err) {
+		return nil, err
+	}
 
 	if err != nil {
-		panic(err)
+		return nil, fmt.Errorf("failed to read %s: %w", files.path(name), err)
 	}
 
-	return []byte(yamlFileContents), nil
+	return yamlFileContents, nil
 }
 
-func yamlLoadAndCheck(yamlFileContents []byte, out interface{}) {
-	err := yaml.Unmarshal(yamlFileContents, out)
-
-	if err != nil {
-		panic(err)
+// yamlLoadAndCheck parses the contents of a yaml file of a chart, returning an error naming the file if they can't be
+// parsed, so that the chart is skipped rather than failing the documentation of every other chart
+func yamlLoadAndCheck(filename string, yamlFileContents []byte, out interface{}) error {
+	if err := yaml.Unmarshal(yamlFileContents, out); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", filename, err)
 	}
+
+	return nil
 }
 
 func isErrorInReadingNecessaryFile(filePath string, loadError error) bool {
 	if loadError != nil {
-		if os.IsNotExist(loadError) {
+		if errors.Is(loadError, os.ErrNotExist) {
 			log.Printf("Required chart file %s missing. Skipping documentation for chart", filePath)
 			return true
 		} else {
@@ -94,153 +173,696 @@ func isErrorInReadingNecessaryFile(filePath string, loadError error) bool {
 	return false
 }
 
+// parseChartFile parses the Chart.yaml of a chart directory or packaged chart
 func parseChartFile(chartDirectory string) (ChartMeta, error) {
-	chartYamlPath := path.Join(chartDirectory, "Chart.yaml")
+	files, err := openChartFiles(chartDirectory)
+	if err != nil {
+		return ChartMeta{}, err
+	}
+
+	return parseChartMetaFile(files)
+}
+
+func parseChartMetaFile(files chartFiles) (ChartMeta, error) {
+	chartYamlPath := files.path("Chart.yaml")
 	chartMeta := ChartMeta{}
-	yamlFileContents, err := getYamlFileContents(chartYamlPath)
+	yamlFileContents, err := getYamlFileContents(files, "Chart.yaml")
 
 	if isErrorInReadingNecessaryFile(chartYamlPath, err) {
 		return chartMeta, err
 	}
 
-	yamlLoadAndCheck(yamlFileContents, &chartMeta)
-	return chartMeta, nil
+	err = yamlLoadAndCheck(chartYamlPath, yamlFileContents, &chartMeta)
+	return chartMeta, err
+}
+
+// The orders in which the dependencies and values tables can be sorted
+const (
+	AlphaNumSortOrder = "alphanum"
+	FileSortOrder     = "file"
+)
+
+// The Chart.yaml annotation overriding the dependencies sort order for a single chart
+const sortDependenciesOrderAnnotation = "helm-docs.io/sort-dependencies-order"
+
+func getSortDependenciesOrder(chartSettings *viper.Viper, chartMeta ChartMeta) string {
+	if sortOrder, ok := chartMeta.Annotations[sortDependenciesOrderAnnotation]; ok {
+		return sortOrder
+	}
+
+	return chartSettings.GetString("sort-dependencies-order")
 }
 
 func requirementKey(requirement ChartRequirementsItem) string {
 	return fmt.Sprintf("%s/%s", requirement.Repository, requirement.Name)
 }
 
-func parseChartRequirementsFile(chartDirectory string, apiVersion string) (ChartRequirements, error) {
-	var requirementsPath string
+func sortChartRequirements(dependencies []ChartRequirementsItem, sortOrder string) error {
+	switch sortOrder {
+	case "", AlphaNumSortOrder:
+		sort.SliceStable(dependencies, func(i, j int) bool {
+			return requirementKey(dependencies[i]) < requirementKey(dependencies[j])
+		})
+	case FileSortOrder:
+		// Dependencies are parsed in the order they're listed in, so there's nothing to do
+	default:
+		return fmt.Errorf("invalid dependencies sort order %s, must be one of %s or %s", sortOrder, AlphaNumSortOrder, FileSortOrder)
+	}
 
-	if apiVersion == "v1" {
-		requirementsPath = path.Join(chartDirectory, "requirements.yaml")
+	return nil
+}
 
-		if _, err := os.Stat(requirementsPath); os.IsNotExist(err) {
+func parseChartRequirementsFile(chartSettings *viper.Viper, files chartFiles, chartMeta ChartMeta) (ChartRequirements, error) {
+	requirementsFile := "Chart.yaml"
+	if chartMeta.ApiVersion == "v1" {
+		requirementsFile = "requirements.yaml"
+
+		if !hasChartFile(files, requirementsFile) {
 			return ChartRequirements{Dependencies: []ChartRequirementsItem{}}, nil
 		}
-	} else {
-		requirementsPath = path.Join(chartDirectory, "Chart.yaml")
 	}
 
+	requirementsPath := files.path(requirementsFile)
 	chartRequirements := ChartRequirements{}
-	yamlFileContents, err := getYamlFileContents(requirementsPath)
+	yamlFileContents, err := getYamlFileContents(files, requirementsFile)
 
 	if isErrorInReadingNecessaryFile(requirementsPath, err) {
 		return chartRequirements, err
 	}
 
-	yamlLoadAndCheck(yamlFileContents, &chartRequirements)
-
-	sort.Slice(chartRequirements.Dependencies[:], func(i, j int) bool {
-		return requirementKey(chartRequirements.Dependencies[i]) < requirementKey(chartRequirements.Dependencies[j])
-	})
+	if err := yamlLoadAndCheck(requirementsPath, yamlFileContents, &chartRequirements); err != nil {
+		return chartRequirements, err
+	}
 
-	return chartRequirements, nil
+	err = sortChartRequirements(chartRequirements.Dependencies, getSortDependenciesOrder(chartSettings, chartMeta))
+	return chartRequirements, err
 }
 
-func parseChartValuesFile(chartDirectory string) (map[interface{}]interface{}, error) {
-	valuesPath := path.Join(chartDirectory, "values.yaml")
+func parseChartValuesFile(files chartFiles, valuesFilename string) (map[interface{}]interface{}, error) {
+	valuesPath := files.path(valuesFilename)
 	values := make(map[interface{}]interface{})
-	yamlFileContents, err := getYamlFileContents(valuesPath)
+	yamlFileContents, err := getYamlFileContents(files, valuesFilename)
 
 	if isErrorInReadingNecessaryFile(valuesPath, err) {
 		return values, err
 	}
 
-	yamlLoadAndCheck(yamlFileContents, &values)
+	values, err = parseValues(yamlFileContents)
+	if err != nil {
+		return values, fmt.Errorf("failed to parse %s: %w", valuesPath, err)
+	}
+
 	return values, nil
 }
 
-func parseChartValuesFileComments(chartDirectory string) (map[string]ChartValueDescription, error) {
-	valuesPath := path.Join(chartDirectory, "values.yaml")
-	valuesFile, err := os.Open(valuesPath)
+func parseAllowedValues(allowedValues string) []string {
+	allowed := make([]string, 0)
+
+	for _, v := range strings.Split(allowedValues, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			allowed = append(allowed, v)
+		}
+	}
+
+	return allowed
+}
+
+func isCommentedOutKey(commentRegexes valuesCommentRegexes, line string, key string) bool {
+	match := commentRegexes.continuation.FindStringSubmatch(line)
+	if len(match) < 2 {
+		return false
+	}
+
+	keyMatch := yamlMapKeyRegex.FindStringSubmatch(strings.TrimSpace(match[1]))
+	return keyMatch != nil && unquoteYamlKey(keyMatch[1]) == lastKeyPathElement(key)
+}
+
+func parseChartValuesFileComments(chartSettings *viper.Viper, files chartFiles, valuesFilename string) (map[string]ChartValueDescription, error) {
+	valuesPath := files.path(valuesFilename)
+	valuesFileContents, err := files.readFile(valuesFilename)
 
 	if isErrorInReadingNecessaryFile(valuesPath, err) {
 		return map[string]ChartValueDescription{}, err
 	}
 
-	defer valuesFile.Close()
+	valuesEntries, err := parseValuesEntries(valuesFileContents)
+	if err != nil {
+		return map[string]ChartValueDescription{}, fmt.Errorf("failed to parse %s: %w", valuesPath, err)
+	}
+
+	return parseValuesComments(chartSettings, valuesEntries)
+}
+
+// parseValuesComments parses the description comments of a values file from its comment blocks and the values between
+// them, in the order they appear in the file. A description comment runs until the end of its comment block, or the
+// first line in it that doesn't continue the description
+func parseValuesComments(chartSettings *viper.Viper, valuesEntries []valuesEntry) (map[string]ChartValueDescription, error) {
+	commentRegexes, err := newValuesCommentRegexes(chartSettings.GetString("comment-prefix"), chartSettings.GetString("description-separator"))
+	if err != nil {
+		return map[string]ChartValueDescription{}, err
+	}
 
-	var description, key string
+	var key string
+	var description ChartValueDescription
 	keyToDescriptions := make(map[string]ChartValueDescription)
-	scanner := bufio.NewScanner(valuesFile)
+	inlineDescriptions := make(map[string]ChartValueDescription)
+	ignoredKeys := make(map[string]bool)
+	ignoreNextKey := false
+	parseInlineComments := chartSettings.GetBool("inline-comments")
+	parseBitnamiParams := chartSettings.GetBool("bitnami-params")
+	parseCommentedOutValues := chartSettings.GetBool("commented-out-values")
 	foundValuesComment := false
+	foundDefaultValue := false
+	bitnamiSection := ""
 
-	for scanner.Scan() {
-		currentLine := scanner.Text()
+	// Completes the description comment being parsed, if there is one
+	endDescription := func() {
+		if foundValuesComment {
+			keyToDescriptions[key] = description
+		}
 
-		// If we've not yet found a values comment with a key name, try and find one on each line
-		if !foundValuesComment {
-			match := valuesDescriptionRegex.FindStringSubmatch(currentLine)
-			if len(match) < 3 {
-				continue
+		foundValuesComment = false
+		foundDefaultValue = false
+	}
+
+	for _, e := range valuesEntries {
+		if e.isKey {
+			// A standalone @ignore comment applies to the next key defined in the file
+			if ignoreNextKey {
+				ignoredKeys[e.keyPath] = true
+				ignoreNextKey = false
+			}
+
+			// Comments on the same line as a value are collected separately, as they're only used for keys that don't
+			// have a description comment
+			if parseInlineComments && e.inlineComment != "" {
+				inlineDescriptions[e.keyPath] = ChartValueDescription{Description: e.inlineComment}
 			}
 
-			foundValuesComment = true
-			key = match[1]
-			description = match[2]
+			endDescription()
 			continue
 		}
 
-		// If we've already found a values comment, on the next line try and parse a custom default value. If we find one
-		// that completes parsing for this key, add it to the list and reset to searching for a new key
-		match := defaultValueRegex.FindStringSubmatch(currentLine)
+		for _, currentLine := range e.comment {
+			// Comment lines following a custom default value continue it, one line per line, so that multi-line defaults
+			// can be written. Anything else completes parsing for this key; new description or @ignore comments are then
+			// handled as usual below
+			if foundDefaultValue {
+				match := commentRegexes.continuation.FindStringSubmatch(currentLine)
+				if len(match) > 1 && !commentRegexes.description.MatchString(currentLine) && !commentRegexes.ignore.MatchString(currentLine) {
+					description.Default = description.Default + "\n" + match[1]
+					continue
+				}
+
+				endDescription()
+			}
+
+			// If we've not yet found a values comment with a key name, try and find one on each line
+			if !foundValuesComment {
+				if commentRegexes.ignore.MatchString(currentLine) {
+					ignoreNextKey = true
+					continue
+				}
+
+				// Bitnami style "## @param key [modifiers] description" comments document a key on a single line, in the
+				// section of the last "## @section name" comment. The modifiers only affect how bitnami's own tooling
+				// renders defaults, so they're dropped
+				if parseBitnamiParams {
+					if match := commentRegexes.bitnamiSection.FindStringSubmatch(currentLine); len(match) > 1 {
+						bitnamiSection = strings.TrimSpace(match[1])
+						continue
+					}
+
+					if match := commentRegexes.bitnamiParam.FindStringSubmatch(currentLine); len(match) > 3 {
+						keyToDescriptions[match[1]] = ChartValueDescription{Description: strings.TrimSpace(match[3]), Section: bitnamiSection}
+						continue
+					}
+				}
+
+				match := commentRegexes.description.FindStringSubmatch(currentLine)
+				if len(match) < 3 {
+					continue
+				}
+
+				foundValuesComment = true
+				key = match[1]
+				description = ChartValueDescription{Description: match[2]}
+				continue
+			}
+
+			// Annotations flagging the value as required, sensitive or ignored, collapsing its default, restricting its
+			// allowed values, overriding its type, or placing it in a section may follow the description comment
+			if commentRegexes.ignore.MatchString(currentLine) {
+				description.Ignored = true
+				continue
+			}
+
+			if commentRegexes.collapse.MatchString(currentLine) {
+				description.Collapse = true
+				continue
+			}
+
+			if commentRegexes.required.MatchString(currentLine) {
+				description.Required = true
+				continue
+			}
 
-		if len(match) > 1 {
-			keyToDescriptions[key] = ChartValueDescription{
-				Description: description,
-				Default:     match[1],
+			if commentRegexes.sensitive.MatchString(currentLine) {
+				description.Sensitive = true
+				continue
 			}
 
-			foundValuesComment = false
+			match := commentRegexes.allowedValues.FindStringSubmatch(currentLine)
+			if len(match) > 1 {
+				description.Allowed = parseAllowedValues(match[1])
+				continue
+			}
+
+			match = commentRegexes.typeOverride.FindStringSubmatch(currentLine)
+			if len(match) > 1 {
+				description.Type = strings.TrimSpace(match[1])
+				continue
+			}
+
+			match = commentRegexes.section.FindStringSubmatch(currentLine)
+			if len(match) > 1 {
+				description.Section = strings.TrimSpace(match[1])
+				continue
+			}
+
+			// A commented out key following the description comment documents an optional value that is unset by
+			// default
+			if parseCommentedOutValues && isCommentedOutKey(commentRegexes, currentLine, key) {
+				description.CommentedOut = true
+				endDescription()
+				continue
+			}
+
+			// If we've already found a values comment, on the next line try and parse a custom default value, which may
+			// be continued on the following lines
+			match = commentRegexes.defaultValue.FindStringSubmatch(currentLine)
+			if len(match) > 1 {
+				description.Default = match[1]
+				foundDefaultValue = true
+				continue
+			}
+
+			// Otherwise, see if there's a comment continuing the description from the previous line
+			match = commentRegexes.continuation.FindStringSubmatch(currentLine)
+			if len(match) > 1 {
+				description.Description = description.Description + " " + match[1]
+				continue
+			}
+
+			// If we haven't continued by this point, we didn't match any of the comment formats we want, so we need to
+			// add the in progress value to the map, and reset to looking for a new key
+			endDescription()
+		}
+
+		endDescription()
+	}
+
+	for k, d := range inlineDescriptions {
+		if _, ok := keyToDescriptions[k]; !ok {
+			keyToDescriptions[k] = d
+		}
+	}
+
+	for k := range ignoredKeys {
+		d := keyToDescriptions[k]
+		d.Ignored = true
+		keyToDescriptions[k] = d
+	}
+
+	return keyToDescriptions, nil
+}
+
+// parseChartValuesFileLineNumbers finds the line on which each key in a values file is defined, for documenting values
+// in the order they appear in the file
+func parseChartValuesFileLineNumbers(files chartFiles, valuesFilename string) (map[string]int, error) {
+	valuesPath := files.path(valuesFilename)
+	valuesFileContents, err := files.readFile(valuesFilename)
+
+	if isErrorInReadingNecessaryFile(valuesPath, err) {
+		return map[string]int{}, err
+	}
+
+	valuesEntries, err := parseValuesEntries(valuesFileContents)
+	if err != nil {
+		return map[string]int{}, fmt.Errorf("failed to parse %s: %w", valuesPath, err)
+	}
+
+	return parseValuesLineNumbers(valuesEntries), nil
+}
+
+func parseValuesLineNumbers(valuesEntries []valuesEntry) map[string]int {
+	keyLineNumbers := make(map[string]int)
+
+	for _, l := range valuesEntries {
+		if !l.isKey {
 			continue
 		}
 
-		// Otherwise, see if there's a comment continuing the description from the previous line
-		match = commentContinuationRegex.FindStringSubmatch(currentLine)
-		if len(match) > 1 {
-			description = description + " " + match[1]
+		if _, ok := keyLineNumbers[l.keyPath]; !ok {
+			keyLineNumbers[l.keyPath] = l.lineNumber
+		}
+	}
+
+	return keyLineNumbers
+}
+
+// parseValuesFileContents parses the values, description comments and key line numbers of a values file that has
+// already been read, e.g. from a packaged chart
+func parseValuesFileContents(chartSettings *viper.Viper, valuesFileContents []byte) (ChartValuesFile, error) {
+	document, err := parseValuesNode(valuesFileContents)
+	if err != nil {
+		return ChartValuesFile{}, err
+	}
+
+	values, err := valuesFromNode(document)
+	if err != nil {
+		return ChartValuesFile{}, err
+	}
+
+	valuesEntries := valuesEntriesFromNode(document)
+	descriptions, err := parseValuesComments(chartSettings, valuesEntries)
+	if err != nil {
+		return ChartValuesFile{}, err
+	}
+
+	return ChartValuesFile{
+		Values:       values,
+		Descriptions: descriptions,
+		LineNumbers:  parseValuesLineNumbers(valuesEntries),
+	}, nil
+}
+
+// parseChartValuesMetadataFile parses descriptions of values from a file alongside the values file, for charts whose
+// values files are generated or vendored and so can't be commented
+func parseChartValuesMetadataFile(chartSettings *viper.Viper, files chartFiles) (map[string]ChartValueDescription, error) {
+	metadataFile := chartSettings.GetString("values-metadata-file")
+	metadataPath := files.path(metadataFile)
+	descriptions := make(map[string]ChartValueDescription)
+
+	yamlFileContents, err := files.readFile(metadataFile)
+	if os.IsNotExist(err) {
+		return descriptions, nil
+	}
+
+	if err != nil {
+		return descriptions, err
+	}
+
+	if err := yaml.Unmarshal(yamlFileContents, &descriptions); err != nil {
+		return descriptions, fmt.Errorf("failed to parse values metadata file %s: %s", metadataPath, err)
+	}
+
+	return descriptions, nil
+}
+
+// mergeValueDescriptions fills in any fields of a description parsed from comments that are not set from a description
+// given elsewhere
+func mergeValueDescriptions(description ChartValueDescription, fallback ChartValueDescription) ChartValueDescription {
+	if description.Description == "" {
+		description.Description = fallback.Description
+	}
+
+	if description.Default == "" {
+		description.Default = fallback.Default
+	}
+
+	if description.Type == "" {
+		description.Type = fallback.Type
+	}
+
+	if len(description.Allowed) == 0 {
+		description.Allowed = fallback.Allowed
+	}
+
+	if description.Section == "" {
+		description.Section = fallback.Section
+	}
+
+	description.Required = description.Required || fallback.Required
+	description.Sensitive = description.Sensitive || fallback.Sensitive
+	description.Ignored = description.Ignored || fallback.Ignored
+	description.Collapse = description.Collapse || fallback.Collapse
+
+	return description
+}
+
+func parseChartExtraValuesFiles(chartSettings *viper.Viper, files chartFiles) (map[string]ChartValuesFile, error) {
+	extraValues := make(map[string]ChartValuesFile)
+
+	for _, valuesFilename := range chartSettings.GetStringSlice("extra-values-files") {
+		if !hasChartFile(files, valuesFilename) {
+			log.Debugf("Extra values file %s not found, skipping it", files.path(valuesFilename))
 			continue
 		}
 
-		// If we haven't continued by this point, we didn't match any of the comment formats we want, so we need to add
-		// the in progress value to the map, and reset to looking for a new key
-		keyToDescriptions[key] = ChartValueDescription{
-			Description: description,
+		values, err := parseChartValuesFile(files, valuesFilename)
+		if err != nil {
+			return extraValues, err
 		}
 
-		foundValuesComment = false
+		descriptions, err := parseChartValuesFileComments(chartSettings, files, valuesFilename)
+		if err != nil {
+			return extraValues, err
+		}
+
+		lineNumbers, err := parseChartValuesFileLineNumbers(files, valuesFilename)
+		if err != nil {
+			return extraValues, err
+		}
+
+		extraValues[valuesFilename] = ChartValuesFile{Values: values, Descriptions: descriptions, LineNumbers: lineNumbers}
 	}
 
-	return keyToDescriptions, nil
+	return extraValues, nil
+}
+
+// parseChartValuesFiles parses the values of a chart, along with their descriptions from the comments in values.yaml and
+// the values metadata file, and the lines they're defined on
+func parseChartValuesFiles(files chartFiles, chartDocInfo *ChartDocumentationInfo) error {
+	var err error
+
+	chartDocInfo.ChartValues, err = parseChartValuesFile(files, "values.yaml")
+	if err != nil {
+		return err
+	}
+
+	chartDocInfo.ChartValuesDescriptions, err = parseChartValuesFileComments(chartDocInfo.Settings(), files, "values.yaml")
+	if err != nil {
+		return err
+	}
+
+	valuesMetadata, err := parseChartValuesMetadataFile(chartDocInfo.Settings(), files)
+	if err != nil {
+		return err
+	}
+
+	for k, d := range valuesMetadata {
+		chartDocInfo.ChartValuesDescriptions[k] = mergeValueDescriptions(chartDocInfo.ChartValuesDescriptions[k], d)
+	}
+
+	chartDocInfo.ChartValuesLineNumbers, err = parseChartValuesFileLineNumbers(files, "values.yaml")
+	return err
 }
 
 func ParseChartInformation(chartDirectory string) (ChartDocumentationInfo, error) {
+	return ParseChartInformationContext(context.Background(), chartDirectory)
+}
+
+// ParseChartInformationContext parses a chart, giving up once the context is done, e.g. on reaching a timeout. The
+// commands run for the chart, e.g. helm template or git log, are killed, and the context is checked between the stages
+// of parsing, so that nothing is left running once it's given up on
+func ParseChartInformationContext(ctx context.Context, chartDirectory string) (ChartDocumentationInfo, error) {
+	if err := ctx.Err(); err != nil {
+		return ChartDocumentationInfo{ChartDirectory: chartDirectory}, err
+	}
+
+	chartDocumentationInfo, err := parseChartInformation(ctx, chartDirectory)
+	if ctx.Err() != nil {
+		return ChartDocumentationInfo{ChartDirectory: chartDirectory}, fmt.Errorf("gave up parsing chart %s: %w", chartDirectory, ctx.Err())
+	}
+
+	return chartDocumentationInfo, err
+}
+
+func parseChartInformation(ctx context.Context, chartDirectory string) (ChartDocumentationInfo, error) {
 	var chartDocInfo ChartDocumentationInfo
 	var err error
 
+	chartSettings := settings.FromContext(ctx)
+	chartDocInfo.settings = chartSettings
 	chartDocInfo.ChartDirectory = chartDirectory
-	chartDocInfo.ChartMeta, err = parseChartFile(chartDirectory)
+	files, err := openChartFiles(chartDirectory)
 	if err != nil {
 		return chartDocInfo, err
 	}
 
-	chartDocInfo.ChartRequirements, err = parseChartRequirementsFile(chartDirectory, chartDocInfo.ApiVersion)
+	chartDocInfo.ChartMeta, err = parseChartMetaFile(files)
 	if err != nil {
 		return chartDocInfo, err
 	}
 
-	chartDocInfo.ChartValues, err = parseChartValuesFile(chartDirectory)
+	chartDocInfo.ChartRequirements, err = parseChartRequirementsFile(chartSettings, files, chartDocInfo.ChartMeta)
 	if err != nil {
 		return chartDocInfo, err
 	}
 
-	chartDocInfo.ChartValuesDescriptions, err = parseChartValuesFileComments(chartDirectory)
+	if err := ctx.Err(); err != nil {
+		return chartDocInfo, err
+	}
+
+	if chartSettings.GetBool("fetch-oci-dependencies") {
+		describeOCIDependencies(ctx, chartDirectory, chartDocInfo.Dependencies)
+	}
+
+	if chartSettings.GetBool("github-maintainers") {
+		describeGitHubMaintainers(ctx, chartDirectory, chartDocInfo.Maintainers)
+	}
+
+	if chartSettings.GetBool("link-dependencies") {
+		localCharts := findLocalCharts(chartSettings)
+		for i := range chartDocInfo.Dependencies {
+			chartDocInfo.Dependencies[i].LocalChart = findLocalDependencyChart(chartDirectory, chartDocInfo.Dependencies[i], localCharts)
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return chartDocInfo, err
+	}
+
+	// Library charts can't be installed themselves, so often don't have a values file
+	if !hasChartFile(files, "values.yaml") && chartDocInfo.IsLibraryChart() {
+		chartDocInfo.ChartValues = make(map[interface{}]interface{})
+		chartDocInfo.ChartValuesDescriptions = make(map[string]ChartValueDescription)
+		chartDocInfo.ChartValuesLineNumbers = make(map[string]int)
+	} else if err := parseChartValuesFiles(files, &chartDocInfo); err != nil {
+		return chartDocInfo, err
+	}
+
+	chartDocInfo.ChartSchemaValidation, err = parseChartSchemaValidation(files, chartDocInfo.ChartValues)
 	if err != nil {
 		return chartDocInfo, err
 	}
 
-	return chartDocInfo, nil
+	if err := ctx.Err(); err != nil {
+		return chartDocInfo, err
+	}
+
+	// Packaged charts aren't in a git repository, so can only be compared with another packaged chart
+	if previous := chartSettings.GetString("compare-values-to"); previous != "" && (!IsChartArchive(chartDirectory) || IsChartArchive(previous)) {
+		chartDocInfo.ChartValuesDiff, err = parseChartValuesDiff(ctx, chartDirectory, previous, chartDocInfo.ChartValues, chartDocInfo.ChartValuesDescriptions)
+		if err != nil {
+			return chartDocInfo, err
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return chartDocInfo, err
+	}
+
+	chartDocInfo.ChartExtraValues, err = parseChartExtraValuesFiles(chartSettings, files)
+	if err != nil {
+		return chartDocInfo, err
+	}
+
+	chartDocInfo.ChartLicense, err = parseChartLicenseFile(files)
+	if err != nil {
+		return chartDocInfo, err
+	}
+
+	chartDocInfo.ChartNotes, err = parseChartNotesFile(chartSettings, files)
+	if err != nil {
+		return chartDocInfo, err
+	}
+
+	chartDocInfo.ChartCRDs, err = parseChartCRDs(files)
+	if err != nil {
+		return chartDocInfo, err
+	}
+
+	chartDocInfo.ChartTests, err = parseChartTests(files)
+	if err != nil {
+		return chartDocInfo, err
+	}
+
+	chartDocInfo.ChartNamedTemplates, err = parseChartNamedTemplates(files)
+	if err != nil {
+		return chartDocInfo, err
+	}
+
+	if err := ctx.Err(); err != nil {
+		return chartDocInfo, err
+	}
+
+	if chartSettings.GetBool("render-resources") {
+		manifests, err := renderChartManifests(ctx, chartDirectory)
+		if err != nil {
+			return chartDocInfo, err
+		}
+
+		chartDocInfo.ChartResources, err = parseChartResources(manifests)
+		if err != nil {
+			return chartDocInfo, err
+		}
+
+		chartDocInfo.ChartAPIDeprecations = findAPIDeprecations(chartDocInfo.ChartResources)
+
+		chartDocInfo.ChartImages, err = parseManifestImages(manifests)
+		if err != nil {
+			return chartDocInfo, err
+		}
+	} else {
+		chartDocInfo.ChartImages, err = parseValuesImages(chartSettings, files)
+		if err != nil {
+			return chartDocInfo, err
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return chartDocInfo, err
+	}
+
+	if chartSettings.GetBool("helm-lint") {
+		chartDocInfo.ChartLint, err = lintChart(ctx, chartDirectory)
+		if err != nil {
+			return chartDocInfo, err
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return chartDocInfo, err
+	}
+
+	if chartSettings.GetBool("git-changelog") {
+		chartDocInfo.ChartChangelog = parseChartChangelog(ctx, chartDirectory)
+	}
+
+	if chartSettings.GetBool("git-metadata") {
+		chartDocInfo.ChartGitMetadata = parseChartGitMetadata(ctx, chartDirectory)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return chartDocInfo, err
+	}
+
+	if chartSettings.GetBool("document-dependency-values") {
+		chartDocInfo.ChartDependencyValues, err = parseChartDependencyValues(chartSettings, chartDirectory, files, chartDocInfo.Dependencies)
+		if err != nil {
+			return chartDocInfo, err
+		}
+	}
+
+	if chartSettings.GetString("document-subcharts") != "" {
+		chartDocInfo.ChartSubcharts, err = parseChartSubcharts(chartSettings, files)
+		if err != nil {
+			return chartDocInfo, err
+		}
+	}
+
+	err = applyArtifactHubAnnotations(&chartDocInfo)
+	return chartDocInfo, err
 }
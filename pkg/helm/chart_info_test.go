@@ -0,0 +1,567 @@
+package helm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func parseValuesFileComments(t *testing.T, valuesFileContents string) map[string]ChartValueDescription {
+	chartDirectory, err := ioutil.TempDir("", "helm-docs-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(chartDirectory)
+
+	valuesPath := path.Join(chartDirectory, "values.yaml")
+	if err := ioutil.WriteFile(valuesPath, []byte(strings.TrimSpace(valuesFileContents)+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	descriptions, err := parseChartValuesFileComments(viper.GetViper(), directoryChartFiles(chartDirectory), "values.yaml")
+	assert.Nil(t, err)
+
+	return descriptions
+}
+
+func TestParseDescriptionComments(t *testing.T) {
+	descriptions := parseValuesFileComments(t, `
+controller:
+  # controller.replicas -- Number of pods to load balance between.
+  # Do not set this below 2.
+  replicas: 2
+
+  # controller.podLabels -- The labels to be applied to the controller pod
+  # @default -- the chart's selector labels
+  podLabels: {}
+	`)
+
+	assert.Len(t, descriptions, 2)
+	assert.Equal(t, ChartValueDescription{Description: "Number of pods to load balance between. Do not set this below 2."}, descriptions["controller.replicas"])
+	assert.Equal(t, ChartValueDescription{Description: "The labels to be applied to the controller pod", Default: "the chart's selector labels"}, descriptions["controller.podLabels"])
+}
+
+func TestParseAnnotationComments(t *testing.T) {
+	descriptions := parseValuesFileComments(t, `
+# adminPassword -- (string) Password for the admin user
+# @required
+adminPassword:
+
+# logLevel -- The log level of the application
+# @allowed -- [debug, info, warn, error]
+logLevel: info
+
+# apiToken -- Token used to authenticate against the API
+# @sensitive
+apiToken: abc123
+
+# timeout -- How long to wait for the API to respond
+# @type -- duration
+# @section -- API
+timeout: 30s
+	`)
+
+	assert.Len(t, descriptions, 4)
+	assert.True(t, descriptions["adminPassword"].Required)
+	assert.Equal(t, []string{"debug", "info", "warn", "error"}, descriptions["logLevel"].Allowed)
+	assert.Equal(t, "The log level of the application", descriptions["logLevel"].Description)
+	assert.True(t, descriptions["apiToken"].Sensitive)
+	assert.Equal(t, "duration", descriptions["timeout"].Type)
+	assert.Equal(t, "API", descriptions["timeout"].Section)
+	assert.Equal(t, "", descriptions["apiToken"].Section)
+}
+
+func TestParseInlineComments(t *testing.T) {
+	viper.Set("inline-comments", true)
+	defer viper.Set("inline-comments", false)
+
+	descriptions := parseValuesFileComments(t, `
+replicaCount: 2  # number of replicas
+image:  # the image to run
+  repository: nginx
+  tag: "1.17#latest"
+  pullPolicy: IfNotPresent # one of Always, IfNotPresent or Never
+
+ingress:
+  hosts:
+    - host: chart-example.local  # hostname to route to this chart
+      paths: []
+    - host: other.local
+      # ingress.hosts[1].port -- port of the second host
+      port: 80 # not this description
+
+annotations:
+  "prometheus.io/scrape": "true" # whether prometheus should scrape the pods
+	`)
+
+	assert.Len(t, descriptions, 6)
+	assert.Equal(t, "number of replicas", descriptions["replicaCount"].Description)
+	assert.Equal(t, "the image to run", descriptions["image"].Description)
+	assert.Equal(t, "one of Always, IfNotPresent or Never", descriptions["image.pullPolicy"].Description)
+	assert.Equal(t, "hostname to route to this chart", descriptions["ingress.hosts[0].host"].Description)
+	assert.Equal(t, "port of the second host", descriptions["ingress.hosts[1].port"].Description)
+	assert.Equal(t, "whether prometheus should scrape the pods", descriptions[`annotations."prometheus.io/scrape"`].Description)
+}
+
+func TestParseBitnamiParams(t *testing.T) {
+	viper.Set("bitnami-params", true)
+	defer viper.Set("bitnami-params", false)
+
+	descriptions := parseValuesFileComments(t, `
+## @section Common parameters
+## @param nameOverride String to partially override common.names.fullname
+## @param image.repository Image repository
+## @param image.pullSecrets [array] Specify docker-registry secret names as an array
+##
+nameOverride: ""
+image:
+  repository: bitnami/nginx
+  pullSecrets: []
+
+# replicaCount -- Number of replicas
+replicaCount: 1
+	`)
+
+	assert.Len(t, descriptions, 4)
+	assert.Equal(t, "String to partially override common.names.fullname", descriptions["nameOverride"].Description)
+	assert.Equal(t, "Image repository", descriptions["image.repository"].Description)
+	assert.Equal(t, "Specify docker-registry secret names as an array", descriptions["image.pullSecrets"].Description)
+	assert.Equal(t, "Number of replicas", descriptions["replicaCount"].Description)
+	assert.Equal(t, "Common parameters", descriptions["nameOverride"].Section)
+	assert.Equal(t, "Common parameters", descriptions["image.pullSecrets"].Section)
+	assert.Equal(t, "", descriptions["replicaCount"].Section)
+}
+
+func TestParseBitnamiParamsCommentPrefix(t *testing.T) {
+	viper.Set("bitnami-params", true)
+	viper.Set("comment-prefix", "##")
+	defer viper.Set("bitnami-params", false)
+	defer viper.Set("comment-prefix", "")
+
+	descriptions := parseValuesFileComments(t, `
+## @section Common parameters
+## @param nameOverride String to partially override common.names.fullname
+nameOverride: ""
+
+## replicaCount -- Number of replicas
+replicaCount: 1
+	`)
+
+	assert.Len(t, descriptions, 2)
+	assert.Equal(t, ChartValueDescription{Description: "String to partially override common.names.fullname", Section: "Common parameters"}, descriptions["nameOverride"])
+	assert.Equal(t, "Number of replicas", descriptions["replicaCount"].Description)
+}
+
+func TestParseCustomCommentFormat(t *testing.T) {
+	viper.Set("comment-prefix", "##|#:")
+	viper.Set("description-separator", "@desc")
+	defer viper.Set("comment-prefix", "")
+	defer viper.Set("description-separator", "")
+
+	descriptions := parseValuesFileComments(t, `
+image:
+  ## image.repository @desc The image repository
+  repository: nginx
+
+  #: image.tag @desc The image tag,
+  #: defaulting to the app version
+  #: @default @desc the chart's appVersion
+  tag: ""
+
+# replicaCount -- Not a description in this format
+replicaCount: 1
+	`)
+
+	assert.Len(t, descriptions, 2)
+	assert.Equal(t, ChartValueDescription{Description: "The image repository"}, descriptions["image.repository"])
+	assert.Equal(t, ChartValueDescription{Description: "The image tag, defaulting to the app version", Default: "the chart's appVersion"}, descriptions["image.tag"])
+}
+
+func TestInvalidCommentFormat(t *testing.T) {
+	_, err := newValuesCommentRegexes("(", "--")
+	assert.NotNil(t, err)
+}
+
+func TestParseIgnoreComments(t *testing.T) {
+	descriptions := parseValuesFileComments(t, `
+# @ignore
+internal:
+  featureFlag: true
+
+image:
+  # image.experimental -- An experimental image setting
+  # @ignore
+  experimental: false
+
+  # image.tag -- The image tag
+  tag: latest
+	`)
+
+	assert.Len(t, descriptions, 3)
+	assert.True(t, descriptions["internal"].Ignored)
+	assert.True(t, descriptions["image.experimental"].Ignored)
+	assert.False(t, descriptions["image.tag"].Ignored)
+}
+
+func TestParseListItemComments(t *testing.T) {
+	viper.Set("inline-comments", true)
+	defer viper.Set("inline-comments", false)
+
+	descriptions := parseValuesFileComments(t, `
+ingress:
+  hosts:
+    # ingress.hosts[0] -- The default host of the chart
+    - host: chart-example.local
+      # ingress.hosts[0].paths -- Paths routed to the chart on the default host
+      paths: []
+
+args:
+  - --verbose  # enable verbose logging
+  - --port=8080
+	`)
+
+	assert.Len(t, descriptions, 3)
+	assert.Equal(t, "The default host of the chart", descriptions["ingress.hosts[0]"].Description)
+	assert.Equal(t, "Paths routed to the chart on the default host", descriptions["ingress.hosts[0].paths"].Description)
+	assert.Equal(t, "enable verbose logging", descriptions["args[0]"].Description)
+}
+
+func TestParseEdgeCaseFormatting(t *testing.T) {
+	viper.Set("inline-comments", true)
+	defer viper.Set("inline-comments", false)
+
+	valuesFileContents := []byte(`
+defaults: &defaults
+  # defaults.timeout -- How long to wait for a response
+  timeout: 30s
+
+# config -- Configuration file of the application
+config: |
+  # not a description comment
+  listen: 8080
+
+resources: {limits: {cpu: 100m}, requests: {cpu: 50m}}  # resources of the pods
+ports: [80, 443]
+
+worker:
+  <<: *defaults
+  # worker.concurrency -- Number of jobs run at once
+  concurrency: 4
+backup: *defaults
+`)
+
+	valuesEntries, err := parseValuesEntries(valuesFileContents)
+	assert.Nil(t, err)
+
+	descriptions, err := parseValuesComments(viper.GetViper(), valuesEntries)
+	assert.Nil(t, err)
+	assert.Len(t, descriptions, 4)
+	assert.Equal(t, "How long to wait for a response", descriptions["defaults.timeout"].Description)
+	assert.Equal(t, "Configuration file of the application", descriptions["config"].Description)
+	assert.Equal(t, "resources of the pods", descriptions["resources"].Description)
+	assert.Equal(t, "Number of jobs run at once", descriptions["worker.concurrency"].Description)
+
+	lineNumbers := parseValuesLineNumbers(valuesEntries)
+	assert.Equal(t, 4, lineNumbers["defaults.timeout"])
+	assert.Equal(t, 7, lineNumbers["config"])
+	assert.Equal(t, 11, lineNumbers["resources.limits.cpu"])
+	assert.Equal(t, 12, lineNumbers["ports[1]"])
+	assert.Equal(t, 17, lineNumbers["worker.concurrency"])
+	assert.Equal(t, 18, lineNumbers["backup"])
+	assert.NotContains(t, lineNumbers, "config.listen")
+	assert.NotContains(t, lineNumbers, `worker."<<"`)
+	assert.NotContains(t, lineNumbers, "backup.timeout")
+}
+
+func TestParseValues(t *testing.T) {
+	values, err := parseValues([]byte(`
+defaults: &defaults
+  timeout: 30s
+  retries: 3
+worker:
+  retries: 5
+  <<: *defaults
+  enabled: yes
+  quoted: "yes"
+  released: 2020-01-01
+  ratio: 0.5
+1: one
+`))
+	assert.Nil(t, err)
+
+	worker := values["worker"].(map[interface{}]interface{})
+	assert.Equal(t, "30s", worker["timeout"])
+	assert.Equal(t, 5, worker["retries"])
+	assert.Equal(t, true, worker["enabled"])
+	assert.Equal(t, "yes", worker["quoted"])
+	assert.Equal(t, "2020-01-01", worker["released"])
+	assert.Equal(t, 0.5, worker["ratio"])
+	assert.Equal(t, "one", values[1])
+
+	values, err = parseValues([]byte(""))
+	assert.Nil(t, err)
+	assert.Empty(t, values)
+
+	_, err = parseValues([]byte("- a\n- b\n"))
+	assert.NotNil(t, err)
+}
+
+func TestMergeValueDescriptions(t *testing.T) {
+	merged := mergeValueDescriptions(
+		ChartValueDescription{Description: "from comments", Required: true},
+		ChartValueDescription{Description: "from metadata", Type: "duration", Sensitive: true, Section: "API"},
+	)
+
+	assert.Equal(t, ChartValueDescription{Description: "from comments", Type: "duration", Required: true, Sensitive: true, Section: "API"}, merged)
+}
+
+func TestParseValuesMetadataFile(t *testing.T) {
+	chartDirectory, err := ioutil.TempDir("", "helm-docs-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(chartDirectory)
+
+	assert.Nil(t, ioutil.WriteFile(path.Join(chartDirectory, "Chart.yaml"), []byte("apiVersion: v2\nname: vendored\nversion: 1.0.0\n"), 0644))
+	assert.Nil(t, ioutil.WriteFile(path.Join(chartDirectory, "values.yaml"), []byte(`# replicas -- Number of pods
+replicas: 1
+image:
+  tag: latest
+`), 0644))
+	assert.Nil(t, ioutil.WriteFile(path.Join(chartDirectory, "values.metadata.yaml"), []byte(`replicas:
+  description: Ignored, as values.yaml describes it
+  type: int
+image.tag:
+  description: The image tag
+  required: true
+  section: Image
+`), 0644))
+
+	viper.Set("values-metadata-file", "values.metadata.yaml")
+	defer viper.Set("values-metadata-file", nil)
+
+	chartDocumentationInfo, err := ParseChartInformation(chartDirectory)
+	assert.Nil(t, err)
+	assert.Equal(t, ChartValueDescription{Description: "Number of pods", Type: "int"}, chartDocumentationInfo.ChartValuesDescriptions["replicas"])
+	assert.Equal(t, ChartValueDescription{Description: "The image tag", Required: true, Section: "Image"}, chartDocumentationInfo.ChartValuesDescriptions["image.tag"])
+
+	assert.Nil(t, ioutil.WriteFile(path.Join(chartDirectory, "values.metadata.yaml"), []byte("replicas: [unclosed\n"), 0644))
+	_, err = ParseChartInformation(chartDirectory)
+	assert.NotNil(t, err)
+}
+
+func TestParseSpecialCharacterKeys(t *testing.T) {
+	viper.Set("inline-comments", true)
+	defer viper.Set("inline-comments", false)
+
+	descriptions := parseValuesFileComments(t, `
+podAnnotations:
+  # podAnnotations."prometheus.io/scrape" -- Whether prometheus scrapes the pods -- defaults to yes
+  prometheus.io/scrape: "true"
+  "say \"hello\"": hi  # a key with quotes
+  'it''s': it is  # a key with a single quote
+  "volumes[0]": x  # not a list
+	`)
+
+	assert.Len(t, descriptions, 4)
+	assert.Equal(t, "Whether prometheus scrapes the pods -- defaults to yes", descriptions[`podAnnotations."prometheus.io/scrape"`].Description)
+	assert.Equal(t, "a key with quotes", descriptions[`podAnnotations."say \"hello\""`].Description)
+	assert.Equal(t, "a key with a single quote", descriptions[`podAnnotations.it's`].Description)
+	assert.Equal(t, "not a list", descriptions[`podAnnotations."volumes[0]"`].Description)
+}
+
+func TestFormatObjectKeyPath(t *testing.T) {
+	assert.Equal(t, "image", FormatObjectKeyPath("", "image"))
+	assert.Equal(t, "image.tag", FormatObjectKeyPath("image", "tag"))
+	assert.Equal(t, `annotations."prometheus.io/scrape"`, FormatObjectKeyPath("annotations", "prometheus.io/scrape"))
+	assert.Equal(t, `config."not real config param"`, FormatObjectKeyPath("config", "not real config param"))
+	assert.Equal(t, `labels."say \"hi\""`, FormatObjectKeyPath("labels", `say "hi"`))
+	assert.Equal(t, `labels."a[0]"`, FormatObjectKeyPath("labels", "a[0]"))
+}
+
+func TestParseCommentedOutValues(t *testing.T) {
+	viper.Set("commented-out-values", true)
+	defer viper.Set("commented-out-values", false)
+
+	descriptions := parseValuesFileComments(t, `
+persistence:
+  # persistence.storageClass -- (string) Storage class of the persistent volume claim
+  # storageClass: fast
+
+  # persistence.size -- Size of the persistent volume claim
+  # which is created for the pod
+  size: 8Gi
+
+  # persistence."existing.claim" -- Name of an existing claim to use
+  # existing.claim: my-claim
+	`)
+
+	assert.Len(t, descriptions, 3)
+	assert.Equal(t, ChartValueDescription{Description: "(string) Storage class of the persistent volume claim", CommentedOut: true}, descriptions["persistence.storageClass"])
+	assert.Equal(t, ChartValueDescription{Description: "Size of the persistent volume claim which is created for the pod"}, descriptions["persistence.size"])
+	assert.True(t, descriptions[`persistence."existing.claim"`].CommentedOut)
+}
+
+func TestParseMultiLineDefault(t *testing.T) {
+	descriptions := parseValuesFileComments(t, `
+# serverBlock -- Custom server block
+# @default -- a server block:
+#   server {
+#     listen 8080;
+#   }
+serverBlock: ""
+
+# port -- Port to listen on
+# @default -- 8080
+# replicas -- Number of replicas
+# @default -- one per node
+	`)
+
+	assert.Len(t, descriptions, 3)
+	assert.Equal(t, "a server block:\n  server {\n    listen 8080;\n  }", descriptions["serverBlock"].Default)
+	assert.Equal(t, "8080", descriptions["port"].Default)
+	assert.Equal(t, ChartValueDescription{Description: "Number of replicas", Default: "one per node"}, descriptions["replicas"])
+}
+
+func TestSortChartRequirements(t *testing.T) {
+	dependencies := []ChartRequirementsItem{
+		{Name: "postgresql", Repository: "https://charts.bitnami.com/bitnami"},
+		{Name: "common", Repository: "https://charts.bitnami.com/bitnami"},
+		{Name: "redis", Repository: "file://../redis"},
+	}
+
+	fileOrder := append([]ChartRequirementsItem(nil), dependencies...)
+	assert.Nil(t, sortChartRequirements(fileOrder, FileSortOrder))
+	assert.Equal(t, dependencies, fileOrder)
+
+	assert.Nil(t, sortChartRequirements(dependencies, AlphaNumSortOrder))
+	assert.Equal(t, []string{"redis", "common", "postgresql"}, []string{dependencies[0].Name, dependencies[1].Name, dependencies[2].Name})
+
+	assert.NotNil(t, sortChartRequirements(dependencies, "random"))
+}
+
+func TestFindLocalDependencyChart(t *testing.T) {
+	charts := []LocalChart{
+		{ChartMeta: ChartMeta{Name: "app", Version: "1.0.0"}, Directory: "charts/app"},
+		{ChartMeta: ChartMeta{Name: "common", Version: "1.2.3"}, Directory: "charts/common"},
+		{ChartMeta: ChartMeta{Name: "redis", Version: "2.0.0"}, Directory: "charts/redis"},
+		{ChartMeta: ChartMeta{Name: "redis", Version: "2.1.0"}, Directory: "legacy/redis"},
+	}
+
+	findDependency := func(dependency ChartRequirementsItem) string {
+		if localChart := findLocalDependencyChart("charts/app", dependency, charts); localChart != nil {
+			return localChart.Directory
+		}
+
+		return ""
+	}
+
+	assert.Equal(t, "charts/common", findDependency(ChartRequirementsItem{Name: "common", Version: "~1.2.0", Repository: "https://charts.example.com"}))
+	assert.Equal(t, "charts/common", findDependency(ChartRequirementsItem{Name: "library", Repository: "file://../common"}))
+	assert.Equal(t, "", findDependency(ChartRequirementsItem{Name: "common", Version: "2.0.0", Repository: "https://charts.example.com"}))
+	assert.Equal(t, "", findDependency(ChartRequirementsItem{Name: "redis", Version: ">=2.0.0", Repository: "https://charts.example.com"}))
+	assert.Equal(t, "legacy/redis", findDependency(ChartRequirementsItem{Name: "redis", Version: "2.1.0", Repository: "https://charts.example.com"}))
+	assert.Equal(t, "", findDependency(ChartRequirementsItem{Name: "app", Repository: "https://charts.example.com"}))
+	assert.Equal(t, "", findDependency(ChartRequirementsItem{Name: "postgresql", Repository: "https://charts.example.com"}))
+}
+
+func TestResetLocalCharts(t *testing.T) {
+	searchRoot, err := ioutil.TempDir("", "helm-docs-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(searchRoot)
+
+	writeChart := func(name string) {
+		assert.Nil(t, os.MkdirAll(path.Join(searchRoot, name), 0755))
+		assert.Nil(t, ioutil.WriteFile(path.Join(searchRoot, name, "Chart.yaml"), []byte("apiVersion: v2\nname: "+name+"\nversion: 1.0.0\n"), 0644))
+	}
+
+	viper.Set("chart-search-root", []string{searchRoot})
+	defer viper.Set("chart-search-root", nil)
+	defer ResetLocalCharts()
+
+	writeChart("app")
+	assert.Len(t, findLocalCharts(viper.GetViper()), 1)
+
+	// The charts found are kept until forgotten, however the charts change in the meantime
+	writeChart("common")
+	assert.Len(t, findLocalCharts(viper.GetViper()), 1)
+
+	ResetLocalCharts()
+	assert.Len(t, findLocalCharts(viper.GetViper()), 2)
+}
+
+func TestParseChartInformationInvalidFiles(t *testing.T) {
+	chartDirectory, err := ioutil.TempDir("", "helm-docs-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(chartDirectory)
+
+	chartYamlPath := path.Join(chartDirectory, "Chart.yaml")
+	assert.Nil(t, ioutil.WriteFile(chartYamlPath, []byte("name: [unclosed\n"), 0644))
+
+	_, err = ParseChartInformation(chartDirectory)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), chartYamlPath)
+
+	assert.Nil(t, ioutil.WriteFile(chartYamlPath, []byte("apiVersion: v2\nname: broken\nversion: 1.0.0\n"), 0644))
+	assert.Nil(t, os.Mkdir(path.Join(chartDirectory, "values.yaml"), 0755))
+
+	_, err = ParseChartInformation(chartDirectory)
+	assert.NotNil(t, err)
+}
+
+func TestParseChartInformationTimeout(t *testing.T) {
+	chartDirectory, err := ioutil.TempDir("", "helm-docs-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(chartDirectory)
+
+	// A stand in for a registry that never responds
+	registry := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(10 * time.Second):
+		}
+	}))
+
+	defer registry.Close()
+
+	chartYaml := fmt.Sprintf("apiVersion: v2\nname: slow\nversion: 1.0.0\ndependencies:\n  - name: redis\n    version: 1.0.0\n    repository: oci://%s/charts\n", strings.TrimPrefix(registry.URL, "http://"))
+	assert.Nil(t, ioutil.WriteFile(path.Join(chartDirectory, "Chart.yaml"), []byte(chartYaml), 0644))
+	assert.Nil(t, ioutil.WriteFile(path.Join(chartDirectory, "values.yaml"), []byte("replicas: 1\n"), 0644))
+
+	ociRegistryScheme = "http"
+	viper.Set("fetch-oci-dependencies", true)
+	viper.Set("values-metadata-file", "values.metadata.yaml")
+	defer func() { ociRegistryScheme = "https" }()
+	defer viper.Set("fetch-oci-dependencies", false)
+	defer viper.Set("values-metadata-file", nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err = ParseChartInformationContext(ctx, chartDirectory)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.True(t, time.Since(start) < 5*time.Second)
+}
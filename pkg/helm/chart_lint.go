@@ -0,0 +1,296 @@
+package helm
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/mail"
+	"net/url"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver"
+	"gopkg.in/yaml.v2"
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/renderutil"
+)
+
+// ChartLintMessage is a message of the given severity, one of INFO, WARNING or ERROR, reported by linting a file of the
+// chart, if any
+type ChartLintMessage struct {
+	Severity string
+	File     string
+	Message  string
+}
+
+// ChartLintResult holds the messages reported by linting a chart. Linted is false for charts which weren't linted
+type ChartLintResult struct {
+	Linted   bool
+	Messages []ChartLintMessage
+}
+
+// Count returns the number of messages of the given severity
+func (r ChartLintResult) Count(severity string) int {
+	count := 0
+	for _, m := range r.Messages {
+		if m.Severity == severity {
+			count++
+		}
+	}
+
+	return count
+}
+
+// Matches the names kubernetes requires of most objects, DNS subdomains of lowercase alphanumerics, - and .
+var kubernetesObjectNameRegex = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`)
+
+type chartLinter struct {
+	messages []ChartLintMessage
+}
+
+func (l *chartLinter) report(severity string, file string, format string, args ...interface{}) {
+	l.messages = append(l.messages, ChartLintMessage{Severity: severity, File: file, Message: fmt.Sprintf(format, args...)})
+}
+
+func isAbsoluteURL(location string) bool {
+	u, err := url.Parse(location)
+	return err == nil && u.Scheme != "" && u.Host != ""
+}
+
+// lintChartFile lints a chart's Chart.yaml, returning whether it could be read at all
+func (l *chartLinter) lintChartFile(files chartFiles) bool {
+	chartYaml, err := files.readFile("Chart.yaml")
+	if os.IsNotExist(err) {
+		l.report("ERROR", "Chart.yaml", "file does not exist")
+		return false
+	}
+
+	if err != nil {
+		l.report("ERROR", "Chart.yaml", "%s", err)
+		return false
+	}
+
+	var chartMeta ChartMeta
+	if err := yaml.Unmarshal(chartYaml, &chartMeta); err != nil {
+		l.report("ERROR", "Chart.yaml", "unable to parse YAML: %s", err)
+		return false
+	}
+
+	var chartFile struct {
+		Dependencies []interface{} `yaml:"dependencies"`
+	}
+
+	if err := yaml.Unmarshal(chartYaml, &chartFile); err != nil {
+		l.report("ERROR", "Chart.yaml", "unable to parse YAML: %s", err)
+		return false
+	}
+
+	if chartMeta.Name == "" {
+		l.report("ERROR", "Chart.yaml", "name is required")
+	} else if strings.Contains(chartMeta.Name, "/") {
+		l.report("ERROR", "Chart.yaml", "chart name %q is invalid, it must not contain a /", chartMeta.Name)
+	}
+
+	switch chartMeta.ApiVersion {
+	case "":
+		l.report("ERROR", "Chart.yaml", `apiVersion is required. The value must be either "v1" or "v2"`)
+	case "v1":
+		if len(chartFile.Dependencies) > 0 {
+			l.report("ERROR", "Chart.yaml", "dependencies are not valid in the Chart file with apiVersion 'v1'. They are valid in apiVersion 'v2'")
+		}
+
+		if chartMeta.Type != "" {
+			l.report("ERROR", "Chart.yaml", "chart type is not valid in apiVersion 'v1'. It is valid in apiVersion 'v2'")
+		}
+	case "v2":
+	default:
+		l.report("ERROR", "Chart.yaml", `apiVersion '%s' is not valid. The value must be either "v1" or "v2"`, chartMeta.ApiVersion)
+	}
+
+	if chartMeta.Version == "" {
+		l.report("ERROR", "Chart.yaml", "version is required")
+	} else if _, err := semver.NewVersion(chartMeta.Version); err != nil {
+		l.report("ERROR", "Chart.yaml", "version '%s' is not a valid SemVer", chartMeta.Version)
+	}
+
+	if chartMeta.Type != "" && chartMeta.Type != "application" && chartMeta.Type != "library" {
+		l.report("ERROR", "Chart.yaml", "chart type '%s' is not valid. The value must be either \"application\" or \"library\"", chartMeta.Type)
+	}
+
+	for _, m := range chartMeta.Maintainers {
+		if m.Name == "" {
+			l.report("ERROR", "Chart.yaml", "each maintainer requires a name")
+		} else if _, err := mail.ParseAddress(m.Email); m.Email != "" && err != nil {
+			l.report("ERROR", "Chart.yaml", "invalid email '%s' for maintainer '%s'", m.Email, m.Name)
+		} else if m.Url != "" && !isAbsoluteURL(m.Url) {
+			l.report("ERROR", "Chart.yaml", "invalid url '%s' for maintainer '%s'", m.Url, m.Name)
+		}
+	}
+
+	for _, s := range chartMeta.Sources {
+		if !isAbsoluteURL(s) {
+			l.report("ERROR", "Chart.yaml", "invalid source URL '%s'", s)
+		}
+	}
+
+	if chartMeta.Icon == "" {
+		l.report("INFO", "Chart.yaml", "icon is recommended")
+	} else if !isAbsoluteURL(chartMeta.Icon) {
+		l.report("ERROR", "Chart.yaml", "invalid icon URL '%s'", chartMeta.Icon)
+	}
+
+	return true
+}
+
+// lintValuesFile lints a chart's values.yaml, checking its default values against the chart's values.schema.json
+func (l *chartLinter) lintValuesFile(files chartFiles) {
+	valuesYaml, err := files.readFile("values.yaml")
+	if os.IsNotExist(err) {
+		l.report("INFO", "values.yaml", "file does not exist")
+		return
+	}
+
+	if err != nil {
+		l.report("ERROR", "values.yaml", "%s", err)
+		return
+	}
+
+	values, err := parseValues(valuesYaml)
+	if err != nil {
+		l.report("ERROR", "values.yaml", "unable to parse YAML: %s", err)
+		return
+	}
+
+	schemaValidation, err := parseChartSchemaValidation(files, values)
+	if err != nil {
+		l.report("ERROR", valuesSchemaFile, "%s", err)
+		return
+	}
+
+	for _, v := range schemaValidation.Violations {
+		l.report("ERROR", "values.yaml", "%s: %s", v.Key, v.Description)
+	}
+}
+
+// lintDependencies warns of dependencies of the chart which haven't been fetched into its charts/ directory
+func (l *chartLinter) lintDependencies(chartDirectory string) {
+	c, err := loadChart(chartDirectory)
+	if err != nil {
+		l.report("ERROR", "", "unable to load chart: %s", err)
+		return
+	}
+
+	requirements, err := chartutil.LoadRequirements(c)
+	if err == chartutil.ErrRequirementsNotFound {
+		return
+	}
+
+	if err != nil {
+		l.report("ERROR", "", "unable to load dependencies: %s", err)
+		return
+	}
+
+	if err := renderutil.CheckDependencies(c, requirements); err != nil {
+		l.report("WARNING", "", "%s", err)
+	}
+}
+
+// lintTemplates renders a chart's templates, checking that those rendering manifests render valid YAML naming objects
+// by names kubernetes accepts
+func (l *chartLinter) lintTemplates(ctx context.Context, chartDirectory string, files chartFiles) error {
+	templates, err := files.listFiles("templates")
+	if err != nil {
+		return err
+	}
+
+	if len(templates) == 0 {
+		l.report("INFO", "templates/", "directory does not exist")
+		return nil
+	}
+
+	rendered, err := renderChartTemplates(ctx, chartDirectory, true)
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+
+		l.report("ERROR", "templates/", "%s", err)
+		return nil
+	}
+
+	names := make([]string, 0, len(rendered))
+	for name := range rendered {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		// Rendered templates are named by the path of the chart's directory within the top-level chart's, e.g.
+		// app/charts/cache/templates/service.yaml, of which only those of the chart's own templates are linted
+		file := name[strings.Index(name, "/")+1:]
+		if !strings.HasPrefix(file, "templates/") || (path.Ext(file) != ".yaml" && path.Ext(file) != ".yml") {
+			continue
+		}
+
+		decoder := yaml.NewDecoder(bytes.NewReader([]byte(rendered[name])))
+		for {
+			var manifest struct {
+				Metadata struct {
+					Name string
+				}
+			}
+
+			err := decoder.Decode(&manifest)
+			if err == io.EOF {
+				break
+			}
+
+			if err != nil {
+				l.report("ERROR", file, "unable to parse YAML: %s", err)
+				break
+			}
+
+			objectName := manifest.Metadata.Name
+			if objectName != "" && (len(objectName) > 253 || !kubernetesObjectNameRegex.MatchString(objectName)) {
+				l.report("ERROR", file, "object name does not conform to Kubernetes naming requirements: %q", objectName)
+			}
+		}
+	}
+
+	return nil
+}
+
+// lintChart lints a chart directory or archive with rules following those of helm lint: its Chart.yaml must give a
+// name, a SemVer version and a valid apiVersion, its default values must parse and satisfy its values.schema.json, and
+// its templates must render valid YAML against them, rendered as helm lint renders them with helm's template engine.
+// The errors found are part of the result rather than a failure to lint, which is only for charts that can't be read
+func lintChart(ctx context.Context, chartDirectory string) (ChartLintResult, error) {
+	files, err := openChartFiles(chartDirectory)
+	if err != nil {
+		return ChartLintResult{}, fmt.Errorf("failed to lint chart %s: %s", chartDirectory, err)
+	}
+
+	linter := chartLinter{messages: make([]ChartLintMessage, 0)}
+	if !linter.lintChartFile(files) {
+		return ChartLintResult{Linted: true, Messages: linter.messages}, nil
+	}
+
+	linter.lintValuesFile(files)
+
+	if err := ctx.Err(); err != nil {
+		return ChartLintResult{}, err
+	}
+
+	linter.lintDependencies(chartDirectory)
+
+	if err := linter.lintTemplates(ctx, chartDirectory, files); err != nil {
+		return ChartLintResult{}, err
+	}
+
+	return ChartLintResult{Linted: true, Messages: linter.messages}, nil
+}
@@ -0,0 +1,68 @@
+package helm
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLintChart(t *testing.T) {
+	chartDirectory, err := ioutil.TempDir("", "helm-docs-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(chartDirectory)
+
+	files := map[string]string{
+		"Chart.yaml": `apiVersion: v2
+name: app
+version: one
+maintainers:
+  - email: jane@example.com
+dependencies:
+  - name: cache
+    version: 1.0.0
+`,
+		"values.yaml":            "replicas: two\n",
+		"values.schema.json":     `{"properties": {"replicas": {"type": "integer"}}}`,
+		"templates/service.yaml": "kind: Service\nmetadata:\n  name: {{ required \"a host is required\" .Values.host }}-Service\n",
+		"templates/config.yaml":  "kind: ConfigMap\ndata: a: b\n",
+		"templates/NOTES.txt":    "Installed {{ .Release.Name }}",
+	}
+
+	for name, contents := range files {
+		assert.Nil(t, os.MkdirAll(path.Dir(path.Join(chartDirectory, name)), 0755))
+		assert.Nil(t, ioutil.WriteFile(path.Join(chartDirectory, name), []byte(contents), 0644))
+	}
+
+	result, err := lintChart(context.Background(), chartDirectory)
+	assert.Nil(t, err)
+	assert.Equal(t, ChartLintResult{
+		Linted: true,
+		Messages: []ChartLintMessage{
+			{Severity: "ERROR", File: "Chart.yaml", Message: "version 'one' is not a valid SemVer"},
+			{Severity: "ERROR", File: "Chart.yaml", Message: "each maintainer requires a name"},
+			{Severity: "INFO", File: "Chart.yaml", Message: "icon is recommended"},
+			{Severity: "ERROR", File: "values.yaml", Message: "replicas: Invalid type. Expected: integer, given: string"},
+			{Severity: "WARNING", File: "", Message: "found in requirements.yaml, but missing in charts/ directory: cache"},
+			{Severity: "ERROR", File: "templates/config.yaml", Message: "unable to parse YAML: yaml: line 2: mapping values are not allowed in this context"},
+			{Severity: "ERROR", File: "templates/service.yaml", Message: `object name does not conform to Kubernetes naming requirements: "-Service"`},
+		},
+	}, result)
+
+	assert.Equal(t, 5, result.Count("ERROR"))
+	assert.Equal(t, 0, result.Count("UNKNOWN"))
+
+	assert.Nil(t, ioutil.WriteFile(path.Join(chartDirectory, "Chart.yaml"), []byte("name: [app\n"), 0644))
+	result, err = lintChart(context.Background(), chartDirectory)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, result.Count("ERROR"))
+
+	_, err = lintChart(context.Background(), path.Join(chartDirectory, "missing.tgz"))
+	assert.NotNil(t, err)
+}
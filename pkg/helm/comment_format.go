@@ -0,0 +1,78 @@
+package helm
+
+import (
+	"fmt"
+	"regexp"
+)
+
+const (
+	defaultCommentPrefix        = "#"
+	defaultDescriptionSeparator = "--"
+)
+
+// Key paths in description comments are made up of unquoted parts without whitespace, and quoted parts which may contain
+// anything but an unescaped quote
+const keyPathPattern = `((?:"(?:[^"\\]|\\.)*"|[^\s"])+)`
+
+// valuesCommentRegexes holds the regular expressions matching each of the kinds of comment line that document values,
+// built from the (configurable) comment prefix and description separator patterns
+type valuesCommentRegexes struct {
+	description   *regexp.Regexp
+	continuation  *regexp.Regexp
+	defaultValue  *regexp.Regexp
+	required      *regexp.Regexp
+	sensitive     *regexp.Regexp
+	ignore        *regexp.Regexp
+	collapse      *regexp.Regexp
+	typeOverride  *regexp.Regexp
+	allowedValues *regexp.Regexp
+	section       *regexp.Regexp
+
+	// Bitnami style comments start with "##", which is the comment prefix when it's been set to match them, and the
+	// default comment prefix followed by another "#" otherwise
+	bitnamiParam   *regexp.Regexp
+	bitnamiSection *regexp.Regexp
+}
+
+func newValuesCommentRegexes(commentPrefix string, descriptionSeparator string) (valuesCommentRegexes, error) {
+	if commentPrefix == "" {
+		commentPrefix = defaultCommentPrefix
+	}
+
+	if descriptionSeparator == "" {
+		descriptionSeparator = defaultDescriptionSeparator
+	}
+
+	var err error
+	compile := func(format string) *regexp.Regexp {
+		if err != nil {
+			return nil
+		}
+
+		var r *regexp.Regexp
+		r, err = regexp.Compile(fmt.Sprintf(format, commentPrefix, descriptionSeparator, keyPathPattern))
+		return r
+	}
+
+	regexes := valuesCommentRegexes{
+		description:   compile("^\\s*(?:%[1]s) %[3]s (?:%[2]s) (.*)$"),
+		continuation:  compile("^\\s*(?:%[1]s) (.*)$"),
+		defaultValue:  compile("^\\s*(?:%[1]s) @default (?:%[2]s) (.*)$"),
+		required:      compile("^\\s*(?:%[1]s) @required\\s*$"),
+		sensitive:     compile("^\\s*(?:%[1]s) @sensitive\\s*$"),
+		ignore:        compile("^\\s*(?:%[1]s) @ignore\\s*$"),
+		collapse:      compile("^\\s*(?:%[1]s) @collapse\\s*$"),
+		typeOverride:  compile("^\\s*(?:%[1]s) @type (?:%[2]s) (.*)$"),
+		allowedValues: compile("^\\s*(?:%[1]s) @allowed (?:%[2]s) \\[?(.*?)\\]?\\s*$"),
+		section:       compile("^\\s*(?:%[1]s) @section (?:%[2]s) (.*)$"),
+
+		bitnamiParam:   compile("^\\s*(?:%[1]s)#? @param (\\S+)\\s*((?:\\[[a-z, ]+\\]\\s*)?)(.*)$"),
+		bitnamiSection: compile("^\\s*(?:%[1]s)#? @section (.*)$"),
+	}
+
+	if err != nil {
+		return regexes, fmt.Errorf("invalid values comment format: %s", err)
+	}
+
+	return regexes, nil
+}
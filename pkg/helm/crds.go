@@ -0,0 +1,107 @@
+package helm
+
+import (
+	"bytes"
+	"io"
+	"path"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// ChartCRD describes a custom resource definition shipped in a chart's crds/ directory
+type ChartCRD struct {
+	Name     string
+	Group    string
+	Kind     string
+	Scope    string
+	Versions []string
+}
+
+type customResourceDefinition struct {
+	Kind     string
+	Metadata struct {
+		Name string
+	}
+	Spec struct {
+		Group string
+		Names struct {
+			Kind string
+		}
+		Scope string
+
+		// apiextensions.k8s.io/v1beta1 definitions may give a single version rather than a list
+		Version  string
+		Versions []struct {
+			Name string
+		}
+	}
+}
+
+func parseCRDs(contents []byte) ([]ChartCRD, error) {
+	crds := make([]ChartCRD, 0)
+	decoder := yaml.NewDecoder(bytes.NewReader(contents))
+
+	for {
+		var crd customResourceDefinition
+		err := decoder.Decode(&crd)
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		if crd.Kind != "CustomResourceDefinition" {
+			continue
+		}
+
+		versions := make([]string, 0)
+		for _, v := range crd.Spec.Versions {
+			versions = append(versions, v.Name)
+		}
+
+		if len(versions) == 0 && crd.Spec.Version != "" {
+			versions = append(versions, crd.Spec.Version)
+		}
+
+		crds = append(crds, ChartCRD{
+			Name:     crd.Metadata.Name,
+			Group:    crd.Spec.Group,
+			Kind:     crd.Spec.Names.Kind,
+			Scope:    crd.Spec.Scope,
+			Versions: versions,
+		})
+	}
+
+	return crds, nil
+}
+
+func parseChartCRDs(files chartFiles) ([]ChartCRD, error) {
+	crdFiles, err := files.listFiles("crds")
+	if err != nil {
+		return nil, err
+	}
+
+	crds := make([]ChartCRD, 0)
+	for _, f := range crdFiles {
+		if path.Dir(f) != "crds" || !(strings.HasSuffix(f, ".yaml") || strings.HasSuffix(f, ".yml")) {
+			continue
+		}
+
+		contents, err := files.readFile(f)
+		if err != nil {
+			return nil, err
+		}
+
+		fileCRDs, err := parseCRDs(contents)
+		if err != nil {
+			return nil, err
+		}
+
+		crds = append(crds, fileCRDs...)
+	}
+
+	return crds, nil
+}
@@ -0,0 +1,46 @@
+package helm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCRDs(t *testing.T) {
+	crds, err := parseCRDs([]byte(`
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: crontabs.stable.example.com
+spec:
+  group: stable.example.com
+  scope: Namespaced
+  names:
+    kind: CronTab
+  versions:
+    - name: v1
+    - name: v2
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: not-a-crd
+---
+apiVersion: apiextensions.k8s.io/v1beta1
+kind: CustomResourceDefinition
+metadata:
+  name: backups.example.com
+spec:
+  group: example.com
+  scope: Cluster
+  version: v1alpha1
+  names:
+    kind: Backup
+`))
+
+	assert.Nil(t, err)
+	assert.Equal(t, []ChartCRD{
+		{Name: "crontabs.stable.example.com", Group: "stable.example.com", Kind: "CronTab", Scope: "Namespaced", Versions: []string{"v1", "v2"}},
+		{Name: "backups.example.com", Group: "example.com", Kind: "Backup", Scope: "Cluster", Versions: []string{"v1alpha1"}},
+	}, crds)
+}
@@ -0,0 +1,126 @@
+package helm
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+type ChartDependencyValues struct {
+	ChartValuesFile
+
+	// The name under which the dependency's values are nested in the parent chart's values, its alias if it has one
+	Name string
+}
+
+func dependencyValuesName(dependency ChartRequirementsItem) string {
+	if dependency.Alias != "" {
+		return dependency.Alias
+	}
+
+	return dependency.Name
+}
+
+// readPackagedChartFile reads a file of a packaged chart that's been read into memory, e.g. a dependency packaged in a
+// chart's charts/ directory
+func readPackagedChartFile(archive []byte, archivePath string, filename string) ([]byte, error) {
+	files, err := readPackagedChart(bytes.NewReader(archive), archivePath)
+	if err != nil {
+		return nil, err
+	}
+
+	contents, ok := files[filename]
+	if !ok {
+		return nil, fmt.Errorf("failed to find %s in chart archive %s", filename, archivePath)
+	}
+
+	return contents, nil
+}
+
+// readDependencyValuesFile reads the values file of a chart dependency from a local file:// repository, or from the
+// chart's charts/ directory where it may be either unpacked or packaged. If the dependency can't be found, nil is
+// returned
+func readDependencyValuesFile(chartDirectory string, files chartFiles, dependency ChartRequirementsItem) ([]byte, error) {
+	// The paths of file:// repositories are relative to a chart's directory, so packaged charts can't have them
+	if strings.HasPrefix(dependency.Repository, "file://") && !IsChartArchive(chartDirectory) {
+		repositoryPath := strings.TrimPrefix(dependency.Repository, "file://")
+		if !filepath.IsAbs(repositoryPath) {
+			repositoryPath = path.Join(chartDirectory, repositoryPath)
+		}
+
+		valuesPath := path.Join(repositoryPath, "values.yaml")
+		if _, err := os.Stat(valuesPath); err == nil {
+			return ioutil.ReadFile(valuesPath)
+		}
+	}
+
+	valuesFileContents, err := files.readFile(path.Join("charts", dependency.Name, "values.yaml"))
+	if !os.IsNotExist(err) {
+		return valuesFileContents, err
+	}
+
+	// Dependency versions are usually ranges, so unless the archive for an exact version is present, use the only
+	// archive there is for the dependency
+	archiveName := path.Join("charts", fmt.Sprintf("%s-%s.tgz", dependency.Name, dependency.Version))
+	if archive, err := files.readFile(archiveName); err == nil {
+		return readPackagedChartFile(archive, files.path(archiveName), "values.yaml")
+	}
+
+	chartsFiles, err := files.listFiles("charts")
+	if err != nil {
+		return nil, err
+	}
+
+	archiveNames := make([]string, 0)
+	for _, f := range chartsFiles {
+		if matched, _ := path.Match(fmt.Sprintf("charts/%s-*.tgz", dependency.Name), f); matched {
+			archiveNames = append(archiveNames, f)
+		}
+	}
+
+	if len(archiveNames) == 1 {
+		archive, err := files.readFile(archiveNames[0])
+		if err != nil {
+			return nil, err
+		}
+
+		return readPackagedChartFile(archive, files.path(archiveNames[0]), "values.yaml")
+	}
+
+	return nil, nil
+}
+
+func parseChartDependencyValues(chartSettings *viper.Viper, chartDirectory string, files chartFiles, dependencies []ChartRequirementsItem) ([]ChartDependencyValues, error) {
+	dependencyValues := make([]ChartDependencyValues, 0)
+
+	for _, d := range dependencies {
+		valuesFileContents, err := readDependencyValuesFile(chartDirectory, files, d)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read values of dependency %s: %s", d.Name, err)
+		}
+
+		if valuesFileContents == nil {
+			log.Debugf("Did not find dependency %s of chart %s locally, not documenting its values", d.Name, chartDirectory)
+			continue
+		}
+
+		valuesFile, err := parseValuesFileContents(chartSettings, valuesFileContents)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse values of dependency %s: %s", d.Name, err)
+		}
+
+		dependencyValues = append(dependencyValues, ChartDependencyValues{
+			ChartValuesFile: valuesFile,
+			Name:            dependencyValuesName(d),
+		})
+	}
+
+	return dependencyValues, nil
+}
@@ -0,0 +1,147 @@
+package helm
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseChartDependencyValues(t *testing.T) {
+	chartsDirectory, err := ioutil.TempDir("", "helm-docs-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(chartsDirectory)
+
+	chartDirectory := path.Join(chartsDirectory, "app")
+	for _, d := range []string{path.Join(chartsDirectory, "common"), path.Join(chartDirectory, "charts", "postgresql")} {
+		assert.Nil(t, os.MkdirAll(d, 0755))
+	}
+
+	assert.Nil(t, ioutil.WriteFile(path.Join(chartsDirectory, "common", "values.yaml"), []byte("# labels -- Labels added to every object\nlabels: {}\n"), 0644))
+	assert.Nil(t, ioutil.WriteFile(path.Join(chartDirectory, "charts", "postgresql", "values.yaml"), []byte("# port -- The port postgres listens on\nport: 5432\n"), 0644))
+	writeChartArchive(t, path.Join(chartDirectory, "charts", "redis-17.0.0.tgz"), map[string]string{
+		"redis/Chart.yaml":  "apiVersion: v2\nname: redis\nversion: 17.0.0\n",
+		"redis/values.yaml": "# replicas -- Number of redis replicas\nreplicas: 3\n",
+	})
+
+	dependencies := []ChartRequirementsItem{
+		{Name: "common", Version: "1.0.0", Repository: "file://../common"},
+		{Name: "postgresql", Version: "12.0.0", Repository: "https://charts.bitnami.com/bitnami", Alias: "database"},
+		{Name: "redis", Version: "~17.0.0", Repository: "https://charts.bitnami.com/bitnami", Alias: "cache"},
+		{Name: "nginx", Version: "1.0.0", Repository: "https://charts.bitnami.com/bitnami"},
+	}
+
+	dependencyValues, err := parseChartDependencyValues(viper.GetViper(), chartDirectory, directoryChartFiles(chartDirectory), dependencies)
+	assert.Nil(t, err)
+	assert.Len(t, dependencyValues, 3)
+
+	assert.Equal(t, "common", dependencyValues[0].Name)
+	assert.Equal(t, "Labels added to every object", dependencyValues[0].Descriptions["labels"].Description)
+
+	assert.Equal(t, "database", dependencyValues[1].Name)
+	assert.Equal(t, 5432, dependencyValues[1].Values["port"])
+
+	assert.Equal(t, "cache", dependencyValues[2].Name)
+	assert.Equal(t, 3, dependencyValues[2].Values["replicas"])
+	assert.Equal(t, "Number of redis replicas", dependencyValues[2].Descriptions["replicas"].Description)
+}
+
+func TestReadDependencyValuesFileExactArchive(t *testing.T) {
+	chartDirectory, err := ioutil.TempDir("", "helm-docs-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(chartDirectory)
+
+	assert.Nil(t, os.Mkdir(path.Join(chartDirectory, "charts"), 0755))
+	for _, version := range []string{"17.0.0", "17.1.0"} {
+		writeChartArchive(t, path.Join(chartDirectory, "charts", "redis-"+version+".tgz"), map[string]string{
+			"redis/Chart.yaml":  "apiVersion: v2\nname: redis\nversion: " + version + "\n",
+			"redis/values.yaml": "version: " + version + "\n",
+		})
+	}
+
+	files := directoryChartFiles(chartDirectory)
+
+	valuesFileContents, err := readDependencyValuesFile(chartDirectory, files, ChartRequirementsItem{Name: "redis", Version: "17.1.0"})
+	assert.Nil(t, err)
+	assert.Equal(t, "version: 17.1.0\n", string(valuesFileContents))
+
+	// With several archives and a version range, which archive the dependency is can't be told
+	valuesFileContents, err = readDependencyValuesFile(chartDirectory, files, ChartRequirementsItem{Name: "redis", Version: "^17.0.0"})
+	assert.Nil(t, err)
+	assert.Nil(t, valuesFileContents)
+}
+
+func TestReadDependencyValuesFileFromChartArchive(t *testing.T) {
+	archiveDirectory, err := ioutil.TempDir("", "helm-docs-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(archiveDirectory)
+
+	subchartArchivePath := path.Join(archiveDirectory, "redis.tgz")
+	writeChartArchive(t, subchartArchivePath, map[string]string{
+		"redis/Chart.yaml":  "apiVersion: v2\nname: redis\nversion: 17.0.0\n",
+		"redis/values.yaml": "replicas: 3\n",
+	})
+
+	subchartArchive, err := ioutil.ReadFile(subchartArchivePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	archivePath := path.Join(archiveDirectory, "app-1.0.0.tgz")
+	files := archiveChartFiles{archivePath: archivePath, files: map[string][]byte{
+		"charts/redis-17.0.0.tgz":       subchartArchive,
+		"charts/postgresql/values.yaml": []byte("port: 5432\n"),
+	}}
+
+	// The paths of file:// repositories are relative to a chart directory a packaged chart doesn't have, so they're
+	// looked for in its charts/ directory instead
+	valuesFileContents, err := readDependencyValuesFile(archivePath, files, ChartRequirementsItem{Name: "postgresql", Version: "12.0.0", Repository: "file://../postgresql"})
+	assert.Nil(t, err)
+	assert.Equal(t, "port: 5432\n", string(valuesFileContents))
+
+	valuesFileContents, err = readDependencyValuesFile(archivePath, files, ChartRequirementsItem{Name: "redis", Version: "17.0.0"})
+	assert.Nil(t, err)
+	assert.Equal(t, "replicas: 3\n", string(valuesFileContents))
+}
+
+func TestReadPackagedChartFile(t *testing.T) {
+	archiveDirectory, err := ioutil.TempDir("", "helm-docs-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(archiveDirectory)
+
+	archivePath := path.Join(archiveDirectory, "redis-17.0.0.tgz")
+	writeChartArchive(t, archivePath, map[string]string{
+		"redis/Chart.yaml":  "apiVersion: v2\nname: redis\nversion: 17.0.0\n",
+		"redis/values.yaml": "replicas: 3\n",
+	})
+
+	archive, err := ioutil.ReadFile(archivePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	contents, err := readPackagedChartFile(archive, archivePath, "values.yaml")
+	assert.Nil(t, err)
+	assert.Equal(t, "replicas: 3\n", string(contents))
+
+	_, err = readPackagedChartFile(archive, archivePath, "values.schema.json")
+	assert.NotNil(t, err)
+
+	_, err = readPackagedChartFile([]byte("not a gzipped tarball"), archivePath, "values.yaml")
+	assert.NotNil(t, err)
+}
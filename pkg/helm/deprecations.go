@@ -0,0 +1,88 @@
+package helm
+
+// ChartAPIDeprecation is a resource installed by a chart with its default values whose API version is removed in the
+// given Kubernetes release, so that the chart breaks on upgrading a cluster to it
+type ChartAPIDeprecation struct {
+	Kind        string
+	Name        string
+	APIVersion  string
+	RemovedIn   string
+	Replacement string
+}
+
+type removedAPI struct {
+	apiVersion  string
+	kinds       []string
+	removedIn   string
+	replacement string
+}
+
+// The API versions of resources removed from Kubernetes, by the release removing them, along with the API version to
+// migrate to, if any
+var removedAPIs = []removedAPI{
+	{"extensions/v1beta1", []string{"DaemonSet", "Deployment", "ReplicaSet"}, "v1.16", "apps/v1"},
+	{"apps/v1beta1", []string{"Deployment", "StatefulSet"}, "v1.16", "apps/v1"},
+	{"apps/v1beta2", []string{"DaemonSet", "Deployment", "ReplicaSet", "StatefulSet"}, "v1.16", "apps/v1"},
+	{"extensions/v1beta1", []string{"NetworkPolicy"}, "v1.16", "networking.k8s.io/v1"},
+	{"extensions/v1beta1", []string{"PodSecurityPolicy"}, "v1.16", "policy/v1beta1"},
+	{"extensions/v1beta1", []string{"Ingress"}, "v1.22", "networking.k8s.io/v1"},
+	{"networking.k8s.io/v1beta1", []string{"Ingress", "IngressClass"}, "v1.22", "networking.k8s.io/v1"},
+	{"rbac.authorization.k8s.io/v1beta1", []string{"ClusterRole", "ClusterRoleBinding", "Role", "RoleBinding"}, "v1.22", "rbac.authorization.k8s.io/v1"},
+	{"apiextensions.k8s.io/v1beta1", []string{"CustomResourceDefinition"}, "v1.22", "apiextensions.k8s.io/v1"},
+	{"admissionregistration.k8s.io/v1beta1", []string{"MutatingWebhookConfiguration", "ValidatingWebhookConfiguration"}, "v1.22", "admissionregistration.k8s.io/v1"},
+	{"apiregistration.k8s.io/v1beta1", []string{"APIService"}, "v1.22", "apiregistration.k8s.io/v1"},
+	{"scheduling.k8s.io/v1beta1", []string{"PriorityClass"}, "v1.22", "scheduling.k8s.io/v1"},
+	{"storage.k8s.io/v1beta1", []string{"CSIDriver", "CSINode", "StorageClass", "VolumeAttachment"}, "v1.22", "storage.k8s.io/v1"},
+	{"coordination.k8s.io/v1beta1", []string{"Lease"}, "v1.22", "coordination.k8s.io/v1"},
+	{"certificates.k8s.io/v1beta1", []string{"CertificateSigningRequest"}, "v1.22", "certificates.k8s.io/v1"},
+	{"batch/v1beta1", []string{"CronJob"}, "v1.25", "batch/v1"},
+	{"discovery.k8s.io/v1beta1", []string{"EndpointSlice"}, "v1.25", "discovery.k8s.io/v1"},
+	{"events.k8s.io/v1beta1", []string{"Event"}, "v1.25", "events.k8s.io/v1"},
+	{"autoscaling/v2beta1", []string{"HorizontalPodAutoscaler"}, "v1.25", "autoscaling/v2"},
+	{"policy/v1beta1", []string{"PodDisruptionBudget"}, "v1.25", "policy/v1"},
+	{"policy/v1beta1", []string{"PodSecurityPolicy"}, "v1.25", ""},
+	{"node.k8s.io/v1beta1", []string{"RuntimeClass"}, "v1.25", "node.k8s.io/v1"},
+	{"autoscaling/v2beta2", []string{"HorizontalPodAutoscaler"}, "v1.26", "autoscaling/v2"},
+	{"flowcontrol.apiserver.k8s.io/v1beta1", []string{"FlowSchema", "PriorityLevelConfiguration"}, "v1.26", "flowcontrol.apiserver.k8s.io/v1"},
+	{"storage.k8s.io/v1beta1", []string{"CSIStorageCapacity"}, "v1.27", "storage.k8s.io/v1"},
+	{"flowcontrol.apiserver.k8s.io/v1beta2", []string{"FlowSchema", "PriorityLevelConfiguration"}, "v1.29", "flowcontrol.apiserver.k8s.io/v1"},
+	{"flowcontrol.apiserver.k8s.io/v1beta3", []string{"FlowSchema", "PriorityLevelConfiguration"}, "v1.32", "flowcontrol.apiserver.k8s.io/v1"},
+}
+
+func findRemovedAPI(apiVersion string, kind string) (removedAPI, bool) {
+	for _, r := range removedAPIs {
+		if r.apiVersion != apiVersion {
+			continue
+		}
+
+		for _, k := range r.kinds {
+			if k == kind {
+				return r, true
+			}
+		}
+	}
+
+	return removedAPI{}, false
+}
+
+// findAPIDeprecations finds the resources rendered from a chart whose API versions are removed in some Kubernetes release
+func findAPIDeprecations(resources []ChartResource) []ChartAPIDeprecation {
+	deprecations := make([]ChartAPIDeprecation, 0)
+
+	for _, r := range resources {
+		removed, ok := findRemovedAPI(r.APIVersion, r.Kind)
+		if !ok {
+			continue
+		}
+
+		deprecations = append(deprecations, ChartAPIDeprecation{
+			Kind:        r.Kind,
+			Name:        r.Name,
+			APIVersion:  r.APIVersion,
+			RemovedIn:   removed.removedIn,
+			Replacement: removed.replacement,
+		})
+	}
+
+	return deprecations
+}
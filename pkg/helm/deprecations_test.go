@@ -0,0 +1,21 @@
+package helm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindAPIDeprecations(t *testing.T) {
+	deprecations := findAPIDeprecations([]ChartResource{
+		{APIVersion: "v1", Kind: "Service", Name: "web"},
+		{APIVersion: "extensions/v1beta1", Kind: "Ingress", Name: "web"},
+		{APIVersion: "policy/v1beta1", Kind: "PodSecurityPolicy", Name: "restricted"},
+		{APIVersion: "apps/v1beta1", Kind: "DaemonSet", Name: "agent"},
+	})
+
+	assert.Equal(t, []ChartAPIDeprecation{
+		{Kind: "Ingress", Name: "web", APIVersion: "extensions/v1beta1", RemovedIn: "v1.22", Replacement: "networking.k8s.io/v1"},
+		{Kind: "PodSecurityPolicy", Name: "restricted", APIVersion: "policy/v1beta1", RemovedIn: "v1.25", Replacement: ""},
+	}, deprecations)
+}
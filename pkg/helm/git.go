@@ -0,0 +1,84 @@
+package helm
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// ChartGitMetadata describes how recently and by how many people a chart was changed, from the git log of its directory
+type ChartGitMetadata struct {
+	LastCommitDate   string
+	LastCommitSHA    string
+	ContributorCount int
+}
+
+// Separates the fields of each commit in the output of git log, as it can't occur in any of them
+const gitLogFieldSeparator = "\x1f"
+
+// runGit runs a git command in a chart's directory, so that the paths given to it are relative to the chart
+func runGit(ctx context.Context, chartDirectory string, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	command := exec.CommandContext(ctx, "git", args...)
+	command.Dir = chartDirectory
+	command.Stdout = &stdout
+	command.Stderr = &stderr
+
+	if err := command.Run(); err != nil {
+		return "", fmt.Errorf("failed to run git %s: %s: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+
+	return stdout.String(), nil
+}
+
+// chartGitPath returns the directory in which to run git for a chart, and the path of the chart there. A packaged
+// chart's history is that of its archive file
+func chartGitPath(chartDirectory string) (string, string) {
+	if IsChartArchive(chartDirectory) {
+		return filepath.Dir(chartDirectory), filepath.Base(chartDirectory)
+	}
+
+	return chartDirectory, "."
+}
+
+// parseGitMetadataLog reads the metadata of a chart from the hash, date and author email of each commit changing it, as
+// logged by git log newest first. Authors are counted by their email, ignoring case
+func parseGitMetadataLog(output string) ChartGitMetadata {
+	var gitMetadata ChartGitMetadata
+	authors := make(map[string]bool)
+
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.SplitN(line, gitLogFieldSeparator, 3)
+		if len(fields) != 3 {
+			continue
+		}
+
+		if gitMetadata.LastCommitSHA == "" {
+			gitMetadata.LastCommitSHA = fields[0]
+			gitMetadata.LastCommitDate = fields[1]
+		}
+
+		authors[strings.ToLower(fields[2])] = true
+	}
+
+	gitMetadata.ContributorCount = len(authors)
+	return gitMetadata
+}
+
+// parseChartGitMetadata finds the last commit changing a chart's directory, and counts the distinct authors of the
+// commits changing it. Charts which aren't in a git repository are left without metadata, with a warning
+func parseChartGitMetadata(ctx context.Context, chartDirectory string) ChartGitMetadata {
+	gitDirectory, gitPath := chartGitPath(chartDirectory)
+	output, err := runGit(ctx, gitDirectory, "log", "--date=short", "--format=%H%x1f%ad%x1f%ae", "--", gitPath)
+	if err != nil {
+		log.Warnf("Error reading the git history of chart %s, leaving its git metadata empty: %s", chartDirectory, err)
+		return ChartGitMetadata{}
+	}
+
+	return parseGitMetadataLog(output)
+}
@@ -0,0 +1,16 @@
+package helm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseGitMetadataLog(t *testing.T) {
+	gitMetadata := parseGitMetadataLog("c3\x1f2020-03-03\x1fjane@example.com\n" +
+		"b2\x1f2020-02-02\x1fJane@example.com\n" +
+		"a1\x1f2020-01-01\x1fjohn@example.com\n")
+
+	assert.Equal(t, ChartGitMetadata{LastCommitDate: "2020-03-03", LastCommitSHA: "c3", ContributorCount: 2}, gitMetadata)
+	assert.Equal(t, ChartGitMetadata{}, parseGitMetadataLog(""))
+}
@@ -0,0 +1,193 @@
+package helm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const githubNoreplyEmailDomain = "@users.noreply.github.com"
+
+// The GitHub API, with which the accounts of maintainers' emails are looked up. Replaced in tests
+var githubAPIURL = "https://api.github.com"
+
+var githubUsernamePattern = regexp.MustCompile(`^[A-Za-z0-9](?:[A-Za-z0-9]|-[A-Za-z0-9]){0,38}$`)
+
+// The client searching GitHub's API, which gives up on a search that's slow to respond rather than holding up the run
+var githubClient = &http.Client{Timeout: 10 * time.Second}
+
+// githubEmailLookup is a lookup of the GitHub account of an email, which is done once whatever is waiting on it
+type githubEmailLookup struct {
+	done     chan struct{}
+	username string
+
+	// Whether the lookup was abandoned on a timeout or cancellation, and so is to be done again by the next waiting on it
+	abandoned bool
+}
+
+// The lookups of the GitHub accounts of emails so far, whose usernames are "" for emails with none or that couldn't be
+// looked up, so that the maintainers of many charts, documented concurrently, are only looked up once per run
+var githubEmailLookups struct {
+	sync.Mutex
+	lookups map[string]*githubEmailLookup
+}
+
+// githubProfileUsername returns the username of the account of a GitHub profile url, e.g. https://github.com/norwoodj,
+// or "" if the url isn't of a profile
+func githubProfileUsername(profileURL string) string {
+	u, err := url.Parse(profileURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || (u.Host != "github.com" && u.Host != "www.github.com") {
+		return ""
+	}
+
+	username := strings.Trim(u.Path, "/")
+	if !githubUsernamePattern.MatchString(username) {
+		return ""
+	}
+
+	return username
+}
+
+// githubNoreplyUsername returns the username of the account of a GitHub noreply email, which are either of the form
+// username@users.noreply.github.com or id+username@users.noreply.github.com, or "" if the email isn't one
+func githubNoreplyUsername(email string) string {
+	if !strings.HasSuffix(strings.ToLower(email), githubNoreplyEmailDomain) {
+		return ""
+	}
+
+	username := email[:len(email)-len(githubNoreplyEmailDomain)]
+	if i := strings.Index(username, "+"); i >= 0 {
+		username = username[i+1:]
+	}
+
+	if !githubUsernamePattern.MatchString(username) {
+		return ""
+	}
+
+	return username
+}
+
+type githubUserSearchResult struct {
+	TotalCount int `json:"total_count"`
+	Items      []struct {
+		Login string
+	}
+}
+
+// searchGitHubEmailUsername looks up the account with a public email with GitHub's user search, returning "" if there
+// isn't exactly one. Requests are authenticated with the GITHUB_TOKEN environment variable if set, as the search's rate
+// limit for anonymous requests is low
+func searchGitHubEmailUsername(ctx context.Context, email string) (string, error) {
+	searchURL := fmt.Sprintf("%s/search/users?q=%s", githubAPIURL, url.QueryEscape(email+" in:email"))
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, searchURL, nil)
+	if err != nil {
+		return "", err
+	}
+
+	request.Header.Set("Accept", "application/vnd.github+json")
+	if token := os.Getenv("GITHUB_TOKEN"); token != "" {
+		request.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	response, err := githubClient.Do(request)
+	if err != nil {
+		return "", err
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to search for GitHub users: %s", response.Status)
+	}
+
+	var result githubUserSearchResult
+	if err := json.NewDecoder(response.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("failed to parse GitHub user search results: %s", err)
+	}
+
+	if result.TotalCount != 1 || len(result.Items) != 1 {
+		return "", nil
+	}
+
+	return result.Items[0].Login, nil
+}
+
+// lookupGitHubEmailUsername returns the username of the GitHub account of an email, looking it up only once. The lock
+// is only held to find or start the email's lookup, so that lookups of other emails needn't wait on this one, and
+// waiting on another chart's lookup of the same email is given up on if the context is done first
+func lookupGitHubEmailUsername(ctx context.Context, chartDirectory string, email string) string {
+	for {
+		githubEmailLookups.Lock()
+		if githubEmailLookups.lookups == nil {
+			githubEmailLookups.lookups = make(map[string]*githubEmailLookup)
+		}
+
+		lookup, looking := githubEmailLookups.lookups[email]
+		if !looking {
+			lookup = &githubEmailLookup{done: make(chan struct{})}
+			githubEmailLookups.lookups[email] = lookup
+		}
+
+		githubEmailLookups.Unlock()
+
+		if looking {
+			select {
+			case <-lookup.done:
+			case <-ctx.Done():
+				return ""
+			}
+
+			if lookup.abandoned {
+				continue
+			}
+
+			return lookup.username
+		}
+
+		username, err := searchGitHubEmailUsername(ctx, email)
+		if err != nil {
+			log.Warnf("Error looking up the GitHub account of maintainer %s of chart %s: %s", email, chartDirectory, err)
+		}
+
+		// Lookups abandoned on a timeout may succeed for another chart, so are forgotten
+		githubEmailLookups.Lock()
+		if ctx.Err() != nil {
+			lookup.abandoned = true
+			delete(githubEmailLookups.lookups, email)
+		} else {
+			lookup.username = username
+		}
+
+		githubEmailLookups.Unlock()
+		close(lookup.done)
+		return username
+	}
+}
+
+// describeGitHubMaintainers fills in the GitHub usernames of maintainers whose url is a GitHub profile or whose email is
+// a GitHub noreply address, looking up the accounts of other emails with the GitHub API. The API may well be
+// unreachable, e.g. when run offline, so maintainers whose accounts can't be looked up are left as they are with a
+// warning rather than failing the chart's documentation
+func describeGitHubMaintainers(ctx context.Context, chartDirectory string, maintainers []ChartMetaMaintainer) {
+	for i, m := range maintainers {
+		username := githubProfileUsername(m.Url)
+		if username == "" {
+			username = githubNoreplyUsername(m.Email)
+		}
+
+		if username == "" && m.Email != "" {
+			username = lookupGitHubEmailUsername(ctx, chartDirectory, m.Email)
+		}
+
+		maintainers[i].GitHubUsername = username
+	}
+}
@@ -0,0 +1,111 @@
+package helm
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// forgetGitHubEmailLookups forgets the emails looked up by earlier tests
+func forgetGitHubEmailLookups() {
+	githubEmailLookups.Lock()
+	defer githubEmailLookups.Unlock()
+	githubEmailLookups.lookups = nil
+}
+
+func TestDescribeGitHubMaintainers(t *testing.T) {
+	forgetGitHubEmailLookups()
+	searches := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		searches++
+		switch r.URL.Query().Get("q") {
+		case "jane@example.com in:email":
+			w.Write([]byte(`{"total_count": 1, "items": [{"login": "jane-doe"}]}`))
+		case "nobody@example.com in:email":
+			w.Write([]byte(`{"total_count": 0, "items": []}`))
+		default:
+			w.WriteHeader(http.StatusForbidden)
+		}
+	}))
+
+	defer server.Close()
+
+	defer func(apiURL string) { githubAPIURL = apiURL }(githubAPIURL)
+	githubAPIURL = server.URL
+
+	maintainers := []ChartMetaMaintainer{
+		{Name: "John Norwood", Url: "https://github.com/norwoodj/"},
+		{Name: "Bot", Email: "1234+helm-bot@users.noreply.github.com"},
+		{Name: "Jane", Email: "jane@example.com"},
+		{Name: "Nobody", Email: "nobody@example.com", Url: "https://example.com/nobody"},
+		{Name: "Limited", Email: "limited@example.com"},
+		{Name: "Jane again", Email: "jane@example.com"},
+	}
+
+	describeGitHubMaintainers(context.Background(), "app", maintainers)
+
+	assert.Equal(t, "norwoodj", maintainers[0].GitHubUsername)
+	assert.Equal(t, "helm-bot", maintainers[1].GitHubUsername)
+	assert.Equal(t, "jane-doe", maintainers[2].GitHubUsername)
+	assert.Equal(t, "", maintainers[3].GitHubUsername)
+	assert.Equal(t, "", maintainers[4].GitHubUsername)
+	assert.Equal(t, "jane-doe", maintainers[5].GitHubUsername)
+	assert.Equal(t, 3, searches)
+}
+
+func TestLookupGitHubEmailUsernameConcurrently(t *testing.T) {
+	forgetGitHubEmailLookups()
+	var searches int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&searches, 1)
+		<-release
+		w.Write([]byte(`{"total_count": 1, "items": [{"login": "sam"}]}`))
+	}))
+
+	defer server.Close()
+
+	defer func(apiURL string) { githubAPIURL = apiURL }(githubAPIURL)
+	githubAPIURL = server.URL
+
+	usernames := make([]string, 4)
+	waitGroup := sync.WaitGroup{}
+	for i := range usernames {
+		waitGroup.Add(1)
+		go func(i int) {
+			defer waitGroup.Done()
+			usernames[i] = lookupGitHubEmailUsername(context.Background(), "app", "sam@example.com")
+		}(i)
+	}
+
+	for atomic.LoadInt32(&searches) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	// Waiting on the lookup under way is given up on once the context is done, without holding up the lookup
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	assert.Equal(t, "", lookupGitHubEmailUsername(ctx, "app", "sam@example.com"))
+	assert.True(t, time.Since(start) < 5*time.Second)
+
+	close(release)
+	waitGroup.Wait()
+
+	assert.Equal(t, []string{"sam", "sam", "sam", "sam"}, usernames)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&searches))
+}
+
+func TestGitHubProfileUsername(t *testing.T) {
+	assert.Equal(t, "norwoodj", githubProfileUsername("https://github.com/norwoodj"))
+	assert.Equal(t, "", githubProfileUsername("https://github.com/norwoodj/helm-docs"))
+	assert.Equal(t, "", githubProfileUsername("https://gitlab.com/norwoodj"))
+	assert.Equal(t, "", githubProfileUsername("github.com/norwoodj"))
+}
@@ -0,0 +1,214 @@
+package helm
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v2"
+)
+
+type helmfileRepository struct {
+	Name string
+	URL  string `yaml:"url"`
+}
+
+type helmfileSetValue struct {
+	Name  string
+	Value interface{}
+}
+
+type helmfileRelease struct {
+	Name      string
+	Namespace string
+	Chart     string
+	Version   string
+	Installed *bool
+	Labels    map[string]string
+
+	// Each item is either the path of a values file relative to the helmfile, or a map of values given inline
+	Values []interface{}
+	Set    []helmfileSetValue
+}
+
+type helmfileDocument struct {
+	Repositories []helmfileRepository
+	Releases     []helmfileRelease
+}
+
+// HelmfileReleaseValue is a value a helmfile release overrides the defaults of its chart with, along with where it's
+// set: a values file, inline values or a set entry
+type HelmfileReleaseValue struct {
+	Key    string
+	Value  string
+	Source string
+}
+
+// HelmfileRelease is a release of a chart declared in a helmfile, with the values it's installed with flattened into a
+// list of leaf values sorted by key. The repository is the url of the helm repository the chart is installed from, when
+// the chart is given as <repository>/<chart> with a repository declared in the helmfile
+type HelmfileRelease struct {
+	Name       string
+	Namespace  string
+	Chart      string
+	Version    string
+	Repository string
+	Installed  bool
+	Labels     map[string]string
+	Values     []HelmfileReleaseValue
+}
+
+// parseHelmfileDocuments parses the yaml documents of a helmfile, separated by ---, e.g. those declaring environments
+// apart from releases, collecting the repositories and releases of them all
+func parseHelmfileDocuments(helmfileContents []byte) (helmfileDocument, error) {
+	var helmfile helmfileDocument
+	decoder := yaml.NewDecoder(bytes.NewReader(helmfileContents))
+
+	for {
+		var document helmfileDocument
+		err := decoder.Decode(&document)
+		if err == io.EOF {
+			return helmfile, nil
+		}
+
+		if err != nil {
+			return helmfile, err
+		}
+
+		helmfile.Repositories = append(helmfile.Repositories, document.Repositories...)
+		helmfile.Releases = append(helmfile.Releases, document.Releases...)
+	}
+}
+
+// readHelmfileValuesFile reads a values file of a release, relative to the helmfile. Templated values files and those
+// which don't exist, e.g. because their paths are templated, aren't rendered by helm-docs, and so are left out
+func readHelmfileValuesFile(helmfileDirectory string, valuesFile string) (map[interface{}]interface{}, bool, error) {
+	if strings.HasSuffix(valuesFile, ".gotmpl") || strings.Contains(valuesFile, "{{") {
+		return nil, false, nil
+	}
+
+	valuesPath := valuesFile
+	if !filepath.IsAbs(valuesPath) {
+		valuesPath = filepath.Join(helmfileDirectory, valuesFile)
+	}
+
+	valuesFileContents, err := ioutil.ReadFile(valuesPath)
+	if os.IsNotExist(err) {
+		return nil, false, nil
+	}
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	values, err := parseValues(valuesFileContents)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to parse values file %s: %s", valuesPath, err)
+	}
+
+	return values, true, nil
+}
+
+// flattenHelmfileReleaseValues flattens the values of a release in the order helmfile merges them, with later values
+// files, inline values and finally set entries overriding the values of the same keys before them
+func flattenHelmfileReleaseValues(helmfileDirectory string, release helmfileRelease) ([]HelmfileReleaseValue, error) {
+	values := make(map[string]HelmfileReleaseValue)
+
+	for _, v := range release.Values {
+		leaves := make(map[string]interface{})
+		source := "inline"
+
+		switch valuesItem := v.(type) {
+		case string:
+			valuesFile, ok, err := readHelmfileValuesFile(helmfileDirectory, valuesItem)
+			if err != nil {
+				return nil, err
+			}
+
+			if !ok {
+				log.Warnf("Not documenting values file %s of release %s, which is templated or doesn't exist", valuesItem, release.Name)
+				continue
+			}
+
+			flattenValues("", valuesFile, nil, leaves)
+			source = valuesItem
+		case map[interface{}]interface{}:
+			flattenValues("", valuesItem, nil, leaves)
+		default:
+			log.Warnf("Not documenting values %v of release %s, which are neither a file nor a map", valuesItem, release.Name)
+			continue
+		}
+
+		for key, value := range leaves {
+			values[key] = HelmfileReleaseValue{Key: key, Value: jsonEncodeValue(value), Source: source}
+		}
+	}
+
+	for _, s := range release.Set {
+		values[s.Name] = HelmfileReleaseValue{Key: s.Name, Value: jsonEncodeValue(s.Value), Source: "set"}
+	}
+
+	flattenedValues := make([]HelmfileReleaseValue, 0, len(values))
+	for _, v := range values {
+		flattenedValues = append(flattenedValues, v)
+	}
+
+	sort.Slice(flattenedValues, func(i, j int) bool {
+		return flattenedValues[i].Key < flattenedValues[j].Key
+	})
+
+	return flattenedValues, nil
+}
+
+// helmfileChartRepository finds the url of the repository a release's chart is installed from, if it's given as
+// <repository>/<chart> with a repository declared in the helmfile
+func helmfileChartRepository(chart string, repositories []helmfileRepository) string {
+	for _, r := range repositories {
+		if strings.HasPrefix(chart, r.Name+"/") {
+			return r.URL
+		}
+	}
+
+	return ""
+}
+
+// ParseHelmfile parses the releases declared in a helmfile. Helmfiles which are themselves templated, e.g.
+// helmfile.yaml.gotmpl, must be rendered to plain yaml first, e.g. with helmfile build
+func ParseHelmfile(helmfilePath string) ([]HelmfileRelease, error) {
+	helmfileContents, err := ioutil.ReadFile(helmfilePath)
+	if err != nil {
+		return nil, err
+	}
+
+	helmfile, err := parseHelmfileDocuments(helmfileContents)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse helmfile %s: %s", helmfilePath, err)
+	}
+
+	releases := make([]HelmfileRelease, 0, len(helmfile.Releases))
+	for _, r := range helmfile.Releases {
+		values, err := flattenHelmfileReleaseValues(filepath.Dir(helmfilePath), r)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read values of release %s in helmfile %s: %s", r.Name, helmfilePath, err)
+		}
+
+		releases = append(releases, HelmfileRelease{
+			Name:       r.Name,
+			Namespace:  r.Namespace,
+			Chart:      r.Chart,
+			Version:    r.Version,
+			Repository: helmfileChartRepository(r.Chart, helmfile.Repositories),
+			Installed:  r.Installed == nil || *r.Installed,
+			Labels:     r.Labels,
+			Values:     values,
+		})
+	}
+
+	return releases, nil
+}
@@ -0,0 +1,66 @@
+package helm
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseHelmfile(t *testing.T) {
+	helmfileDirectory, err := ioutil.TempDir("", "helm-docs-test")
+	assert.Nil(t, err)
+	defer os.RemoveAll(helmfileDirectory)
+
+	helmfile := `environments:
+  default: {}
+---
+repositories:
+  - name: bitnami
+    url: https://charts.bitnami.com/bitnami
+releases:
+  - name: redis
+    namespace: cache
+    chart: bitnami/redis
+    version: 17.0.0
+    values:
+      - redis.yaml
+      - "{{ .Environment.Name }}.yaml"
+      - auth:
+          enabled: false
+    set:
+      - name: replica.replicaCount
+        value: 2
+  - name: app
+    chart: ./charts/app
+    installed: false
+`
+
+	assert.Nil(t, ioutil.WriteFile(path.Join(helmfileDirectory, "helmfile.yaml"), []byte(helmfile), 0644))
+	assert.Nil(t, ioutil.WriteFile(path.Join(helmfileDirectory, "redis.yaml"), []byte("auth:\n  enabled: true\nreplica:\n  replicaCount: 1\n"), 0644))
+
+	releases, err := ParseHelmfile(path.Join(helmfileDirectory, "helmfile.yaml"))
+	assert.Nil(t, err)
+	assert.Equal(t, []HelmfileRelease{
+		{
+			Name:       "redis",
+			Namespace:  "cache",
+			Chart:      "bitnami/redis",
+			Version:    "17.0.0",
+			Repository: "https://charts.bitnami.com/bitnami",
+			Installed:  true,
+			Values: []HelmfileReleaseValue{
+				{Key: "auth.enabled", Value: "false", Source: "inline"},
+				{Key: "replica.replicaCount", Value: "2", Source: "set"},
+			},
+		},
+		{
+			Name:      "app",
+			Chart:     "./charts/app",
+			Installed: false,
+			Values:    []HelmfileReleaseValue{},
+		},
+	}, releases)
+}
@@ -0,0 +1,202 @@
+package helm
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v2"
+	yaml3 "gopkg.in/yaml.v3"
+)
+
+// ChartImage describes a container image used by a chart
+type ChartImage struct {
+	Repository string
+	Tag        string
+	PullPolicy string
+}
+
+// splitImageReference splits an image reference as given in a container spec, e.g. "nginx:1.19" or
+// "nginx@sha256:...", into its repository and tag or digest
+func splitImageReference(image string) (string, string) {
+	if i := strings.Index(image, "@"); i >= 0 {
+		return image[:i], image[i+1:]
+	}
+
+	// A colon before the last slash separates the registry's port, not the tag
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		return image[:i], image[i+1:]
+	}
+
+	return image, ""
+}
+
+// findManifestImages walks a rendered manifest for container specs, i.e. maps with an image and, like every
+// container, a name, wherever they are nested, so that images in custom resources are found too
+func findManifestImages(node interface{}, images []ChartImage) []ChartImage {
+	switch n := node.(type) {
+	case map[interface{}]interface{}:
+		image, isImage := n["image"].(string)
+		_, hasName := n["name"]
+
+		if isImage && hasName {
+			repository, tag := splitImageReference(image)
+			pullPolicy, _ := n["imagePullPolicy"].(string)
+			images = append(images, ChartImage{Repository: repository, Tag: tag, PullPolicy: pullPolicy})
+		}
+
+		for _, v := range n {
+			images = findManifestImages(v, images)
+		}
+	case []interface{}:
+		for _, v := range n {
+			images = findManifestImages(v, images)
+		}
+	}
+
+	return images
+}
+
+func parseManifestImages(manifests []byte) ([]ChartImage, error) {
+	images := make([]ChartImage, 0)
+	decoder := yaml.NewDecoder(bytes.NewReader(manifests))
+
+	for {
+		var manifest interface{}
+		err := decoder.Decode(&manifest)
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		images = findManifestImages(manifest, images)
+	}
+
+	return uniqueSortedImages(images), nil
+}
+
+// valuesMapFields returns the values of a map in a values file by their keys, including those merged into it with
+// merge keys, which the map's own keys override
+func valuesMapFields(node *yaml3.Node) map[string]*yaml3.Node {
+	fields := make(map[string]*yaml3.Node)
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		keyNode, valueNode := node.Content[i], node.Content[i+1]
+		if keyNode.Tag != "!!merge" && keyNode.Value != "<<" {
+			fields[keyNode.Value] = valueNode
+			continue
+		}
+
+		if valueNode.Kind == yaml3.AliasNode && valueNode.Alias != nil && valueNode.Alias.Kind == yaml3.MappingNode {
+			for k, v := range valuesMapFields(valueNode.Alias) {
+				if _, ok := fields[k]; !ok {
+					fields[k] = v
+				}
+			}
+		}
+	}
+
+	return fields
+}
+
+// scalarValue returns the value of a scalar node as it's written in the values file, e.g. "1.10" for a tag of 1.10,
+// which would be read as the number 1.1, or "" for any other node
+func scalarValue(node *yaml3.Node) string {
+	if node == nil || node.Kind != yaml3.ScalarNode || node.Tag == "!!null" {
+		return ""
+	}
+
+	return node.Value
+}
+
+// findValuesImages walks the node tree of a chart's values file for maps following the common convention for
+// configuring images, e.g. "image: {registry: docker.io, repository: nginx, tag: 1.19, pullPolicy: Always}", with the
+// names of the repository and tag keys configurable. Tags are read as they're written, as they're often numbers
+func findValuesImages(node *yaml3.Node, repositoryKey string, tagKey string, images []ChartImage) []ChartImage {
+	switch node.Kind {
+	case yaml3.DocumentNode, yaml3.SequenceNode:
+		for _, n := range node.Content {
+			images = findValuesImages(n, repositoryKey, tagKey, images)
+		}
+	case yaml3.MappingNode:
+		fields := valuesMapFields(node)
+
+		if repository := scalarValue(fields[repositoryKey]); repository != "" {
+			if registry := scalarValue(fields["registry"]); registry != "" {
+				repository = registry + "/" + repository
+			}
+
+			images = append(images, ChartImage{
+				Repository: repository,
+				Tag:        scalarValue(fields[tagKey]),
+				PullPolicy: scalarValue(fields["pullPolicy"]),
+			})
+		}
+
+		for i := 1; i < len(node.Content); i += 2 {
+			images = findValuesImages(node.Content[i], repositoryKey, tagKey, images)
+		}
+	}
+
+	return images
+}
+
+func parseValuesImages(chartSettings *viper.Viper, files chartFiles) ([]ChartImage, error) {
+	repositoryKey := chartSettings.GetString("image-repository-key")
+	if repositoryKey == "" {
+		repositoryKey = "repository"
+	}
+
+	tagKey := chartSettings.GetString("image-tag-key")
+	if tagKey == "" {
+		tagKey = "tag"
+	}
+
+	valuesFileContents, err := files.readFile("values.yaml")
+	if os.IsNotExist(err) {
+		return make([]ChartImage, 0), nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	valuesNode, err := parseValuesNode(valuesFileContents)
+	if err != nil {
+		return nil, err
+	}
+
+	return uniqueSortedImages(findValuesImages(valuesNode, repositoryKey, tagKey, make([]ChartImage, 0))), nil
+}
+
+func uniqueSortedImages(images []ChartImage) []ChartImage {
+	seen := make(map[ChartImage]bool)
+	uniqueImages := make([]ChartImage, 0, len(images))
+
+	for _, i := range images {
+		if !seen[i] {
+			seen[i] = true
+			uniqueImages = append(uniqueImages, i)
+		}
+	}
+
+	sort.Slice(uniqueImages, func(i, j int) bool {
+		if uniqueImages[i].Repository != uniqueImages[j].Repository {
+			return uniqueImages[i].Repository < uniqueImages[j].Repository
+		}
+
+		if uniqueImages[i].Tag != uniqueImages[j].Tag {
+			return uniqueImages[i].Tag < uniqueImages[j].Tag
+		}
+
+		return uniqueImages[i].PullPolicy < uniqueImages[j].PullPolicy
+	})
+
+	return uniqueImages
+}
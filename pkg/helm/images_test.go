@@ -0,0 +1,84 @@
+package helm
+
+import (
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitImageReference(t *testing.T) {
+	for image, expected := range map[string][2]string{
+		"nginx":                          {"nginx", ""},
+		"nginx:1.19":                     {"nginx", "1.19"},
+		"registry.local:5000/nginx":      {"registry.local:5000/nginx", ""},
+		"registry.local:5000/nginx:1.19": {"registry.local:5000/nginx", "1.19"},
+		"nginx@sha256:abc":               {"nginx", "sha256:abc"},
+	} {
+		repository, tag := splitImageReference(image)
+		assert.Equal(t, expected, [2]string{repository, tag}, image)
+	}
+}
+
+func TestParseManifestImages(t *testing.T) {
+	images, err := parseManifestImages([]byte(`
+kind: Deployment
+spec:
+  template:
+    spec:
+      initContainers:
+        - name: init
+          image: busybox
+      containers:
+        - name: app
+          image: nginx:1.19
+          imagePullPolicy: Always
+---
+kind: Deployment
+spec:
+  template:
+    spec:
+      containers:
+        - name: app
+          image: nginx:1.19
+          imagePullPolicy: Always
+`))
+
+	assert.Nil(t, err)
+	assert.Equal(t, []ChartImage{
+		{Repository: "busybox"},
+		{Repository: "nginx", Tag: "1.19", PullPolicy: "Always"},
+	}, images)
+}
+
+func TestParseValuesImages(t *testing.T) {
+	files := archiveChartFiles{archivePath: "app.tgz", files: map[string][]byte{"values.yaml": []byte(`
+image:
+  registry: docker.io
+  repository: bitnami/nginx
+  tag: 1.10
+  pullPolicy: IfNotPresent
+metrics:
+  image: &exporter
+    repository: bitnami/nginx-exporter
+    tag: 0.10
+sidecars:
+  - image:
+      <<: *exporter
+      tag: 0.11.0
+service:
+  type: ClusterIP
+`)}}
+
+	images, err := parseValuesImages(viper.GetViper(), files)
+	assert.Nil(t, err)
+	assert.Equal(t, []ChartImage{
+		{Repository: "bitnami/nginx-exporter", Tag: "0.10"},
+		{Repository: "bitnami/nginx-exporter", Tag: "0.11.0"},
+		{Repository: "docker.io/bitnami/nginx", Tag: "1.10", PullPolicy: "IfNotPresent"},
+	}, images)
+
+	images, err = parseValuesImages(viper.GetViper(), archiveChartFiles{archivePath: "app.tgz", files: map[string][]byte{}})
+	assert.Nil(t, err)
+	assert.Equal(t, []ChartImage{}, images)
+}
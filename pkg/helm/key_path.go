@@ -0,0 +1,86 @@
+package helm
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var keyPathEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+var yamlMapKeyRegex = regexp.MustCompile(`^("(?:[^"\\]|\\.)*"|'(?:[^']|'')*'|[^\s#"'][^:#]*?)\s*:(\s+|$)`)
+
+func FormatListKeyPath(prefix string, index int) string {
+	return fmt.Sprintf("%s[%d]", prefix, index)
+}
+
+// FormatObjectKeyPath appends a key to a key path. Keys which contain whitespace, or characters with a meaning in key
+// paths, are quoted, with any quotes or backslashes within them escaped
+func FormatObjectKeyPath(prefix string, key string) string {
+	var escapedKey string
+	var nextPrefix string
+
+	if key == "" || strings.ContainsAny(key, ".[]\"\\ \t") {
+		escapedKey = fmt.Sprintf(`"%s"`, keyPathEscaper.Replace(key))
+	} else {
+		escapedKey = key
+	}
+
+	if prefix != "" {
+		nextPrefix = fmt.Sprintf("%s.%s", prefix, escapedKey)
+	} else {
+		nextPrefix = fmt.Sprintf("%s", escapedKey)
+	}
+
+	return nextPrefix
+}
+
+// ParentKeyPath strips the last element off of a key path, ignoring dots and brackets within quoted key elements
+func ParentKeyPath(key string) string {
+	inQuotes := false
+	escaped := false
+	lastSeparator := 0
+
+	for i, c := range key {
+		switch {
+		case escaped:
+			escaped = false
+		case inQuotes && c == '\\':
+			escaped = true
+		case c == '"':
+			inQuotes = !inQuotes
+		case !inQuotes && (c == '.' || c == '['):
+			lastSeparator = i
+		}
+	}
+
+	return key[:lastSeparator]
+}
+
+// lastKeyPathElement returns the key of the last element of a key path, unquoted if it's quoted
+func lastKeyPathElement(key string) string {
+	lastElement := strings.TrimPrefix(key[len(ParentKeyPath(key)):], ".")
+
+	if strings.HasPrefix(lastElement, `"`) {
+		if unquotedElement, err := strconv.Unquote(lastElement); err == nil {
+			return unquotedElement
+		}
+	}
+
+	return lastElement
+}
+
+func unquoteYamlKey(key string) string {
+	switch {
+	case strings.HasPrefix(key, `"`):
+		if unquotedKey, err := strconv.Unquote(key); err == nil {
+			return unquotedKey
+		}
+
+		return key[1 : len(key)-1]
+	case strings.HasPrefix(key, "'"):
+		return strings.ReplaceAll(key[1:len(key)-1], "''", "'")
+	}
+
+	return key
+}
@@ -0,0 +1,88 @@
+package helm
+
+import (
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ChartNamedTemplate is a named template defined in one of a chart's template helper files, e.g. templates/_helpers.tpl,
+// described by the comment directly preceding its define action, if any
+type ChartNamedTemplate struct {
+	Name        string
+	Description string
+	File        string
+}
+
+const libraryChartType = "library"
+
+// IsLibraryChart returns whether the chart is a library chart, which only provides named templates to other charts and
+// can't be installed itself
+func (c ChartMeta) IsLibraryChart() bool {
+	return c.Type == libraryChartType
+}
+
+// Matches a define action along with a template comment separated from it by nothing but whitespace. The comment may
+// not contain the end of a comment, so that a comment followed by anything but a define isn't matched with a later one
+var namedTemplateRegex = regexp.MustCompile(`(?:{{-?\s*/\*((?:[^*]|\*[^/])*)\*/\s*-?}}\s*)?{{-?\s*define\s+"([^"]+)"\s*-?}}`)
+
+func parseNamedTemplates(templateFile string, contents []byte) []ChartNamedTemplate {
+	namedTemplates := make([]ChartNamedTemplate, 0)
+
+	for _, match := range namedTemplateRegex.FindAllStringSubmatch(string(contents), -1) {
+		descriptionLines := strings.Split(strings.TrimSpace(match[1]), "\n")
+		for i, l := range descriptionLines {
+			descriptionLines[i] = strings.TrimSpace(l)
+		}
+
+		namedTemplates = append(namedTemplates, ChartNamedTemplate{
+			Name:        match[2],
+			Description: strings.Join(descriptionLines, "\n"),
+			File:        templateFile,
+		})
+	}
+
+	return namedTemplates
+}
+
+// isTemplateHelperFile returns whether a template file is one of the files helm doesn't render as a manifest, by
+// convention those with names starting with an underscore, which hold named templates
+func isTemplateHelperFile(templateFile string) bool {
+	return strings.HasPrefix(path.Base(templateFile), "_") && strings.HasSuffix(templateFile, ".tpl")
+}
+
+func sortNamedTemplates(namedTemplates []ChartNamedTemplate) {
+	sort.SliceStable(namedTemplates, func(i, j int) bool {
+		if namedTemplates[i].Name != namedTemplates[j].Name {
+			return namedTemplates[i].Name < namedTemplates[j].Name
+		}
+
+		return namedTemplates[i].File < namedTemplates[j].File
+	})
+}
+
+// parseChartNamedTemplates finds the named templates defined in the template helper files of a chart, sorted by name
+func parseChartNamedTemplates(files chartFiles) ([]ChartNamedTemplate, error) {
+	templateFiles, err := files.listFiles("templates")
+	if err != nil {
+		return nil, err
+	}
+
+	namedTemplates := make([]ChartNamedTemplate, 0)
+	for _, f := range templateFiles {
+		if !isTemplateHelperFile(f) {
+			continue
+		}
+
+		contents, err := files.readFile(f)
+		if err != nil {
+			return nil, err
+		}
+
+		namedTemplates = append(namedTemplates, parseNamedTemplates(f, contents)...)
+	}
+
+	sortNamedTemplates(namedTemplates)
+	return namedTemplates, nil
+}
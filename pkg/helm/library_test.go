@@ -0,0 +1,34 @@
+package helm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseNamedTemplates(t *testing.T) {
+	namedTemplates := parseNamedTemplates("templates/_names.tpl", []byte(`{{/* vim: set filetype=mustache: */}}
+{{/*
+Expand the name of the chart.
+  Truncated to 63 characters.
+*/}}
+{{- define "common.names.name" -}}
+{{- default .Chart.Name .Values.nameOverride | trunc 63 | trimSuffix "-" -}}
+{{- end -}}
+
+{{- define "common.names.chart" -}}
+{{- printf "%s-%s" .Chart.Name .Chart.Version -}}
+{{- end -}}
+
+{{- /* Renders a value that contains template actions, e.g. {{ include "common.tplvalues.render" . }} */ -}}
+{{ define "common.tplvalues.render" }}
+{{- tpl .value .context }}
+{{- end }}
+`))
+
+	assert.Equal(t, []ChartNamedTemplate{
+		{Name: "common.names.name", Description: "Expand the name of the chart.\nTruncated to 63 characters.", File: "templates/_names.tpl"},
+		{Name: "common.names.chart", Description: "", File: "templates/_names.tpl"},
+		{Name: "common.tplvalues.render", Description: `Renders a value that contains template actions, e.g. {{ include "common.tplvalues.render" . }}`, File: "templates/_names.tpl"},
+	}, namedTemplates)
+}
@@ -0,0 +1,87 @@
+package helm
+
+import (
+	"os"
+	"regexp"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// ChartLicense describes the license file found in a chart's directory. ID is the SPDX identifier of the license, or ""
+// if it couldn't be identified
+type ChartLicense struct {
+	File string
+	ID   string
+}
+
+var licenseFileNames = []string{"LICENSE", "LICENSE.md", "LICENSE.txt", "LICENCE", "COPYING"}
+
+var spdxIdentifierRegex = regexp.MustCompile(`SPDX-License-Identifier:\s*([A-Za-z0-9.+-]+)`)
+
+// licenseHeuristics identify licenses by phrases from their text, checked in order so that more specific licenses are
+// matched before the ones whose text they contain (e.g. the LGPL before the GPL)
+var licenseHeuristics = []struct {
+	id      string
+	phrases []string
+}{
+	{"AGPL-3.0", []string{"GNU AFFERO GENERAL PUBLIC LICENSE", "Version 3"}},
+	{"LGPL-3.0", []string{"GNU LESSER GENERAL PUBLIC LICENSE", "Version 3"}},
+	{"LGPL-2.1", []string{"GNU LESSER GENERAL PUBLIC LICENSE", "Version 2.1"}},
+	{"GPL-3.0", []string{"GNU GENERAL PUBLIC LICENSE", "Version 3"}},
+	{"GPL-2.0", []string{"GNU GENERAL PUBLIC LICENSE", "Version 2"}},
+	{"Apache-2.0", []string{"Apache License", "Version 2.0"}},
+	{"MPL-2.0", []string{"Mozilla Public License", "Version 2.0"}},
+	{"BSD-3-Clause", []string{"Redistribution and use in source and binary forms", "Neither the name"}},
+	{"BSD-2-Clause", []string{"Redistribution and use in source and binary forms"}},
+	{"MIT", []string{"Permission is hereby granted, free of charge"}},
+	{"ISC", []string{"Permission to use, copy, modify, and/or distribute this software for any purpose"}},
+	{"Unlicense", []string{"This is free and unencumbered software released into the public domain"}},
+}
+
+func identifyLicense(licenseText string) string {
+	if match := spdxIdentifierRegex.FindStringSubmatch(licenseText); len(match) > 1 {
+		return match[1]
+	}
+
+	// Line breaks in license texts fall in different places depending on who wrapped them
+	normalizedText := strings.Join(strings.Fields(licenseText), " ")
+
+	for _, h := range licenseHeuristics {
+		matched := true
+		for _, p := range h.phrases {
+			if !strings.Contains(normalizedText, p) {
+				matched = false
+				break
+			}
+		}
+
+		if matched {
+			return h.id
+		}
+	}
+
+	return ""
+}
+
+func parseChartLicenseFile(files chartFiles) (ChartLicense, error) {
+	for _, f := range licenseFileNames {
+		licenseText, err := files.readFile(f)
+		if os.IsNotExist(err) {
+			continue
+		}
+
+		if err != nil {
+			return ChartLicense{}, err
+		}
+
+		license := ChartLicense{File: f, ID: identifyLicense(string(licenseText))}
+		if license.ID == "" {
+			log.Warnf("Could not identify the license in %s", files.path(f))
+		}
+
+		return license, nil
+	}
+
+	return ChartLicense{}, nil
+}
@@ -0,0 +1,17 @@
+package helm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdentifyLicense(t *testing.T) {
+	assert.Equal(t, "Apache-2.0", identifyLicense("\n                                 Apache License\n                           Version 2.0, January 2004\n"))
+	assert.Equal(t, "MIT", identifyLicense("MIT License\n\nPermission is hereby granted, free\nof charge, to any person"))
+	assert.Equal(t, "LGPL-3.0", identifyLicense("GNU LESSER GENERAL PUBLIC LICENSE\nVersion 3, 29 June 2007"))
+	assert.Equal(t, "GPL-3.0", identifyLicense("GNU GENERAL PUBLIC LICENSE\nVersion 3, 29 June 2007"))
+	assert.Equal(t, "BSD-2-Clause", identifyLicense("Redistribution and use in source and binary forms, with or without"))
+	assert.Equal(t, "EPL-2.0", identifyLicense("SPDX-License-Identifier: EPL-2.0\nEclipse Public License"))
+	assert.Equal(t, "", identifyLicense("All rights reserved."))
+}
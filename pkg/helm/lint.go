@@ -0,0 +1,231 @@
+package helm
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/norwoodj/helm-docs/pkg/settings"
+)
+
+// ValuesLintFinding is a problem with the comments or keys of a values file, found on the given line
+type ValuesLintFinding struct {
+	File    string
+	Line    int
+	Message string
+}
+
+func (f ValuesLintFinding) String() string {
+	return fmt.Sprintf("%s:%d: %s", f.File, f.Line, f.Message)
+}
+
+// valuesLintRegexes holds the regular expressions matching comment lines of any form, and comment lines that look like
+// they were meant to be description comments, i.e. that contain the description separator after at most a key path but
+// with any spacing. Lines of dashes aren't taken for description comments
+type valuesLintRegexes struct {
+	comment              *regexp.Regexp
+	malformedDescription *regexp.Regexp
+}
+
+func newValuesLintRegexes(commentPrefix string, descriptionSeparator string) (valuesLintRegexes, error) {
+	if commentPrefix == "" {
+		commentPrefix = defaultCommentPrefix
+	}
+
+	if descriptionSeparator == "" {
+		descriptionSeparator = defaultDescriptionSeparator
+	}
+
+	comment, err := regexp.Compile(fmt.Sprintf("^\\s*(?:%s)", commentPrefix))
+	if err != nil {
+		return valuesLintRegexes{}, fmt.Errorf("invalid values comment format: %s", err)
+	}
+
+	malformedDescription, err := regexp.Compile(fmt.Sprintf("^\\s*(?:%s)\\s*(?:[^-\\s]\\S*?\\s*)?(?:%s)(?:[^-]|$)", commentPrefix, descriptionSeparator))
+	if err != nil {
+		return valuesLintRegexes{}, fmt.Errorf("invalid values comment format: %s", err)
+	}
+
+	return valuesLintRegexes{comment: comment, malformedDescription: malformedDescription}, nil
+}
+
+// isValuesAnnotation returns whether a comment line is one of the @ annotations that may follow a description comment
+func isValuesAnnotation(commentRegexes valuesCommentRegexes, line string) bool {
+	for _, r := range []*regexp.Regexp{
+		commentRegexes.defaultValue,
+		commentRegexes.required,
+		commentRegexes.sensitive,
+		commentRegexes.collapse,
+		commentRegexes.typeOverride,
+		commentRegexes.allowedValues,
+		commentRegexes.section,
+	} {
+		if r.MatchString(line) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Matches the line number in the errors of the yaml parser, e.g. "yaml: line 3: mapping values are not allowed here"
+var yamlErrorLineRegex = regexp.MustCompile(`line (\d+)`)
+
+// lintValues checks the lines of a values file for malformed description comments, @ annotations that don't follow a
+// description comment, descriptions of keys the file doesn't define, and keys defined more than once. The values the
+// file defines, and the lines they're defined on, are found by the yaml parser, as they are when parsing descriptions
+func lintValues(valuesFile io.Reader, valuesPath string) ([]ValuesLintFinding, error) {
+	commentPrefix := settings.GetString("comment-prefix")
+	descriptionSeparator := settings.GetString("description-separator")
+
+	commentRegexes, err := newValuesCommentRegexes(commentPrefix, descriptionSeparator)
+	if err != nil {
+		return nil, err
+	}
+
+	lintRegexes, err := newValuesLintRegexes(commentPrefix, descriptionSeparator)
+	if err != nil {
+		return nil, err
+	}
+
+	findings := make([]ValuesLintFinding, 0)
+	addFinding := func(line int, format string, args ...interface{}) {
+		findings = append(findings, ValuesLintFinding{File: valuesPath, Line: line, Message: fmt.Sprintf(format, args...)})
+	}
+
+	valuesFileContents, err := ioutil.ReadAll(valuesFile)
+	if err != nil {
+		return nil, err
+	}
+
+	valuesEntries, err := parseValuesEntries(valuesFileContents)
+	if err != nil {
+		lineNumber := 1
+		if match := yamlErrorLineRegex.FindStringSubmatch(err.Error()); match != nil {
+			lineNumber, _ = strconv.Atoi(match[1])
+		}
+
+		addFinding(lineNumber, "%s", err)
+		return findings, nil
+	}
+
+	// The key paths of the values defined on each line, of which there may be several, e.g. on "- name: nginx"
+	lineKeyPaths := make(map[int][]string)
+	for _, l := range valuesEntries {
+		if l.isKey {
+			lineKeyPaths[l.lineNumber] = append(lineKeyPaths[l.lineNumber], l.keyPath)
+		}
+	}
+
+	keyLineNumbers := make(map[string]int)
+	describedKeyLineNumbers := make(map[string]int)
+	commentedOutKeys := make(map[string]bool)
+	parseBitnamiParams := settings.GetBool("bitnami-params")
+	parseCommentedOutValues := settings.GetBool("commented-out-values")
+	scanner := bufio.NewScanner(bytes.NewReader(valuesFileContents))
+	describedKey := ""
+
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		currentLine := scanner.Text()
+
+		if keyPaths, ok := lineKeyPaths[lineNumber]; ok {
+			for _, keyPath := range keyPaths {
+				if firstLineNumber, ok := keyLineNumbers[keyPath]; ok {
+					addFinding(lineNumber, "duplicate key %s, first defined on line %d", keyPath, firstLineNumber)
+				} else {
+					keyLineNumbers[keyPath] = lineNumber
+				}
+			}
+
+			describedKey = ""
+			continue
+		}
+
+		// Any line that isn't a comment, e.g. a blank line, ends the description comment preceding it
+		if !lintRegexes.comment.MatchString(currentLine) {
+			describedKey = ""
+			continue
+		}
+
+		if describedKey == "" {
+			// Annotations would otherwise match as descriptions of keys named after them, e.g. "@default"
+			if isValuesAnnotation(commentRegexes, currentLine) {
+				addFinding(lineNumber, "annotation does not follow a description comment")
+				continue
+			}
+
+			if match := commentRegexes.description.FindStringSubmatch(currentLine); len(match) > 2 {
+				describedKey = match[1]
+				describedKeyLineNumbers[describedKey] = lineNumber
+				continue
+			}
+
+			if parseBitnamiParams {
+				if match := commentRegexes.bitnamiParam.FindStringSubmatch(currentLine); len(match) > 3 {
+					describedKeyLineNumbers[match[1]] = lineNumber
+					continue
+				}
+			}
+
+			if lintRegexes.malformedDescription.MatchString(currentLine) {
+				addFinding(lineNumber, "malformed description comment, the key and description must be separated from the separator by single spaces")
+			}
+
+			continue
+		}
+
+		if parseCommentedOutValues && isCommentedOutKey(commentRegexes, currentLine, describedKey) {
+			commentedOutKeys[describedKey] = true
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	for key, lineNumber := range describedKeyLineNumbers {
+		if _, ok := keyLineNumbers[key]; !ok && !commentedOutKeys[key] {
+			addFinding(lineNumber, "description of key %s, which is not defined", key)
+		}
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		return findings[i].Line < findings[j].Line
+	})
+
+	return findings, nil
+}
+
+// LintChartValuesFiles lints the values file of a chart, along with any extra values files it has
+func LintChartValuesFiles(chartDirectory string) ([]ValuesLintFinding, error) {
+	findings := make([]ValuesLintFinding, 0)
+
+	for _, valuesFilename := range append([]string{"values.yaml"}, settings.GetStringSlice("extra-values-files")...) {
+		valuesPath := path.Join(chartDirectory, valuesFilename)
+		valuesFile, err := os.Open(valuesPath)
+		if os.IsNotExist(err) {
+			continue
+		}
+
+		if err != nil {
+			return findings, err
+		}
+
+		valuesFindings, err := lintValues(valuesFile, valuesPath)
+		valuesFile.Close()
+		if err != nil {
+			return findings, err
+		}
+
+		findings = append(findings, valuesFindings...)
+	}
+
+	return findings, nil
+}
@@ -0,0 +1,64 @@
+package helm
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLintValues(t *testing.T) {
+	findings, err := lintValues(strings.NewReader(`
+# ---------------------------------
+# Controller settings
+# ---------------------------------
+controller:
+  # controller.replicas -- Number of pods
+  # @default -- two
+  replicas: 2
+
+  # @default -- orphaned
+  #controller.image --The image
+  image: nginx
+  # -- The pull policy
+  pullPolicy: Always
+
+# ingress.enabled -- Whether to create an ingress
+enabled: true
+controller:
+  replicas: 3
+`), "values.yaml")
+
+	assert.Nil(t, err)
+	assert.Equal(t, []ValuesLintFinding{
+		{File: "values.yaml", Line: 10, Message: "annotation does not follow a description comment"},
+		{File: "values.yaml", Line: 11, Message: "malformed description comment, the key and description must be separated from the separator by single spaces"},
+		{File: "values.yaml", Line: 13, Message: "malformed description comment, the key and description must be separated from the separator by single spaces"},
+		{File: "values.yaml", Line: 16, Message: "description of key ingress.enabled, which is not defined"},
+		{File: "values.yaml", Line: 18, Message: "duplicate key controller, first defined on line 5"},
+		{File: "values.yaml", Line: 19, Message: "duplicate key controller.replicas, first defined on line 8"},
+	}, findings)
+}
+
+func TestLintValuesParsedAsYaml(t *testing.T) {
+	// Lines of block scalars and flow style maps aren't keys of their own, however they look
+	findings, err := lintValues(strings.NewReader(`
+# config -- The configuration file
+config: |
+  replicas: 1
+  replicas: 2
+# resources.limits -- The resource limits
+resources: {limits: {cpu: 1}, requests: {cpu: 1}}
+sidecars:
+  - name: proxy
+  - name: logger
+`), "values.yaml")
+
+	assert.Nil(t, err)
+	assert.Equal(t, []ValuesLintFinding{}, findings)
+
+	findings, err = lintValues(strings.NewReader("image:\n  tag: 1.0\n tag: 2.0\n"), "values.yaml")
+	assert.Nil(t, err)
+	assert.Len(t, findings, 1)
+	assert.Contains(t, findings[0].String(), "values.yaml:2: yaml: line 2:")
+}
@@ -0,0 +1,53 @@
+package helm
+
+import (
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+var templateActionRegex = regexp.MustCompile(`(?s){{.*?}}`)
+
+// Marks the place of each stripped action, so that lines made up only of actions can be told apart from blank lines
+const strippedActionMarker = "\x00"
+
+// stripTemplateActions removes go template actions from a template's text, dropping the lines that held nothing but
+// actions entirely, so that only the static text remains
+func stripTemplateActions(text string) string {
+	lines := strings.Split(templateActionRegex.ReplaceAllString(text, strippedActionMarker), "\n")
+	strippedLines := make([]string, 0, len(lines))
+
+	for _, line := range lines {
+		strippedLine := strings.ReplaceAll(line, strippedActionMarker, "")
+		if strippedLine != line && strings.TrimSpace(strippedLine) == "" {
+			continue
+		}
+
+		strippedLines = append(strippedLines, strippedLine)
+	}
+
+	return strings.Join(strippedLines, "\n")
+}
+
+func parseChartNotesFile(chartSettings *viper.Viper, files chartFiles) (string, error) {
+	notes, err := files.readFile("templates/NOTES.txt")
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	return formatChartNotes(chartSettings, notes), nil
+}
+
+func formatChartNotes(chartSettings *viper.Viper, notes []byte) string {
+	if chartSettings.GetBool("strip-notes-template-actions") {
+		return strings.TrimSpace(stripTemplateActions(string(notes)))
+	}
+
+	return strings.TrimSpace(string(notes))
+}
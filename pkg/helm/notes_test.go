@@ -0,0 +1,26 @@
+package helm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStripTemplateActions(t *testing.T) {
+	notes := `Get the application URL by running these commands:
+{{- if .Values.ingress.enabled }}
+  http{{ if .Values.ingress.tls }}s{{ end }}://chart-example.local
+
+{{- end }}
+{{/*
+  a comment
+*/}}
+  kubectl port-forward svc/{{ include "fullname" . }} 8080:80`
+
+	expected := `Get the application URL by running these commands:
+  https://chart-example.local
+
+  kubectl port-forward svc/ 8080:80`
+
+	assert.Equal(t, expected, stripTemplateActions(notes))
+}
@@ -0,0 +1,312 @@
+package helm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/semver"
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v2"
+)
+
+const ociRepositoryScheme = "oci://"
+
+// The media types of the manifests helm pushes charts to OCI registries with, and of their config, which holds the
+// chart's Chart.yaml as JSON
+const (
+	ociManifestMediaType     = "application/vnd.oci.image.manifest.v1+json"
+	helmChartConfigMediaType = "application/vnd.cncf.helm.config.v1+json"
+)
+
+// The scheme OCI registries are reached by. As with helm, registries are only ever reached over https, but for tests
+var ociRegistryScheme = "https"
+
+// The client fetching charts' manifests and configs from OCI registries, which gives up on a registry that stops
+// responding rather than hanging the run
+var ociRegistryClient = &http.Client{Timeout: time.Minute}
+
+// Matches the parameters of a WWW-Authenticate challenge, e.g. realm="https://auth.example.com/token"
+var authenticateParamRegex = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+func isOCIRepository(repository string) bool {
+	return strings.HasPrefix(repository, ociRepositoryScheme)
+}
+
+// ociRegistryCredentials returns the basic auth credentials of a registry stored by helm registry login in helm's
+// registry config, if any. Like helm, the config is read from $HELM_REGISTRY_CONFIG, or from helm's config directory
+func ociRegistryCredentials(registry string) string {
+	configPath := os.Getenv("HELM_REGISTRY_CONFIG")
+	if configPath == "" {
+		configHome := os.Getenv("XDG_CONFIG_HOME")
+		if configHome == "" {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return ""
+			}
+
+			configHome = filepath.Join(home, ".config")
+		}
+
+		configPath = filepath.Join(configHome, "helm", "registry", "config.json")
+	}
+
+	configContents, err := ioutil.ReadFile(configPath)
+	if err != nil {
+		return ""
+	}
+
+	var config struct {
+		Auths map[string]struct {
+			Auth string `json:"auth"`
+		} `json:"auths"`
+	}
+
+	if err := json.Unmarshal(configContents, &config); err != nil {
+		log.Warnf("Error reading helm registry config %s: %s", configPath, err)
+		return ""
+	}
+
+	return config.Auths[registry].Auth
+}
+
+// fetchOCIRegistryToken fetches a bearer token for a request the registry challenged, authenticating with the
+// credentials of helm registry login, if any, and anonymously otherwise
+func fetchOCIRegistryToken(ctx context.Context, registry string, challenge string) (string, error) {
+	params := make(map[string]string)
+	for _, match := range authenticateParamRegex.FindAllStringSubmatch(challenge, -1) {
+		params[match[1]] = match[2]
+	}
+
+	if params["realm"] == "" {
+		return "", fmt.Errorf("registry %s sent an authentication challenge without a realm", registry)
+	}
+
+	query := url.Values{}
+	for _, p := range []string{"service", "scope"} {
+		if params[p] != "" {
+			query.Set(p, params[p])
+		}
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, params["realm"]+"?"+query.Encode(), nil)
+	if err != nil {
+		return "", err
+	}
+
+	if credentials := ociRegistryCredentials(registry); credentials != "" {
+		request.Header.Set("Authorization", "Basic "+credentials)
+	}
+
+	response, err := ociRegistryClient.Do(request)
+	if err != nil {
+		return "", err
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to authenticate with registry %s: %s", registry, response.Status)
+	}
+
+	var token struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+
+	if err := json.NewDecoder(response.Body).Decode(&token); err != nil {
+		return "", err
+	}
+
+	if token.Token != "" {
+		return token.Token, nil
+	}
+
+	return token.AccessToken, nil
+}
+
+// fetchOCIRegistryFile fetches a file of a repository in an OCI registry by its path under the repository, e.g.
+// manifests/1.0.0, answering the registry's authentication challenge if it sends one
+func fetchOCIRegistryFile(ctx context.Context, registry string, repository string, filePath string, accept string) ([]byte, error) {
+	location := fmt.Sprintf("%s://%s/v2/%s/%s", ociRegistryScheme, registry, repository, filePath)
+
+	get := func(authorization string) (*http.Response, error) {
+		request, err := http.NewRequestWithContext(ctx, http.MethodGet, location, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		if accept != "" {
+			request.Header.Set("Accept", accept)
+		}
+
+		if authorization != "" {
+			request.Header.Set("Authorization", authorization)
+		}
+
+		return ociRegistryClient.Do(request)
+	}
+
+	response, err := get("")
+	if err != nil {
+		return nil, err
+	}
+
+	if response.StatusCode == http.StatusUnauthorized {
+		response.Body.Close()
+
+		challenge := response.Header.Get("WWW-Authenticate")
+		authorization := ""
+
+		if strings.HasPrefix(challenge, "Bearer ") {
+			token, err := fetchOCIRegistryToken(ctx, registry, challenge)
+			if err != nil {
+				return nil, err
+			}
+
+			authorization = "Bearer " + token
+		} else if credentials := ociRegistryCredentials(registry); credentials != "" {
+			authorization = "Basic " + credentials
+		}
+
+		if response, err = get(authorization); err != nil {
+			return nil, err
+		}
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch %s: %s", location, response.Status)
+	}
+
+	return ioutil.ReadAll(response.Body)
+}
+
+// resolveOCIChartTag returns the tag of the version of a chart a dependency's version refers to. As with helm, an exact
+// version is the chart's tag, with any + of its build metadata replaced by _, as tags can't contain a +, whereas the
+// repository's tags are listed to find the latest version satisfying a constraint, or the latest of all versions
+func resolveOCIChartTag(ctx context.Context, registry string, repository string, version string) (string, error) {
+	if _, err := semver.NewVersion(version); err == nil {
+		return strings.Replace(version, "+", "_", -1), nil
+	}
+
+	constraint, err := semver.NewConstraint("*")
+	if version != "" {
+		constraint, err = semver.NewConstraint(version)
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("invalid version %s: %s", version, err)
+	}
+
+	tagsContents, err := fetchOCIRegistryFile(ctx, registry, repository, "tags/list", "")
+	if err != nil {
+		return "", err
+	}
+
+	var tags struct {
+		Tags []string `json:"tags"`
+	}
+
+	if err := json.Unmarshal(tagsContents, &tags); err != nil {
+		return "", err
+	}
+
+	var latestVersion *semver.Version
+	latestTag := ""
+
+	for _, tag := range tags.Tags {
+		v, err := semver.NewVersion(strings.Replace(tag, "_", "+", -1))
+		if err != nil || !constraint.Check(v) {
+			continue
+		}
+
+		if latestVersion == nil || v.GreaterThan(latestVersion) {
+			latestVersion = v
+			latestTag = tag
+		}
+	}
+
+	if latestVersion == nil {
+		return "", fmt.Errorf("no version of %s/%s satisfies %s", registry, repository, version)
+	}
+
+	return latestTag, nil
+}
+
+// fetchOCIChartMeta reads the Chart.yaml of a dependency published to an OCI registry from the config of the chart's
+// manifest, which is all that's needed of the chart, fetching it with the credentials of helm registry login, if any
+func fetchOCIChartMeta(ctx context.Context, dependency ChartRequirementsItem) (ChartMeta, error) {
+	var chartMeta ChartMeta
+
+	chartReference := fmt.Sprintf("%s/%s", strings.TrimSuffix(strings.TrimPrefix(dependency.Repository, ociRepositoryScheme), "/"), dependency.Name)
+	referenceParts := strings.SplitN(chartReference, "/", 2)
+	if len(referenceParts) != 2 {
+		return chartMeta, fmt.Errorf("invalid chart reference %s%s", ociRepositoryScheme, chartReference)
+	}
+
+	registry, repository := referenceParts[0], referenceParts[1]
+
+	tag, err := resolveOCIChartTag(ctx, registry, repository, dependency.Version)
+	if err != nil {
+		return chartMeta, fmt.Errorf("failed to fetch chart %s%s: %s", ociRepositoryScheme, chartReference, err)
+	}
+
+	manifestContents, err := fetchOCIRegistryFile(ctx, registry, repository, "manifests/"+tag, ociManifestMediaType)
+	if err != nil {
+		return chartMeta, fmt.Errorf("failed to fetch chart %s%s: %s", ociRepositoryScheme, chartReference, err)
+	}
+
+	var manifest struct {
+		Config struct {
+			MediaType string `json:"mediaType"`
+			Digest    string `json:"digest"`
+		} `json:"config"`
+	}
+
+	if err := json.Unmarshal(manifestContents, &manifest); err != nil {
+		return chartMeta, fmt.Errorf("failed to fetch chart %s%s: %s", ociRepositoryScheme, chartReference, err)
+	}
+
+	if manifest.Config.MediaType != helmChartConfigMediaType {
+		return chartMeta, fmt.Errorf("%s%s:%s is not a helm chart", ociRepositoryScheme, chartReference, tag)
+	}
+
+	configContents, err := fetchOCIRegistryFile(ctx, registry, repository, "blobs/"+manifest.Config.Digest, "")
+	if err != nil {
+		return chartMeta, fmt.Errorf("failed to fetch chart %s%s: %s", ociRepositoryScheme, chartReference, err)
+	}
+
+	// The config is JSON, which parses as YAML all the same
+	err = yaml.Unmarshal(configContents, &chartMeta)
+	return chartMeta, err
+}
+
+// describeOCIDependencies fills in the descriptions and app versions of the dependencies published to OCI registries
+// from their Chart.yaml files. Registries may well be unreachable, e.g. when run offline, so dependencies that can't be
+// fetched are left undescribed with a warning rather than failing the chart's documentation
+func describeOCIDependencies(ctx context.Context, chartDirectory string, dependencies []ChartRequirementsItem) {
+	for i, d := range dependencies {
+		if !isOCIRepository(d.Repository) {
+			continue
+		}
+
+		chartMeta, err := fetchOCIChartMeta(ctx, d)
+		if err != nil {
+			log.Warnf("Error describing dependency %s of chart %s: %s", d.Name, chartDirectory, err)
+			continue
+		}
+
+		dependencies[i].Description = chartMeta.Description
+		dependencies[i].AppVersion = chartMeta.AppVersion
+	}
+}
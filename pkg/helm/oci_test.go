@@ -0,0 +1,87 @@
+package helm
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// A stand in for an OCI registry serving the redis chart, versions 17.0.0 and 17.1.0, to holders of a token
+func newTestOCIRegistry(t *testing.T) *httptest.Server {
+	var server *httptest.Server
+
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			assert.Equal(t, "repository:charts/redis:pull", r.URL.Query().Get("scope"))
+			fmt.Fprint(w, `{"token": "secret"}`)
+			return
+		}
+
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="registry",scope="repository:charts/redis:pull"`, server.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		switch r.URL.Path {
+		case "/v2/charts/redis/tags/list":
+			fmt.Fprint(w, `{"name": "charts/redis", "tags": ["17.0.0", "17.1.0", "latest"]}`)
+		case "/v2/charts/redis/manifests/17.0.0", "/v2/charts/redis/manifests/17.1.0":
+			assert.Equal(t, ociManifestMediaType, r.Header.Get("Accept"))
+			version := strings.TrimPrefix(r.URL.Path, "/v2/charts/redis/manifests/")
+			fmt.Fprintf(w, `{"schemaVersion": 2, "config": {"mediaType": "%s", "digest": "sha256:%s"}}`, helmChartConfigMediaType, version)
+		case "/v2/charts/redis/blobs/sha256:17.0.0":
+			fmt.Fprint(w, `{"name": "redis", "version": "17.0.0", "appVersion": "7.0.4", "description": "An in-memory store"}`)
+		case "/v2/charts/redis/blobs/sha256:17.1.0":
+			fmt.Fprint(w, `{"name": "redis", "version": "17.1.0", "appVersion": "7.0.5", "description": "An in-memory store"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+
+	return server
+}
+
+func TestDescribeOCIDependencies(t *testing.T) {
+	registry := newTestOCIRegistry(t)
+	defer registry.Close()
+
+	ociRegistryScheme = "http"
+	defer func() { ociRegistryScheme = "https" }()
+
+	registryHost := strings.TrimPrefix(registry.URL, "http://")
+	dependencies := []ChartRequirementsItem{
+		{Name: "redis", Version: "17.0.0", Repository: "oci://" + registryHost + "/charts/"},
+		{Name: "postgresql", Version: "1.0.0", Repository: "oci://" + registryHost + "/charts"},
+		{Name: "nginx", Version: "1.0.0", Repository: "https://charts.example.com"},
+		{Name: "redis", Version: "~17.0.0 || ^17.1.0", Repository: "oci://" + registryHost + "/charts"},
+	}
+
+	describeOCIDependencies(context.Background(), "app", dependencies)
+
+	assert.Equal(t, "An in-memory store", dependencies[0].Description)
+	assert.Equal(t, "7.0.4", dependencies[0].AppVersion)
+	assert.Equal(t, "", dependencies[1].Description)
+	assert.Equal(t, "", dependencies[2].Description)
+	assert.Equal(t, "7.0.5", dependencies[3].AppVersion)
+}
+
+func TestFetchOCIChartMetaCancelled(t *testing.T) {
+	registry := newTestOCIRegistry(t)
+	defer registry.Close()
+
+	ociRegistryScheme = "http"
+	defer func() { ociRegistryScheme = "https" }()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	dependency := ChartRequirementsItem{Name: "redis", Version: "17.0.0", Repository: "oci://" + strings.TrimPrefix(registry.URL, "http://") + "/charts"}
+	_, err := fetchOCIChartMeta(ctx, dependency)
+	assert.NotNil(t, err)
+}
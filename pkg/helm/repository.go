@@ -0,0 +1,227 @@
+package helm
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/semver"
+	"github.com/norwoodj/helm-docs/pkg/settings"
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v2"
+)
+
+// RepositoryChartVersion is a version of a chart listed in a helm repository's index.yaml
+type RepositoryChartVersion struct {
+	Name    string
+	Version string
+	URLs    []string `yaml:"urls"`
+}
+
+type repositoryIndex struct {
+	Entries map[string][]RepositoryChartVersion
+}
+
+// The client fetching files of helm repositories over http(s), which gives up on a repository that stops responding
+// rather than hanging the run
+var repositoryClient = &http.Client{Timeout: 5 * time.Minute}
+
+func isURL(location string) bool {
+	return strings.HasPrefix(location, "http://") || strings.HasPrefix(location, "https://")
+}
+
+// openRepositoryFile opens a file of a helm repository, its index or a chart archive, which may be on disk or be served
+// over http(s)
+func openRepositoryFile(ctx context.Context, location string) (io.ReadCloser, error) {
+	if !isURL(location) {
+		return os.Open(location)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, location, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := repositoryClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.StatusCode != http.StatusOK {
+		response.Body.Close()
+		return nil, fmt.Errorf("failed to fetch %s: %s", location, response.Status)
+	}
+
+	return response.Body, nil
+}
+
+func parseRepositoryIndex(ctx context.Context, indexLocation string) (repositoryIndex, error) {
+	var index repositoryIndex
+
+	indexFile, err := openRepositoryFile(ctx, indexLocation)
+	if err != nil {
+		return index, err
+	}
+
+	defer indexFile.Close()
+
+	indexContents, err := ioutil.ReadAll(indexFile)
+	if err != nil {
+		return index, err
+	}
+
+	if err := yaml.Unmarshal(indexContents, &index); err != nil {
+		return index, fmt.Errorf("failed to parse repository index %s: %s", indexLocation, err)
+	}
+
+	return index, nil
+}
+
+// latestChartVersion returns the latest version of a chart in a repository. As with helm, prereleases are only chosen if
+// there are no releases
+func latestChartVersion(versions []RepositoryChartVersion) (RepositoryChartVersion, bool) {
+	var latest RepositoryChartVersion
+	var latestSemver *semver.Version
+
+	for _, v := range versions {
+		s, err := semver.NewVersion(v.Version)
+		if err != nil {
+			log.Warnf("Ignoring version %s of chart %s, which is not a semantic version", v.Version, v.Name)
+			continue
+		}
+
+		isBetterRelease := latestSemver == nil ||
+			(latestSemver.Prerelease() != "" && s.Prerelease() == "") ||
+			((latestSemver.Prerelease() == "") == (s.Prerelease() == "") && s.GreaterThan(latestSemver))
+
+		if isBetterRelease {
+			latest = v
+			latestSemver = s
+		}
+	}
+
+	return latest, latestSemver != nil
+}
+
+// resolveChartURL resolves the url of a chart archive, which is relative to the repository's index if it isn't absolute
+func resolveChartURL(indexLocation string, chartURL string) (string, error) {
+	if isURL(chartURL) {
+		return chartURL, nil
+	}
+
+	if !isURL(indexLocation) {
+		if filepath.IsAbs(chartURL) {
+			return chartURL, nil
+		}
+
+		return filepath.Join(filepath.Dir(indexLocation), chartURL), nil
+	}
+
+	indexURL, err := url.Parse(indexLocation)
+	if err != nil {
+		return "", err
+	}
+
+	relativeURL, err := url.Parse(chartURL)
+	if err != nil {
+		return "", err
+	}
+
+	return indexURL.ResolveReference(relativeURL).String(), nil
+}
+
+func downloadChartArchive(ctx context.Context, chartURL string, archivePath string) error {
+	chartFile, err := openRepositoryFile(ctx, chartURL)
+	if err != nil {
+		return err
+	}
+
+	defer chartFile.Close()
+
+	archiveFile, err := os.Create(archivePath)
+	if err != nil {
+		return err
+	}
+
+	defer archiveFile.Close()
+
+	_, err = io.Copy(archiveFile, chartFile)
+	return err
+}
+
+// DownloadRepositoryCharts downloads the latest version of each chart listed in a helm repository's index.yaml, given as
+// a path or a url, to the output directory, returning the paths of the archives. Each archive is named after its chart
+// alone, e.g. nginx.tgz, so that the documentation generated from it goes in a directory named after the chart. Only the
+// charts named to document are downloaded, if any were given
+func DownloadRepositoryCharts(indexLocation string, outputDirectory string) ([]string, error) {
+	return DownloadRepositoryChartsContext(context.Background(), indexLocation, outputDirectory)
+}
+
+// DownloadRepositoryChartsContext downloads the charts of a helm repository like DownloadRepositoryCharts, giving up once
+// the context is done
+func DownloadRepositoryChartsContext(ctx context.Context, indexLocation string, outputDirectory string) ([]string, error) {
+	index, err := parseRepositoryIndex(ctx, indexLocation)
+	if err != nil {
+		return nil, err
+	}
+
+	chartNames := make([]string, 0, len(index.Entries))
+	for n := range index.Entries {
+		chartNames = append(chartNames, n)
+	}
+
+	sort.Strings(chartNames)
+
+	if err := os.MkdirAll(outputDirectory, 0755); err != nil {
+		return nil, err
+	}
+
+	includedChartNames := settings.GetStringSlice("chart")
+	archivePaths := make([]string, 0, len(chartNames))
+
+	for _, n := range chartNames {
+		if len(includedChartNames) > 0 && !containsString(includedChartNames, n) {
+			continue
+		}
+
+		latest, ok := latestChartVersion(index.Entries[n])
+		if !ok || len(latest.URLs) == 0 {
+			log.Warnf("Found no version of chart %s to download in repository index %s, skipping", n, indexLocation)
+			continue
+		}
+
+		chartURL, err := resolveChartURL(indexLocation, latest.URLs[0])
+		if err != nil {
+			return archivePaths, err
+		}
+
+		log.Infof("Downloading version %s of chart %s from %s", latest.Version, n, chartURL)
+		archivePath := filepath.Join(outputDirectory, fmt.Sprintf("%s.tgz", n))
+		if err := downloadChartArchive(ctx, chartURL, archivePath); err != nil {
+			os.Remove(archivePath)
+			return archivePaths, fmt.Errorf("failed to download chart %s: %s", n, err)
+		}
+
+		archivePaths = append(archivePaths, archivePath)
+	}
+
+	return archivePaths, nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,83 @@
+package helm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLatestChartVersion(t *testing.T) {
+	latest, ok := latestChartVersion([]RepositoryChartVersion{
+		{Name: "app", Version: "1.10.0-rc.1"},
+		{Name: "app", Version: "1.2.0"},
+		{Name: "app", Version: "1.9.1"},
+		{Name: "app", Version: "latest"},
+	})
+
+	assert.True(t, ok)
+	assert.Equal(t, "1.9.1", latest.Version)
+
+	latest, ok = latestChartVersion([]RepositoryChartVersion{{Name: "app", Version: "0.1.0-alpha"}})
+	assert.True(t, ok)
+	assert.Equal(t, "0.1.0-alpha", latest.Version)
+
+	_, ok = latestChartVersion([]RepositoryChartVersion{})
+	assert.False(t, ok)
+}
+
+func TestResolveChartURL(t *testing.T) {
+	for _, c := range []struct {
+		indexLocation string
+		chartURL      string
+		expected      string
+	}{
+		{"https://charts.example.com/index.yaml", "charts/app-1.0.0.tgz", "https://charts.example.com/charts/app-1.0.0.tgz"},
+		{"https://charts.example.com/stable/index.yaml", "/charts/app-1.0.0.tgz", "https://charts.example.com/charts/app-1.0.0.tgz"},
+		{"https://charts.example.com/index.yaml", "https://cdn.example.com/app-1.0.0.tgz", "https://cdn.example.com/app-1.0.0.tgz"},
+		{"repo/index.yaml", "app-1.0.0.tgz", "repo/app-1.0.0.tgz"},
+	} {
+		chartURL, err := resolveChartURL(c.indexLocation, c.chartURL)
+		assert.Nil(t, err)
+		assert.Equal(t, c.expected, chartURL)
+	}
+}
+
+func TestDownloadRepositoryChartsContext(t *testing.T) {
+	outputDirectory, err := ioutil.TempDir("", "helm-docs-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(outputDirectory)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/index.yaml":
+			fmt.Fprint(w, "entries:\n  app:\n    - name: app\n      version: 1.0.0\n      urls: [app-1.0.0.tgz]\n")
+		case "/app-1.0.0.tgz":
+			fmt.Fprint(w, "archive")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+
+	defer server.Close()
+
+	archivePaths, err := DownloadRepositoryChartsContext(context.Background(), server.URL+"/index.yaml", outputDirectory)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{path.Join(outputDirectory, "app.tgz")}, archivePaths)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = DownloadRepositoryChartsContext(ctx, server.URL+"/index.yaml", outputDirectory)
+	assert.True(t, errors.Is(err, context.Canceled))
+}
@@ -0,0 +1,228 @@
+package helm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/golang/protobuf/ptypes/any"
+	"gopkg.in/yaml.v2"
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/engine"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+	"k8s.io/helm/pkg/renderutil"
+	"k8s.io/helm/pkg/version"
+)
+
+// ChartResource describes a kubernetes resource installed by a chart with its default values
+type ChartResource struct {
+	APIVersion string
+	Kind       string
+	Name       string
+	Namespace  string
+}
+
+// The release name helm uses when rendering templates without one being given
+const renderedReleaseName = "release-name"
+
+// The template functions helm 3 adds to those of helm's v2 template engine, which charts written for helm 3 may well
+// use. Nothing is looked up in a cluster when rendering offline, so lookup finds nothing, as with helm template
+var helm3TemplateFunctions = map[string]interface{}{
+	"lookup": func(apiVersion, kind, namespace, name string) (map[string]interface{}, error) {
+		return map[string]interface{}{}, nil
+	},
+	"fromYamlArray": func(str string) []interface{} {
+		a := make([]interface{}, 0)
+		if err := yaml.Unmarshal([]byte(str), &a); err != nil {
+			a = []interface{}{err.Error()}
+		}
+
+		return a
+	},
+	"fromJsonArray": func(str string) []interface{} {
+		a := make([]interface{}, 0)
+		if err := json.Unmarshal([]byte(str), &a); err != nil {
+			a = []interface{}{err.Error()}
+		}
+
+		return a
+	},
+}
+
+// loadChart loads a chart directory or packaged chart with helm's own chart loader. Helm's v2 loader only knows of
+// dependencies declared in requirements.yaml, so those declared in the Chart.yaml of apiVersion v2 charts are handed to
+// it as a requirements.yaml, for their conditions and tags to be honoured when rendering
+func loadChart(chartDirectory string) (*chart.Chart, error) {
+	c, err := chartutil.Load(chartDirectory)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, f := range c.Files {
+		if f.TypeUrl == "requirements.yaml" {
+			return c, nil
+		}
+	}
+
+	files, err := openChartFiles(chartDirectory)
+	if err != nil {
+		return nil, err
+	}
+
+	chartYaml, err := files.readFile("Chart.yaml")
+	if err != nil {
+		return nil, err
+	}
+
+	var chartFile struct {
+		Dependencies []interface{} `yaml:"dependencies"`
+	}
+
+	if err := yaml.Unmarshal(chartYaml, &chartFile); err != nil {
+		return nil, err
+	}
+
+	if len(chartFile.Dependencies) == 0 {
+		return c, nil
+	}
+
+	requirementsYaml, err := yaml.Marshal(chartFile)
+	if err != nil {
+		return nil, err
+	}
+
+	c.Files = append(c.Files, &any.Any{TypeUrl: "requirements.yaml", Value: requirementsYaml})
+	return c, nil
+}
+
+// renderChartTemplates renders a chart's templates against its default values with helm's template engine, the way
+// helm template does, returning the rendered templates by their paths, e.g. nginx/templates/service.yaml. In lint mode,
+// as with helm lint, the chart's dependencies needn't have been fetched and values marked required may be missing
+func renderChartTemplates(ctx context.Context, chartDirectory string, lintMode bool) (map[string]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	c, err := loadChart(chartDirectory)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load chart %s: %s", chartDirectory, err)
+	}
+
+	if requirements, err := chartutil.LoadRequirements(c); err == nil && !lintMode {
+		if err := renderutil.CheckDependencies(c, requirements); err != nil {
+			return nil, fmt.Errorf("failed to render templates of chart %s: %s", chartDirectory, err)
+		}
+	} else if err != nil && err != chartutil.ErrRequirementsNotFound {
+		return nil, fmt.Errorf("failed to load dependencies of chart %s: %s", chartDirectory, err)
+	}
+
+	config := &chart.Config{Raw: "{}"}
+	if err := chartutil.ProcessRequirementsEnabled(c, config); err != nil {
+		return nil, fmt.Errorf("failed to render templates of chart %s: %s", chartDirectory, err)
+	}
+
+	if err := chartutil.ProcessRequirementsImportValues(c); err != nil {
+		return nil, fmt.Errorf("failed to render templates of chart %s: %s", chartDirectory, err)
+	}
+
+	capabilities := &chartutil.Capabilities{
+		APIVersions:   chartutil.DefaultVersionSet,
+		KubeVersion:   chartutil.DefaultKubeVersion,
+		TillerVersion: version.GetVersionProto(),
+	}
+
+	releaseOptions := chartutil.ReleaseOptions{Name: renderedReleaseName, Namespace: "default", IsInstall: true}
+	values, err := chartutil.ToRenderValuesCaps(c, config, releaseOptions, capabilities)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render templates of chart %s: %s", chartDirectory, err)
+	}
+
+	// Releases are installed by helm itself rather than tiller since helm 3
+	if release, ok := values["Release"].(map[string]interface{}); ok {
+		release["Service"] = "Helm"
+	}
+
+	renderer := engine.New()
+	renderer.LintMode = lintMode
+	for name, function := range helm3TemplateFunctions {
+		renderer.FuncMap[name] = function
+	}
+
+	rendered, err := renderer.Render(c, values)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render templates of chart %s: %s", chartDirectory, err)
+	}
+
+	return rendered, nil
+}
+
+// renderChartManifests renders a chart's templates and joins the manifests they render into one multi-document YAML
+// stream, in the format of helm template's output
+func renderChartManifests(ctx context.Context, chartDirectory string) ([]byte, error) {
+	rendered, err := renderChartTemplates(ctx, chartDirectory, false)
+	if err != nil {
+		return nil, err
+	}
+
+	templates := make([]string, 0, len(rendered))
+	for name := range rendered {
+		if path.Base(name) == "NOTES.txt" || strings.TrimSpace(rendered[name]) == "" {
+			continue
+		}
+
+		templates = append(templates, name)
+	}
+
+	sort.Strings(templates)
+
+	var manifests bytes.Buffer
+	for _, name := range templates {
+		fmt.Fprintf(&manifests, "---\n# Source: %s\n%s\n", name, strings.TrimSpace(rendered[name]))
+	}
+
+	return manifests.Bytes(), nil
+}
+
+func parseChartResources(manifests []byte) ([]ChartResource, error) {
+	resources := make([]ChartResource, 0)
+	decoder := yaml.NewDecoder(bytes.NewReader(manifests))
+
+	for {
+		var manifest struct {
+			APIVersion string `yaml:"apiVersion"`
+			Kind       string
+			Metadata   struct {
+				Name      string
+				Namespace string
+			}
+		}
+
+		err := decoder.Decode(&manifest)
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		// Templates which render nothing still leave an empty document behind
+		if manifest.Kind == "" {
+			continue
+		}
+
+		resources = append(resources, ChartResource{
+			APIVersion: manifest.APIVersion,
+			Kind:       manifest.Kind,
+			Name:       manifest.Metadata.Name,
+			Namespace:  manifest.Metadata.Namespace,
+		})
+	}
+
+	return resources, nil
+}
@@ -0,0 +1,93 @@
+package helm
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseChartResources(t *testing.T) {
+	resources, err := parseChartResources([]byte(`---
+# Source: nginx/templates/service.yaml
+apiVersion: v1
+kind: Service
+metadata:
+  name: release-name-nginx
+---
+# Source: nginx/templates/ingress.yaml
+---
+# Source: nginx/templates/deployment.yaml
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: release-name-nginx
+  namespace: web
+`))
+
+	assert.Nil(t, err)
+	assert.Equal(t, []ChartResource{
+		{APIVersion: "v1", Kind: "Service", Name: "release-name-nginx"},
+		{APIVersion: "apps/v1", Kind: "Deployment", Name: "release-name-nginx", Namespace: "web"},
+	}, resources)
+}
+
+func TestRenderChartManifests(t *testing.T) {
+	chartDirectory, err := ioutil.TempDir("", "helm-docs-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(chartDirectory)
+
+	files := map[string]string{
+		"Chart.yaml": `apiVersion: v2
+name: app
+version: 1.0.0
+dependencies:
+  - name: cache
+    version: 1.0.0
+    condition: cache.enabled
+`,
+		"values.yaml":                   "replicas: 2\ncache:\n  enabled: false\n",
+		"templates/_helpers.tpl":        `{{ define "app.name" }}{{ .Release.Name }}-{{ .Chart.Name }}{{ end }}`,
+		"templates/NOTES.txt":           "Installed {{ .Release.Name }}",
+		"templates/empty.yaml":          "{{- if .Values.missing }}\nkind: Secret\n{{- end }}\n",
+		"templates/deployment.yaml":     "kind: Deployment\nmetadata:\n  name: {{ include \"app.name\" . }}\n  labels:\n    managed-by: {{ .Release.Service }}\nspec:\n  replicas: {{ .Values.replicas }}\n",
+		"templates/secret.yaml":         "{{- $existing := lookup \"v1\" \"Secret\" .Release.Namespace \"app\" }}\nkind: Secret\nmetadata:\n  name: app\n  namespace: {{ .Release.Namespace }}\n",
+		"charts/cache/Chart.yaml":       "apiVersion: v2\nname: cache\nversion: 1.0.0\n",
+		"charts/cache/templates/a.yaml": "kind: StatefulSet\n",
+	}
+
+	for name, contents := range files {
+		assert.Nil(t, os.MkdirAll(path.Dir(path.Join(chartDirectory, name)), 0755))
+		assert.Nil(t, ioutil.WriteFile(path.Join(chartDirectory, name), []byte(contents), 0644))
+	}
+
+	manifests, err := renderChartManifests(context.Background(), chartDirectory)
+	assert.Nil(t, err)
+	assert.Equal(t, `---
+# Source: app/templates/deployment.yaml
+kind: Deployment
+metadata:
+  name: release-name-app
+  labels:
+    managed-by: Helm
+spec:
+  replicas: 2
+---
+# Source: app/templates/secret.yaml
+kind: Secret
+metadata:
+  name: app
+  namespace: default
+`, string(manifests))
+
+	// As with helm template, dependencies must have been fetched into the charts/ directory to render the chart
+	assert.Nil(t, os.RemoveAll(path.Join(chartDirectory, "charts")))
+	_, err = renderChartManifests(context.Background(), chartDirectory)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,93 @@
+package helm
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// The file helm validates a chart's values against when it's installed
+const valuesSchemaFile = "values.schema.json"
+
+// ChartSchemaViolation is a way in which a chart's default values don't match its values schema, for the value with the
+// given key, or the values as a whole for the key (root)
+type ChartSchemaViolation struct {
+	Key         string
+	Description string
+}
+
+// ChartSchemaValidation is the result of validating a chart's default values against its values schema. The schema file
+// is empty for charts without one
+type ChartSchemaValidation struct {
+	SchemaFile string
+	Violations []ChartSchemaViolation
+}
+
+// jsonCompatibleValue converts values parsed from yaml into those that can be encoded as json, whose objects must have
+// string keys
+func jsonCompatibleValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		converted := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			converted[fmt.Sprintf("%v", key)] = jsonCompatibleValue(item)
+		}
+
+		return converted
+
+	case []interface{}:
+		converted := make([]interface{}, len(v))
+		for i, item := range v {
+			converted[i] = jsonCompatibleValue(item)
+		}
+
+		return converted
+
+	default:
+		return v
+	}
+}
+
+func validateValuesSchema(schema []byte, values map[interface{}]interface{}) ([]ChartSchemaViolation, error) {
+	result, err := gojsonschema.Validate(gojsonschema.NewBytesLoader(schema), gojsonschema.NewGoLoader(jsonCompatibleValue(values)))
+	if err != nil {
+		return nil, err
+	}
+
+	violations := make([]ChartSchemaViolation, 0, len(result.Errors()))
+	for _, e := range result.Errors() {
+		violations = append(violations, ChartSchemaViolation{Key: e.Field(), Description: e.Description()})
+	}
+
+	// The order in which the schema's properties are validated isn't fixed
+	sort.Slice(violations, func(i, j int) bool {
+		if violations[i].Key != violations[j].Key {
+			return violations[i].Key < violations[j].Key
+		}
+
+		return violations[i].Description < violations[j].Description
+	})
+
+	return violations, nil
+}
+
+// parseChartSchemaValidation validates a chart's default values against its values.schema.json, if it has one
+func parseChartSchemaValidation(files chartFiles, values map[interface{}]interface{}) (ChartSchemaValidation, error) {
+	schema, err := files.readFile(valuesSchemaFile)
+	if os.IsNotExist(err) {
+		return ChartSchemaValidation{}, nil
+	}
+
+	if err != nil {
+		return ChartSchemaValidation{}, err
+	}
+
+	violations, err := validateValuesSchema(schema, values)
+	if err != nil {
+		return ChartSchemaValidation{}, fmt.Errorf("failed to validate values against %s: %s", files.path(valuesSchemaFile), err)
+	}
+
+	return ChartSchemaValidation{SchemaFile: valuesSchemaFile, Violations: violations}, nil
+}
@@ -0,0 +1,46 @@
+package helm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateValuesSchema(t *testing.T) {
+	schema := []byte(`{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "type": "object",
+  "required": ["image"],
+  "properties": {
+    "image": {
+      "type": "object",
+      "properties": {
+        "tag": {"type": "string"}
+      }
+    },
+    "replicas": {"type": "integer", "minimum": 1}
+  }
+}`)
+
+	violations, err := validateValuesSchema(schema, map[interface{}]interface{}{
+		"image":    map[interface{}]interface{}{"tag": "1.0"},
+		"replicas": 1,
+	})
+
+	assert.Nil(t, err)
+	assert.Empty(t, violations)
+
+	violations, err = validateValuesSchema(schema, map[interface{}]interface{}{
+		"image":    map[interface{}]interface{}{"tag": 1.0},
+		"replicas": 0,
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, []ChartSchemaViolation{
+		{Key: "image.tag", Description: "Invalid type. Expected: string, given: integer"},
+		{Key: "replicas", Description: "Must be greater than or equal to 1"},
+	}, violations)
+
+	_, err = validateValuesSchema([]byte("{"), map[interface{}]interface{}{})
+	assert.NotNil(t, err)
+}
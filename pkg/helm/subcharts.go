@@ -0,0 +1,124 @@
+package helm
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// ChartSubchart is a chart unpacked in the charts/ directory of a parent chart, or of one of its subcharts in turn
+type ChartSubchart struct {
+	ChartMeta
+	ChartValuesFile
+
+	// The subchart's directory relative to the parent chart's, e.g. charts/app/charts/redis
+	Path string
+
+	// The key path under which the subchart's values are nested in the parent chart's values, e.g. app.redis, made up of
+	// the aliases of the subchart and its parents where they're given in their parents' dependencies
+	ValuesPrefix string
+}
+
+// subchartValuesNames finds the names under which the values of a subchart are nested in its parent's values, those of
+// the aliases of the dependencies on it, if any, or otherwise its name
+func subchartValuesNames(chartName string, dependencies []ChartRequirementsItem) []string {
+	names := make([]string, 0)
+	for _, d := range dependencies {
+		if d.Name == chartName && d.Alias != "" {
+			names = append(names, d.Alias)
+		}
+	}
+
+	if len(names) == 0 {
+		names = append(names, chartName)
+	}
+
+	return names
+}
+
+func readSubchartValuesFile(chartSettings *viper.Viper, files chartFiles) (ChartValuesFile, error) {
+	valuesFileContents, err := files.readFile("values.yaml")
+	if err != nil && !os.IsNotExist(err) {
+		return ChartValuesFile{}, err
+	}
+
+	return parseValuesFileContents(chartSettings, valuesFileContents)
+}
+
+// findSubchartNames finds the names of the subcharts unpacked in a chart's charts/ directory, those of the directories
+// in it with a Chart.yaml
+func findSubchartNames(files chartFiles) ([]string, error) {
+	chartsFiles, err := files.listFiles("charts")
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0)
+	for _, f := range chartsFiles {
+		if parts := strings.Split(f, "/"); len(parts) == 3 && parts[2] == "Chart.yaml" {
+			names = append(names, parts[1])
+		}
+	}
+
+	return names, nil
+}
+
+func findSubcharts(chartSettings *viper.Viper, files chartFiles, relativePath string, valuesPrefix string) ([]ChartSubchart, error) {
+	chartMeta, err := parseChartMetaFile(files)
+	if err != nil {
+		return nil, err
+	}
+
+	requirements, err := parseChartRequirementsFile(chartSettings, files, chartMeta)
+	if err != nil {
+		return nil, err
+	}
+
+	subchartNames, err := findSubchartNames(files)
+	if err != nil {
+		return nil, err
+	}
+
+	subcharts := make([]ChartSubchart, 0)
+	for _, n := range subchartNames {
+		subchartFiles := files.sub(path.Join("charts", n))
+		subchartMeta, err := parseChartMetaFile(subchartFiles)
+		if err != nil {
+			return nil, err
+		}
+
+		valuesFile, err := readSubchartValuesFile(chartSettings, subchartFiles)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse values of subchart %s: %s", subchartFiles.path(""), err)
+		}
+
+		subchartPath := path.Join(relativePath, "charts", n)
+		for _, name := range subchartValuesNames(subchartMeta.Name, requirements.Dependencies) {
+			subchartValuesPrefix := FormatObjectKeyPath(valuesPrefix, name)
+			subcharts = append(subcharts, ChartSubchart{
+				ChartMeta:       subchartMeta,
+				ChartValuesFile: valuesFile,
+				Path:            subchartPath,
+				ValuesPrefix:    subchartValuesPrefix,
+			})
+
+			nestedSubcharts, err := findSubcharts(chartSettings, subchartFiles, subchartPath, subchartValuesPrefix)
+			if err != nil {
+				return nil, err
+			}
+
+			subcharts = append(subcharts, nestedSubcharts...)
+		}
+	}
+
+	return subcharts, nil
+}
+
+// parseChartSubcharts finds the subcharts unpacked in a chart's charts/ directory, recursively, each followed by its own
+// subcharts. Packaged subcharts aren't included, being neither documented themselves nor local to the chart
+func parseChartSubcharts(chartSettings *viper.Viper, files chartFiles) ([]ChartSubchart, error) {
+	return findSubcharts(chartSettings, files, "", "")
+}
@@ -0,0 +1,51 @@
+package helm
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseChartSubcharts(t *testing.T) {
+	chartDirectory, err := ioutil.TempDir("", "helm-docs-test")
+	assert.Nil(t, err)
+	defer os.RemoveAll(chartDirectory)
+
+	files := map[string]string{
+		"Chart.yaml":                          "apiVersion: v2\nname: parent\nversion: 1.0.0\ndependencies:\n  - name: app\n    alias: frontend\n",
+		"charts/app/Chart.yaml":               "apiVersion: v2\nname: app\nversion: 0.1.0\n",
+		"charts/app/values.yaml":              "# image.tag -- Image tag\nimage:\n  tag: latest\n",
+		"charts/app/charts/redis/Chart.yaml":  "apiVersion: v2\nname: redis\nversion: 7.0.0\n",
+		"charts/app/charts/redis/values.yaml": "port: 6379\n",
+		"charts/lib/Chart.yaml":               "apiVersion: v2\nname: lib\ntype: library\nversion: 1.0.0\n",
+		"charts/not-a-chart/README.md":        "",
+		"charts/packaged-0.1.0.tgz":           "",
+	}
+
+	for name, contents := range files {
+		assert.Nil(t, os.MkdirAll(path.Dir(path.Join(chartDirectory, name)), 0755))
+		assert.Nil(t, ioutil.WriteFile(path.Join(chartDirectory, name), []byte(contents), 0644))
+	}
+
+	subcharts, err := parseChartSubcharts(viper.GetViper(), directoryChartFiles(chartDirectory))
+	assert.Nil(t, err)
+	assert.Len(t, subcharts, 3)
+
+	assert.Equal(t, "app", subcharts[0].Name)
+	assert.Equal(t, "charts/app", subcharts[0].Path)
+	assert.Equal(t, "frontend", subcharts[0].ValuesPrefix)
+	assert.Equal(t, "Image tag", subcharts[0].Descriptions["image.tag"].Description)
+
+	assert.Equal(t, "redis", subcharts[1].Name)
+	assert.Equal(t, "charts/app/charts/redis", subcharts[1].Path)
+	assert.Equal(t, "frontend.redis", subcharts[1].ValuesPrefix)
+	assert.Equal(t, map[interface{}]interface{}{"port": 6379}, subcharts[1].Values)
+
+	assert.Equal(t, "lib", subcharts[2].Name)
+	assert.Equal(t, "lib", subcharts[2].ValuesPrefix)
+	assert.Empty(t, subcharts[2].Values)
+}
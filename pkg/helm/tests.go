@@ -0,0 +1,93 @@
+package helm
+
+import (
+	"path"
+	"regexp"
+	"strings"
+)
+
+// ChartTest describes a resource of a chart run by helm test, i.e. one annotated with the helm.sh/hook: test annotation
+type ChartTest struct {
+	Name string
+	Kind string
+	File string
+}
+
+var (
+	manifestSeparatorRegex = regexp.MustCompile(`(?m)^---.*$`)
+	manifestKindRegex      = regexp.MustCompile(`(?m)^kind:[ \t]*(\S+)`)
+	manifestNameRegex      = regexp.MustCompile(`(?m)^metadata:[ \t]*\n(?:[ \t]+.*\n)*?[ \t]+name:[ \t]*(.+)$`)
+	testHookRegex          = regexp.MustCompile(`(?m)^[ \t]+["']?helm\.sh/hook["']?:[ \t]*["']?([^"'\n]*)`)
+)
+
+// isTestHook returns whether a helm.sh/hook annotation's comma separated hooks include a test hook. Helm 2 charts use the
+// test-success and test-failure hooks, which helm 3 still runs
+func isTestHook(hooks string) bool {
+	for _, h := range strings.Split(hooks, ",") {
+		switch strings.TrimSpace(h) {
+		case "test", "test-success", "test-failure":
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseTemplateTests finds the tests in a template file. Templates can't be parsed as yaml before they're rendered, so
+// the kind, name and hook annotation of each document are matched line by line, with any template actions in the name
+// stripped, leaving the static part of it, e.g. test-connection for {{ include "fullname" . }}-test-connection
+func parseTemplateTests(templateFile string, contents []byte) []ChartTest {
+	tests := make([]ChartTest, 0)
+
+	for _, document := range manifestSeparatorRegex.Split(string(contents), -1) {
+		hook := testHookRegex.FindStringSubmatch(document)
+		if len(hook) < 2 || !isTestHook(hook[1]) {
+			continue
+		}
+
+		test := ChartTest{File: templateFile}
+		if kind := manifestKindRegex.FindStringSubmatch(document); len(kind) > 1 {
+			test.Kind = kind[1]
+		}
+
+		if name := manifestNameRegex.FindStringSubmatch(document); len(name) > 1 {
+			test.Name = strings.Trim(stripTemplateActions(name[1]), " \t\"'-")
+		}
+
+		if test.Name == "" {
+			test.Name = strings.TrimSuffix(path.Base(templateFile), path.Ext(templateFile))
+		}
+
+		tests = append(tests, test)
+	}
+
+	return tests
+}
+
+func isManifestTemplate(templateFile string) bool {
+	return strings.HasSuffix(templateFile, ".yaml") || strings.HasSuffix(templateFile, ".yml")
+}
+
+// parseChartTests finds the tests among the templates of a chart, which are conventionally kept in templates/tests/
+func parseChartTests(files chartFiles) ([]ChartTest, error) {
+	templateFiles, err := files.listFiles("templates")
+	if err != nil {
+		return nil, err
+	}
+
+	tests := make([]ChartTest, 0)
+	for _, f := range templateFiles {
+		if !isManifestTemplate(f) {
+			continue
+		}
+
+		contents, err := files.readFile(f)
+		if err != nil {
+			return nil, err
+		}
+
+		tests = append(tests, parseTemplateTests(f, contents)...)
+	}
+
+	return tests, nil
+}
@@ -0,0 +1,58 @@
+package helm
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTemplateTests(t *testing.T) {
+	tests := parseTemplateTests("templates/tests/test-connection.yaml", []byte(`apiVersion: v1
+kind: Pod
+metadata:
+  name: "{{ include "nginx.fullname" . }}-test-connection"
+  labels:
+    {{- include "nginx.labels" . | nindent 4 }}
+  annotations:
+    "helm.sh/hook": test
+spec:
+  containers:
+    - name: wget
+      image: busybox
+      command: ['wget']
+      args: ['{{ include "nginx.fullname" . }}:{{ .Values.service.port }}']
+  restartPolicy: Never
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: {{ .Release.Name }}
+---
+apiVersion: batch/v1
+kind: Job
+metadata:
+  annotations:
+    helm.sh/hook: test-success, post-install
+spec: {}
+`))
+
+	assert.Equal(t, []ChartTest{
+		{Name: "test-connection", Kind: "Pod", File: "templates/tests/test-connection.yaml"},
+		{Name: "test-connection", Kind: "Job", File: "templates/tests/test-connection.yaml"},
+	}, tests)
+}
+
+func TestParseChartTestsWithoutTemplates(t *testing.T) {
+	chartDirectory, err := ioutil.TempDir("", "helm-docs-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(chartDirectory)
+
+	tests, err := parseChartTests(directoryChartFiles(chartDirectory))
+	assert.Nil(t, err)
+	assert.Empty(t, tests)
+}
@@ -0,0 +1,34 @@
+package helm
+
+const (
+	BoolValueType   = "bool"
+	FloatValueType  = "float"
+	IntValueType    = "int"
+	ListValueType   = "list"
+	NullValueType   = "null"
+	ObjectValueType = "object"
+	StringValueType = "string"
+)
+
+// InferValueType returns the name of the type of a value parsed from a chart's values file, based on the go type it was
+// decoded to
+func InferValueType(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return NullValueType
+	case bool:
+		return BoolValueType
+	case float32, float64:
+		return FloatValueType
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return IntValueType
+	case string:
+		return StringValueType
+	case []interface{}:
+		return ListValueType
+	case map[interface{}]interface{}, map[string]interface{}:
+		return ObjectValueType
+	}
+
+	return ""
+}
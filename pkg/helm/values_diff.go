@@ -0,0 +1,174 @@
+package helm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v2"
+)
+
+// ChartValuesChange is a default value added, removed or changed since the previous version of a chart. The previous
+// and current defaults are json encoded, and empty for added and removed values respectively
+type ChartValuesChange struct {
+	Key      string
+	Change   string
+	Previous string
+	Current  string
+}
+
+// ChartValuesDiff lists the changes to the default values of a chart since the previous version it's compared with. The
+// previous version is empty for charts which weren't compared with one
+type ChartValuesDiff struct {
+	PreviousVersion string
+	Changes         []ChartValuesChange
+}
+
+// The default shown in place of those of sensitive values, as in the values table
+const redactedValue = "<redacted>"
+
+// flattenValues maps the key path of each leaf of a values tree to its value. Lists and empty maps are leaves, so that a
+// changed list is a single change rather than one for each of its items. As in the values table, ignored keys are left
+// out along with everything under them
+func flattenValues(prefix string, values map[interface{}]interface{}, descriptions map[string]ChartValueDescription, leaves map[string]interface{}) {
+	for k, v := range values {
+		key := FormatObjectKeyPath(prefix, fmt.Sprintf("%v", k))
+		if descriptions[key].Ignored {
+			continue
+		}
+
+		if nested, ok := v.(map[interface{}]interface{}); ok && len(nested) > 0 {
+			flattenValues(key, nested, descriptions, leaves)
+			continue
+		}
+
+		leaves[key] = v
+	}
+}
+
+// isSensitiveKey returns whether a value is sensitive, or is under a sensitive value, e.g. a field of a secret's map
+func isSensitiveKey(key string, descriptions map[string]ChartValueDescription) bool {
+	for k, d := range descriptions {
+		if d.Sensitive && (key == k || strings.HasPrefix(key, k+".")) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func jsonEncodeValue(value interface{}) string {
+	encoded, err := json.Marshal(jsonCompatibleValue(value))
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+
+	return string(encoded)
+}
+
+// diffValues compares the leaves of two values trees, returning the changes sorted by key. The defaults of sensitive
+// values are redacted, and ignored values left out, according to the current values' descriptions
+func diffValues(previous map[interface{}]interface{}, current map[interface{}]interface{}, descriptions map[string]ChartValueDescription) []ChartValuesChange {
+	previousLeaves := make(map[string]interface{})
+	currentLeaves := make(map[string]interface{})
+	flattenValues("", previous, descriptions, previousLeaves)
+	flattenValues("", current, descriptions, currentLeaves)
+
+	changes := make([]ChartValuesChange, 0)
+	for key, currentValue := range currentLeaves {
+		previousValue, ok := previousLeaves[key]
+		if !ok {
+			changes = append(changes, ChartValuesChange{Key: key, Change: "added", Current: jsonEncodeValue(currentValue)})
+		} else if !reflect.DeepEqual(previousValue, currentValue) {
+			changes = append(changes, ChartValuesChange{
+				Key:      key,
+				Change:   "changed",
+				Previous: jsonEncodeValue(previousValue),
+				Current:  jsonEncodeValue(currentValue),
+			})
+		}
+	}
+
+	for key, previousValue := range previousLeaves {
+		if _, ok := currentLeaves[key]; !ok {
+			changes = append(changes, ChartValuesChange{Key: key, Change: "removed", Previous: jsonEncodeValue(previousValue)})
+		}
+	}
+
+	for i, c := range changes {
+		if !isSensitiveKey(c.Key, descriptions) {
+			continue
+		}
+
+		if c.Previous != "" {
+			changes[i].Previous = redactedValue
+		}
+
+		if c.Current != "" {
+			changes[i].Current = redactedValue
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Key < changes[j].Key
+	})
+
+	return changes
+}
+
+// readPreviousChartFiles reads the Chart.yaml and values.yaml of the previous version of a chart, from a packaged chart
+// or otherwise from the chart's directory as of a git ref, e.g. a release tag
+func readPreviousChartFiles(ctx context.Context, chartDirectory string, previous string) ([]byte, []byte, error) {
+	if IsChartArchive(previous) {
+		files, err := readChartArchive(previous)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return files["Chart.yaml"], files["values.yaml"], nil
+	}
+
+	chartFile, err := runGit(ctx, chartDirectory, "show", fmt.Sprintf("%s:./Chart.yaml", previous))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// A chart may well have had no values file in its previous version
+	valuesFile, err := runGit(ctx, chartDirectory, "show", fmt.Sprintf("%s:./values.yaml", previous))
+	if err != nil {
+		log.Debugf("Found no values file of chart %s as of %s, comparing with empty values: %s", chartDirectory, previous, err)
+	}
+
+	return []byte(chartFile), []byte(valuesFile), nil
+}
+
+// parseChartValuesDiff compares the default values of a chart with those of its previous version, given as a git ref or
+// a packaged chart. Charts whose previous version can't be read, e.g. because they didn't exist yet, aren't compared
+func parseChartValuesDiff(ctx context.Context, chartDirectory string, previous string, values map[interface{}]interface{}, descriptions map[string]ChartValueDescription) (ChartValuesDiff, error) {
+	chartFile, valuesFile, err := readPreviousChartFiles(ctx, chartDirectory, previous)
+	if err != nil {
+		log.Warnf("Error reading the previous version %s of chart %s, not comparing its values: %s", previous, chartDirectory, err)
+		return ChartValuesDiff{}, nil
+	}
+
+	var previousChartMeta ChartMeta
+	if err := yaml.Unmarshal(chartFile, &previousChartMeta); err != nil {
+		return ChartValuesDiff{}, fmt.Errorf("failed to parse Chart.yaml of the previous version %s of chart %s: %s", previous, chartDirectory, err)
+	}
+
+	previousValues, err := parseValues(valuesFile)
+	if err != nil {
+		return ChartValuesDiff{}, fmt.Errorf("failed to parse values.yaml of the previous version %s of chart %s: %s", previous, chartDirectory, err)
+	}
+
+	previousVersion := previousChartMeta.Version
+	if previousVersion == "" {
+		previousVersion = previous
+	}
+
+	return ChartValuesDiff{PreviousVersion: previousVersion, Changes: diffValues(previousValues, values, descriptions)}, nil
+}
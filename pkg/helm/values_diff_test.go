@@ -0,0 +1,88 @@
+package helm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v2"
+)
+
+func parseYamlValuesForTest(t *testing.T, values string) map[interface{}]interface{} {
+	parsed := make(map[interface{}]interface{})
+	if err := yaml.Unmarshal([]byte(values), &parsed); err != nil {
+		t.Fatal(err)
+	}
+
+	return parsed
+}
+
+func TestDiffValues(t *testing.T) {
+	previous := parseYamlValuesForTest(t, `
+image:
+  repository: nginx
+  tag: "1.19"
+replicas: 1
+ingress:
+  hosts: [a.example.com]
+legacy: true
+`)
+
+	current := parseYamlValuesForTest(t, `
+image:
+  repository: nginx
+  tag: "1.21"
+replicas: 1
+ingress:
+  hosts: [a.example.com, b.example.com]
+resources: {}
+`)
+
+	assert.Equal(t, []ChartValuesChange{
+		{Key: "image.tag", Change: "changed", Previous: `"1.19"`, Current: `"1.21"`},
+		{Key: "ingress.hosts", Change: "changed", Previous: `["a.example.com"]`, Current: `["a.example.com","b.example.com"]`},
+		{Key: "legacy", Change: "removed", Previous: "true"},
+		{Key: "resources", Change: "added", Current: "{}"},
+	}, diffValues(previous, current, map[string]ChartValueDescription{}))
+}
+
+func TestDiffValuesSensitiveAndIgnored(t *testing.T) {
+	previous := parseYamlValuesForTest(t, `
+auth:
+  password: hunter2
+  username: admin
+database:
+  credentials:
+    token: abc
+internal:
+  debug: false
+legacy: true
+`)
+
+	current := parseYamlValuesForTest(t, `
+auth:
+  password: correcthorse
+  username: root
+database:
+  credentials:
+    token: def
+    secret: ghi
+internal:
+  debug: true
+  trace: true
+`)
+
+	descriptions := map[string]ChartValueDescription{
+		"auth.password":        {Sensitive: true},
+		"database.credentials": {Sensitive: true},
+		"internal":             {Ignored: true},
+		"legacy":               {Sensitive: true},
+	}
+
+	assert.Equal(t, []ChartValuesChange{
+		{Key: "auth.password", Change: "changed", Previous: "<redacted>", Current: "<redacted>"},
+		{Key: "auth.username", Change: "changed", Previous: `"admin"`, Current: `"root"`},
+		{Key: "database.credentials.secret", Change: "added", Current: "<redacted>"},
+		{Key: "database.credentials.token", Change: "changed", Previous: "<redacted>", Current: "<redacted>"},
+		{Key: "legacy", Change: "removed", Previous: "<redacted>"},
+	}, diffValues(previous, current, descriptions))
+}
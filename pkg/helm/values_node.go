@@ -0,0 +1,273 @@
+package helm
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// valuesEntry is either a comment block in a values file, or a value defined in it, in the order they appear. A comment
+// block is the head, foot or line comment the yaml parser attached to a node, split at any blank lines in it. The
+// description comment parser works through these rather than the raw lines of the file, so that which value a comment
+// belongs to is decided by the yaml parser rather than by indentation, and flow style collections, block scalars and
+// anchors are read as yaml rather than line by line
+type valuesEntry struct {
+	comment       []string
+	isKey         bool
+	keyPath       string
+	lineNumber    int
+	inlineComment string
+}
+
+// parseValuesNode parses the node tree of a values file, which keeps its comments, key order and line numbers
+func parseValuesNode(valuesFileContents []byte) (*yaml.Node, error) {
+	var document yaml.Node
+	if err := yaml.Unmarshal(valuesFileContents, &document); err != nil {
+		return nil, err
+	}
+
+	return &document, nil
+}
+
+// yaml11Bools are the plain scalars that yaml 1.1, which helm reads values files as, resolves to booleans, but yaml 1.2
+// resolves to strings
+var yaml11Bools = map[string]bool{
+	"y": true, "Y": true, "yes": true, "Yes": true, "YES": true, "on": true, "On": true, "ON": true,
+	"n": false, "N": false, "no": false, "No": false, "NO": false, "off": false, "Off": false, "OFF": false,
+}
+
+// nodeValue converts a node to the value it holds, in the same shapes as values decoded by yaml.v2 elsewhere: maps are
+// map[interface{}]interface{}, and scalars are resolved as yaml 1.1
+func nodeValue(node *yaml.Node) (interface{}, error) {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			return nil, nil
+		}
+
+		return nodeValue(node.Content[0])
+	case yaml.AliasNode:
+		return nodeValue(node.Alias)
+	case yaml.SequenceNode:
+		values := make([]interface{}, 0, len(node.Content))
+		for _, n := range node.Content {
+			v, err := nodeValue(n)
+			if err != nil {
+				return nil, err
+			}
+
+			values = append(values, v)
+		}
+
+		return values, nil
+	case yaml.MappingNode:
+		return mappingNodeValue(node)
+	}
+
+	if b, ok := yaml11Bools[node.Value]; ok && node.Style == 0 && node.Tag == "!!str" {
+		return b, nil
+	}
+
+	// Timestamps are kept as written, as helm doesn't parse them either
+	if node.Tag == "!!timestamp" {
+		return node.Value, nil
+	}
+
+	var value interface{}
+	if err := node.Decode(&value); err != nil {
+		return nil, err
+	}
+
+	return value, nil
+}
+
+// mappingNodeValue converts a mapping node to a map. The values of maps merged in with merge keys are overridden by
+// those of the keys of the mapping itself, wherever they're defined in it
+func mappingNodeValue(node *yaml.Node) (map[interface{}]interface{}, error) {
+	values := make(map[interface{}]interface{})
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		keyNode, valueNode := node.Content[i], node.Content[i+1]
+		if keyNode.Tag != "!!merge" {
+			continue
+		}
+
+		merged := []*yaml.Node{valueNode}
+		if valueNode.Kind == yaml.SequenceNode {
+			merged = valueNode.Content
+		}
+
+		for _, n := range merged {
+			v, err := nodeValue(n)
+			if err != nil {
+				return nil, err
+			}
+
+			m, ok := v.(map[interface{}]interface{})
+			if !ok {
+				return nil, fmt.Errorf("line %d: map merge requires map or sequence of maps as the value", valueNode.Line)
+			}
+
+			for k, v := range m {
+				values[k] = v
+			}
+		}
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		keyNode, valueNode := node.Content[i], node.Content[i+1]
+		if keyNode.Tag == "!!merge" {
+			continue
+		}
+
+		if keyNode.Kind != yaml.ScalarNode {
+			return nil, fmt.Errorf("line %d: map keys must be scalars", keyNode.Line)
+		}
+
+		k, err := nodeValue(keyNode)
+		if err != nil {
+			return nil, err
+		}
+
+		v, err := nodeValue(valueNode)
+		if err != nil {
+			return nil, err
+		}
+
+		values[k] = v
+	}
+
+	return values, nil
+}
+
+// valuesFromNode converts the node tree of a values file to its values. An empty file has no values
+func valuesFromNode(document *yaml.Node) (map[interface{}]interface{}, error) {
+	v, err := nodeValue(document)
+	if err != nil || v == nil {
+		return make(map[interface{}]interface{}), err
+	}
+
+	values, ok := v.(map[interface{}]interface{})
+	if !ok {
+		return make(map[interface{}]interface{}), fmt.Errorf("line %d: values must be a map", document.Content[0].Line)
+	}
+
+	return values, nil
+}
+
+// parseValues parses the values of a values file
+func parseValues(valuesFileContents []byte) (map[interface{}]interface{}, error) {
+	document, err := parseValuesNode(valuesFileContents)
+	if err != nil {
+		return make(map[interface{}]interface{}), err
+	}
+
+	return valuesFromNode(document)
+}
+
+func inlineCommentText(nodes ...*yaml.Node) string {
+	for _, n := range nodes {
+		if n.LineComment != "" {
+			return strings.TrimSpace(strings.TrimPrefix(n.LineComment, "#"))
+		}
+	}
+
+	return ""
+}
+
+type valuesEntryCollector struct {
+	entries []valuesEntry
+}
+
+// addComment adds the blocks of a comment attached to a node
+func (c *valuesEntryCollector) addComment(comment string) {
+	block := make([]string, 0)
+
+	for _, l := range strings.Split(comment, "\n") {
+		if strings.TrimSpace(l) != "" {
+			block = append(block, l)
+			continue
+		}
+
+		if len(block) > 0 {
+			c.entries = append(c.entries, valuesEntry{comment: block})
+			block = make([]string, 0)
+		}
+	}
+
+	if len(block) > 0 {
+		c.entries = append(c.entries, valuesEntry{comment: block})
+	}
+}
+
+func (c *valuesEntryCollector) addKey(keyPath string, lineNumber int, inlineComment string) {
+	c.entries = append(c.entries, valuesEntry{isKey: true, keyPath: keyPath, lineNumber: lineNumber, inlineComment: inlineComment})
+}
+
+// addValue adds the value at a key path, preceded by the comments above it and followed by the values nested in it and
+// the comments below it. Aliases aren't followed, as the values they refer to are defined, and documented, at their
+// anchors
+func (c *valuesEntryCollector) addValue(keyPath string, keyNode *yaml.Node, valueNode *yaml.Node) {
+	c.addComment(keyNode.HeadComment)
+	if keyNode != valueNode {
+		c.addComment(valueNode.HeadComment)
+	}
+
+	c.addKey(keyPath, keyNode.Line, inlineCommentText(valueNode, keyNode))
+	c.addChildren(keyPath, valueNode)
+
+	c.addComment(keyNode.FootComment)
+	if keyNode != valueNode {
+		c.addComment(valueNode.FootComment)
+	}
+}
+
+func (c *valuesEntryCollector) addChildren(keyPath string, node *yaml.Node) {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		c.addComment(node.HeadComment)
+		for _, n := range node.Content {
+			c.addComment(n.HeadComment)
+			c.addChildren(keyPath, n)
+			c.addComment(n.FootComment)
+		}
+		c.addComment(node.FootComment)
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			keyNode, valueNode := node.Content[i], node.Content[i+1]
+
+			// Merge keys pull in the values of an anchored map, which are documented where they're defined
+			if keyNode.Tag == "!!merge" || keyNode.Value == "<<" {
+				c.addComment(keyNode.HeadComment)
+				c.addComment(keyNode.FootComment)
+				continue
+			}
+
+			c.addValue(FormatObjectKeyPath(keyPath, keyNode.Value), keyNode, valueNode)
+		}
+	case yaml.SequenceNode:
+		for i, n := range node.Content {
+			c.addValue(FormatListKeyPath(keyPath, i), n, n)
+		}
+	}
+}
+
+// valuesEntriesFromNode lists the comment blocks and values of the node tree of a values file in the order they appear
+// in it
+func valuesEntriesFromNode(document *yaml.Node) []valuesEntry {
+	collector := valuesEntryCollector{entries: make([]valuesEntry, 0)}
+	collector.addChildren("", document)
+
+	return collector.entries
+}
+
+// parseValuesEntries lists the comment blocks and values of a values file in the order they appear in it
+func parseValuesEntries(valuesFileContents []byte) ([]valuesEntry, error) {
+	document, err := parseValuesNode(valuesFileContents)
+	if err != nil {
+		return nil, err
+	}
+
+	return valuesEntriesFromNode(document), nil
+}
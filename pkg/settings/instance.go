@@ -0,0 +1,39 @@
+package settings
+
+import (
+	"context"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// The key under which a context carries the viper instance settings are read from
+type contextKey struct{}
+
+// NewContext returns a context carrying a viper instance from which the charts parsed with it read their settings, e.g.
+// one holding the options of a program embedding helm-docs, rather than from viper's global instance
+func NewContext(ctx context.Context, v *viper.Viper) context.Context {
+	return context.WithValue(ctx, contextKey{}, v)
+}
+
+// FromContext returns the viper instance settings are read from within a context, viper's global instance, holding the
+// options of the helm-docs command, unless the context carries another
+func FromContext(ctx context.Context) *viper.Viper {
+	if v, ok := ctx.Value(contextKey{}).(*viper.Viper); ok && v != nil {
+		return v
+	}
+
+	return viper.GetViper()
+}
+
+// The settings of the helm-docs command, read by the code only run by the command, e.g. the search for charts. What's
+// run for each chart reads the settings the chart was parsed with instead
+func Get(key string) interface{}           { return viper.Get(key) }
+func GetBool(key string) bool              { return viper.GetBool(key) }
+func GetDuration(key string) time.Duration { return viper.GetDuration(key) }
+func GetFloat64(key string) float64        { return viper.GetFloat64(key) }
+func GetInt(key string) int                { return viper.GetInt(key) }
+func GetString(key string) string          { return viper.GetString(key) }
+func GetStringSlice(key string) []string   { return viper.GetStringSlice(key) }
+func IsSet(key string) bool                { return viper.IsSet(key) }
+func AllSettings() map[string]interface{}  { return viper.AllSettings() }
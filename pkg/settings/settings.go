@@ -0,0 +1,143 @@
+// Package settings holds the options of helm-docs, from which both the flags of the helm-docs command and the defaults
+// of documentation generated through the docs package are built, and through which the packages generating
+// documentation read them.
+package settings
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/pflag"
+)
+
+// Setting is an option of helm-docs, given on the command line as a flag of its name
+type Setting struct {
+	Name      string
+	Shorthand string
+
+	// The value of the setting unless given, whose type is that of the setting's flag
+	Default interface{}
+	Usage   string
+}
+
+// Settings are all of the options of helm-docs, in the order they're listed in the command's help
+var Settings = []Setting{
+	{Name: "badge-base-url", Default: "https://img.shields.io", Usage: "base url of the shields.io compatible server rendering badges, e.g. a self hosted one for air-gapped environments"},
+	{Name: "badge-color", Default: "informational", Usage: "color of the badges rendered by the badge templates"},
+	{Name: "badge-style", Default: "flat-square", Usage: "style of the badges rendered by the badge templates, one of shields.io's styles (flat, flat-square, plastic, for-the-badge, social)"},
+	{Name: "bitnami-params", Default: false, Usage: "parse bitnami style \"## @param key description\" comments in values files as value descriptions"},
+	{Name: "cache-file", Default: "", Usage: "file recording what each chart's documentation was generated from, so that unchanged charts are skipped on later runs, e.g. \".helm-docs-cache.json\""},
+	{Name: "chart", Default: []string{}, Usage: "names of the only charts, as given in their Chart.yaml, to generate documentation for, may be given multiple times or as a comma separated list"},
+	{Name: "chart-archive", Default: []string{}, Usage: "packaged charts (.tgz), or directories of them, to generate documentation for rather than searching for chart directories, may be given multiple times or as a comma separated list"},
+	{Name: "chart-repository-name", Default: "", Usage: "name of the helm repository the charts are published to, used in installation instructions; defaults to the chart's name"},
+	{Name: "chart-repository-url", Default: "", Usage: "url of the helm repository the charts are published to, used in installation instructions"},
+	{Name: "chart-search-root", Default: []string{"."}, Usage: "directories to search recursively for charts, may be given multiple times or as a comma separated list"},
+	{Name: "check", Default: false, Usage: "don't write any markdown files, instead print a diff of those that are out of date and exit with a non-zero code if there are any"},
+	{Name: "collapse-defaults-length", Default: 0, Usage: "render default values longer than this many characters in an expandable details element, 0 to disable"},
+	{Name: "comment-prefix", Default: "#", Usage: "regular expression matching the prefix of comments documenting values in values files"},
+	{Name: "commented-out-values", Default: false, Usage: "document commented out keys following a description comment in values files, e.g. \"# storageClass: fast\", as values unset by default"},
+	{Name: "compare-values-to", Default: "", Usage: "git ref, e.g. a release tag, or packaged chart (.tgz) of the previous version of each chart, whose default values are compared with the current ones for the chart.valuesDiffSection template"},
+	{Name: "config-file", Shorthand: "c", Default: "", Usage: "yaml file setting any of these options, with keys named after them; defaults to .helm-docs.yaml if present"},
+	{Name: "coverage-threshold", Default: float64(0), Usage: "fail if the percentage of values with descriptions in any chart is below this threshold, 0 to disable"},
+	{Name: "default-format", Default: "json", Usage: "format in which default values are rendered in values tables, one of (json, yaml)"},
+	{Name: "description-separator", Default: "--", Usage: "regular expression matching the separator between the key and description in comments documenting values"},
+	{Name: "document-dependency-values", Default: false, Usage: "document the values of dependencies found in each chart's charts/ directory or local file:// repositories"},
+	{Name: "document-subcharts", Default: "", Usage: "document the subcharts unpacked in each chart's charts/ directory, recursively, one of (embed, link) to embed their values tables in the chart's documentation or link to their own documentation"},
+	{Name: "dry-run", Shorthand: "d", Default: false, Usage: "don't actually render any markdown files just print to stdout passed"},
+	{Name: "exclude", Default: []string{}, Usage: "glob patterns, in which ** matches any number of directories, of chart directories not to generate documentation for, e.g. \"**/examples/**\""},
+	{Name: "extra-values", Default: "", Usage: "yaml file whose contents are exposed to templates under .Extra, e.g. for variables used in shared templates"},
+	{Name: "extra-values-files", Default: []string{}, Usage: "values files, in addition to values.yaml, relative to each chart directory for which values tables will be generated"},
+	{Name: "fail-on-error", Default: false, Usage: "exit with a non-zero code if the documentation of any chart couldn't be generated, e.g. because its files couldn't be parsed"},
+	{Name: "fail-on-lint-errors", Default: false, Usage: "fail if linting reports errors for any chart, when run with --helm-lint"},
+	{Name: "fail-on-missing-descriptions", Default: false, Usage: "fail if any value in any chart has no description, equivalent to a coverage threshold of 100"},
+	{Name: "fail-on-schema-violation", Default: false, Usage: "fail if the default values of any chart aren't valid against its values.schema.json"},
+	{Name: "fetch-oci-dependencies", Default: false, Usage: "fetch the Chart.yaml of dependencies in oci:// repositories from their registries, to document their app versions and descriptions in the requirements table"},
+	{Name: "generate-values-schema", Default: false, Usage: "generate the values.schema.json of each chart from its default values and their descriptions, with the @allowed values of each as its enum, alongside its documentation"},
+	{Name: "generated-date", Default: "", Usage: "date stated by the chart.generatedFooter template, defaults to the current date. Pin it for reproducible output"},
+	{Name: "git-changelog", Default: false, Usage: "build a changelog of each chart from the git log of its directory, grouped by the commits bumping its version, for the chart.changelogSection template"},
+	{Name: "git-metadata", Default: false, Usage: "expose the date and hash of the last commit changing each chart, and the number of people who changed it, to templates as .LastCommitDate, .LastCommitSHA and .ContributorCount, from git"},
+	{Name: "github-maintainers", Default: false, Usage: "link maintainers whose url or email is of a GitHub account to their profiles, with their avatars, in the chart.maintainersTable template, looking up the accounts of other emails with the GitHub API"},
+	{Name: "heading-offset", Default: 0, Usage: "number of levels by which to shift the headings of the built in templates, e.g. 1 to nest the documentation under an existing top level heading"},
+	{Name: "helm-lint", Default: false, Usage: "lint each chart with the rules of helm lint, to document the result in the chart.lintSection template"},
+	{Name: "ignore-file", Shorthand: "i", Default: ".helmdocsignore", Usage: "The filename to use as an ignore file to exclude chart directories"},
+	{Name: "image-repository-key", Default: "repository", Usage: "key of the image repository in the maps of values configuring images, used to document images when resources aren't rendered"},
+	{Name: "image-tag-key", Default: "tag", Usage: "key of the image tag in the maps of values configuring images, used to document images when resources aren't rendered"},
+	{Name: "include", Default: []string{}, Usage: "glob patterns, in which ** matches any number of directories, of the only chart directories to generate documentation for, e.g. \"charts/app-*\""},
+	{Name: "index-file", Default: "", Usage: "markdown file path, relative to the directory helm-docs is run from, to which an index of all of the charts documented will be written, e.g. \"README.md\""},
+	{Name: "index-template-file", Default: "index.md.gotmpl", Usage: "gotemplate file path, relative to the directory helm-docs is run from, from which the index of charts will be generated"},
+	{Name: "inject", Default: false, Usage: "only replace the content between <!-- helm-docs:start --> and <!-- helm-docs:end --> markers in existing output files, preserving the rest"},
+	{Name: "inline-comments", Default: false, Usage: "use comments on the same line as a value in values files as the value's description, if it has no description comment"},
+	// The most recent minor Kubernetes releases at the time of writing
+	{Name: "kube-versions", Default: []string{"1.29", "1.30", "1.31", "1.32", "1.33", "1.34"}, Usage: "minor Kubernetes versions checked against the kubeVersion constraint of each chart, to document the versions it supports in the chart.kubeVersionsTable template"},
+	{Name: "link-dependencies", Default: false, Usage: "link the names of dependencies on charts also found in the chart search roots to the documentation of those charts in requirements tables"},
+	{Name: "link-values-to-source", Default: false, Usage: "link the keys in values tables to the lines defining them in the chart's values files on its git host, at the url given by --source-base-url or derived from the chart's github or gitlab sources"},
+	{Name: "locale", Default: "en", Usage: "locale in which the headings of built in templates are written, one of (en, de, fr, ja)"},
+	{Name: "log-format", Default: "text", Usage: "format of logs, one of (text, json)"},
+	{Name: "log-level", Shorthand: "l", Default: "info", Usage: fmt.Sprintf("Level of logs that should printed, one of (%s)", strings.Join(possibleLogLevels(), ", "))},
+	{Name: "omit-generated-date", Default: false, Usage: "don't state the date documentation was generated on in the chart.generatedFooter template"},
+	{Name: "output-file", Shorthand: "o", Default: "README.md", Usage: "markdown file path relative to each chart directory to which rendered documentation will be written"},
+	{Name: "output-format", Default: "markdown", Usage: "format in which documentation is rendered, one of the renderers registered; output files named .md take the format's extension instead"},
+	{Name: "quiet", Shorthand: "q", Default: false, Usage: "don't periodically log how many of the charts found have been documented so far"},
+	{Name: "render-resources", Default: false, Usage: "render each chart's templates against its default values with helm template, to document the kubernetes resources it installs"},
+	{Name: "report-file", Default: "", Usage: "json file to write a summary of the run to, listing the charts processed, skipped, failed and found out of date, rather than logging it"},
+	{Name: "repository-index", Default: "", Usage: "path or url of a helm repository's index.yaml, the latest version of each chart in which is downloaded and documented in the repository output directory"},
+	{Name: "repository-output-dir", Default: "docs", Usage: "directory to which the documentation of the charts in the --repository-index is written, in a directory per chart"},
+	{Name: "set", Default: []string{}, Usage: "key=value pairs, with dotted keys for nested values, exposed to templates under .Extra, overriding those from --extra-values"},
+	{Name: "shared-templates-dir", Default: "", Usage: "directory of .gotmpl files whose defines are parsed before each chart's template file, in addition to ./_templates.gotmpl"},
+	{Name: "skip-sections", Default: []string{}, Usage: "built in sections to render empty, named after their templates, e.g. \"values,requirements\" for chart.valuesSection and chart.requirementsSection"},
+	{Name: "sort-dependencies-order", Default: "alphanum", Usage: "order in which to sort the requirements table, one of (alphanum, file)"},
+	{Name: "sort-values-order", Default: "alphanum", Usage: "order in which to sort the values table, one of (alphanum, file)"},
+	{Name: "source-base-url", Default: "", Usage: "url under which the files of the git repository containing the charts are browsed at a ref, e.g. \"https://github.com/org/charts/blob/main\", to which each chart's path is appended when linking values to their source"},
+	{Name: "strict", Default: false, Usage: "fail if a template references keys that don't exist, or documentation can't be rendered for any chart"},
+	{Name: "strip-notes-template-actions", Default: false, Usage: "strip go template actions from the chart's templates/NOTES.txt when rendering it in documentation"},
+	{Name: "template-delims", Default: "", Usage: "left and right delimiters of actions in template files separated by a space, e.g. \"[[ ]]\", so that templates can contain helm's {{ }} syntax literally"},
+	{Name: "template-file", Shorthand: "t", Default: []string{"README.md.gotmpl"}, Usage: "gotemplate file paths, relative to each chart directory unless absolute, from which documentation will be generated. May be given several times, with later files overriding the defines of earlier ones"},
+	{Name: "template-output", Default: []string{}, Usage: "template file to render into an output file of its own, as template-file=output-file with paths relative to each chart directory, may be given multiple times in place of --template-file and --output-file"},
+	{Name: "timeout", Default: time.Duration(0), Usage: "how long to spend documenting each chart, e.g. \"2m\", before giving up on it and failing the run, 0 for no limit"},
+	{Name: "values-metadata-file", Default: "values.metadata.yaml", Usage: "yaml file path relative to each chart directory from which descriptions of values are read, in addition to values.yaml comments"},
+	{Name: "watch", Shorthand: "w", Default: false, Usage: "after generating documentation, keep watching chart directories and regenerate the documentation of charts whose yaml or template files, or shared template files, change"},
+}
+
+func possibleLogLevels() []string {
+	levels := make([]string, 0)
+
+	for _, l := range log.AllLevels {
+		levels = append(levels, l.String())
+	}
+
+	return levels
+}
+
+// AddFlags adds a flag for each of the settings to a flag set
+func AddFlags(flags *pflag.FlagSet) {
+	for _, s := range Settings {
+		switch defaultValue := s.Default.(type) {
+		case bool:
+			flags.BoolP(s.Name, s.Shorthand, defaultValue, s.Usage)
+		case int:
+			flags.IntP(s.Name, s.Shorthand, defaultValue, s.Usage)
+		case float64:
+			flags.Float64P(s.Name, s.Shorthand, defaultValue, s.Usage)
+		case string:
+			flags.StringP(s.Name, s.Shorthand, defaultValue, s.Usage)
+		case []string:
+			flags.StringSliceP(s.Name, s.Shorthand, defaultValue, s.Usage)
+		case time.Duration:
+			flags.DurationP(s.Name, s.Shorthand, defaultValue, s.Usage)
+		default:
+			panic(fmt.Sprintf("setting %s has a default of unsupported type %T", s.Name, s.Default))
+		}
+	}
+}
+
+// Defaults returns the default value of each of the settings, by name
+func Defaults() map[string]interface{} {
+	defaults := make(map[string]interface{}, len(Settings))
+	for _, s := range Settings {
+		defaults[s.Name] = s.Default
+	}
+
+	return defaults
+}
@@ -0,0 +1,41 @@
+package settings
+
+import (
+	"context"
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddFlags(t *testing.T) {
+	flags := pflag.NewFlagSet("helm-docs", pflag.ContinueOnError)
+	AddFlags(flags)
+
+	defaults := Defaults()
+	assert.Len(t, defaults, len(Settings))
+
+	for name := range defaults {
+		assert.NotNil(t, flags.Lookup(name), name)
+	}
+
+	v := viper.New()
+	assert.Nil(t, v.BindPFlags(flags))
+	assert.Equal(t, defaults["kube-versions"], v.GetStringSlice("kube-versions"))
+	assert.Equal(t, defaults["link-dependencies"], v.GetBool("link-dependencies"))
+
+	assert.Equal(t, "template-file", flags.ShorthandLookup("t").Name)
+	assert.Nil(t, flags.Parse([]string{"--sort-values-order", "file", "-t", "a.gotmpl,b.gotmpl", "--timeout", "2m"}))
+	assert.Equal(t, "file", v.GetString("sort-values-order"))
+	assert.Equal(t, []string{"a.gotmpl", "b.gotmpl"}, v.GetStringSlice("template-file"))
+	assert.Equal(t, "2m0s", v.GetDuration("timeout").String())
+}
+
+func TestFromContext(t *testing.T) {
+	v := viper.New()
+	v.Set("locale", "de")
+
+	assert.Equal(t, "de", FromContext(NewContext(context.Background(), v)).GetString("locale"))
+	assert.Equal(t, viper.GetViper(), FromContext(context.Background()))
+}
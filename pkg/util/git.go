@@ -6,7 +6,15 @@ import (
 )
 
 func FindGitRepositoryRoot() (string, error) {
-	path, err := exec.Command("git", "rev-parse", "--show-toplevel").Output()
+	return FindGitRepositoryRootOf("")
+}
+
+// FindGitRepositoryRootOf finds the root of the git repository a directory is in, or that of the working directory if
+// the directory is ""
+func FindGitRepositoryRootOf(directory string) (string, error) {
+	command := exec.Command("git", "rev-parse", "--show-toplevel")
+	command.Dir = directory
+	path, err := command.Output()
 
 	if err != nil {
 		return "", err
@@ -0,0 +1,50 @@
+package util
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// globToRegexp translates a glob pattern to a regular expression. As well as the * and ? wildcards, which don't match
+// path separators, the pattern may contain ** to match any number of directories
+func globToRegexp(pattern string) *regexp.Regexp {
+	expression := strings.Builder{}
+	expression.WriteString("^")
+
+	for i := 0; i < len(pattern); i++ {
+		switch {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			expression.WriteString("(.*/)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			expression.WriteString(".*")
+			i++
+		case pattern[i] == '*':
+			expression.WriteString("[^/]*")
+		case pattern[i] == '?':
+			expression.WriteString("[^/]")
+		default:
+			expression.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+
+	expression.WriteString("$")
+	return regexp.MustCompile(expression.String())
+}
+
+// MatchGlob returns whether a path matches a glob pattern, see globToRegexp
+func MatchGlob(pattern string, path string) bool {
+	return globToRegexp(filepath.ToSlash(filepath.Clean(pattern))).MatchString(filepath.ToSlash(filepath.Clean(path)))
+}
+
+// MatchAnyGlob returns whether a path matches any of a number of glob patterns
+func MatchAnyGlob(patterns []string, path string) bool {
+	for _, p := range patterns {
+		if MatchGlob(p, path) {
+			return true
+		}
+	}
+
+	return false
+}
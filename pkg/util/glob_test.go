@@ -0,0 +1,21 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchGlob(t *testing.T) {
+	assert.True(t, MatchGlob("charts/app-*", "charts/app-frontend"))
+	assert.True(t, MatchGlob("./charts/app-*", "charts/app-frontend"))
+	assert.False(t, MatchGlob("charts/app-*", "charts/app-frontend/charts/db"))
+	assert.False(t, MatchGlob("charts/app-*", "charts/backend"))
+
+	assert.True(t, MatchGlob("**/examples/**", "examples/basic"))
+	assert.True(t, MatchGlob("**/examples/**", "charts/app/examples/basic"))
+	assert.False(t, MatchGlob("**/examples/**", "charts/app"))
+
+	assert.True(t, MatchGlob("charts/?pp", "charts/app"))
+	assert.False(t, MatchGlob("charts/a.p", "charts/app"))
+}
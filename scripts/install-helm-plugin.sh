@@ -0,0 +1,20 @@
+#!/usr/bin/env bash
+#
+# Installs the helm-docs binary for use as a helm plugin, run by helm from the plugin's directory when the plugin is
+# installed or updated. The binary is built from the plugin's source, so that the plugin runs the version of helm-docs
+# it was installed from rather than some release of another, and so go is required
+
+set -e
+
+if ! command -v go > /dev/null 2>&1; then
+    echo "go is required to build the helm-docs plugin from source, install go and then the plugin again" >&2
+    exit 1
+fi
+
+mkdir -p bin
+
+echo "Building helm-docs from source"
+version="$(git describe --tags --always --dirty 2> /dev/null | sed 's/^v//')"
+commit="$(git rev-parse HEAD 2> /dev/null)"
+date="$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+go build -ldflags "-X main.version=${version} -X main.commit=${commit} -X main.date=${date}" -o bin/helm-docs ./cmd/helm-docs